@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// float32Bytes is the storage size of one embedding component.
+const float32Bytes = 4
+
+// adminTokenHeader carries the shared admin secret configured via ADMIN_TOKEN.
+const adminTokenHeader = "X-Admin-Token"
+
+// RequireAdmin rejects requests that do not present the configured admin token.
+// An empty token disables the admin routes entirely.
+func RequireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin endpoints are disabled"})
+			return
+		}
+		presented := c.GetHeader(adminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// EmbeddingDimensionsHandler godoc
+//
+//	@Summary		Histogram of stored embedding dimensions
+//	@Description	Counts files by vector_dims(embedding) so operators can find rows embedded with the wrong dimension. mixed is true when more than one dimension is present.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string								true	"Admin token"
+//	@Success		200				{object}	models.EmbeddingDimensionsResponse	"Dimension histogram"
+//	@Failure		401				{object}	map[string]interface{}				"Invalid admin token"
+//	@Failure		403				{object}	map[string]interface{}				"Admin endpoints disabled"
+//	@Failure		500				{object}	map[string]interface{}				"Failed to count dimensions"
+//	@Router			/admin/embedding-dimensions [get]
+func EmbeddingDimensionsHandler(q *db.Queries, expectedDim int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rows, err := q.GetEmbeddingDimensionCounts(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to count embedding dimensions"})
+			return
+		}
+
+		counts := make([]models.EmbeddingDimensionCount, len(rows))
+		for i, row := range rows {
+			counts[i] = models.EmbeddingDimensionCount{
+				Dimension: int(row.Dimension),
+				Count:     row.Count,
+			}
+		}
+
+		writeJSON(c, http.StatusOK, models.EmbeddingDimensionsResponse{
+			ExpectedDimension: expectedDim,
+			Dimensions:        counts,
+			Mixed:             len(counts) > 1,
+		})
+	}
+}
+
+// SchemaHandler godoc
+//
+//	@Summary		Describe the files table
+//	@Description	Returns the introspected columns and types of the files table, the declared embedding dimension (-1 when the column has none), and the indexes that exist, to diagnose slow search or failing uploads without database access.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string					true	"Admin token"
+//	@Success		200				{object}	models.SchemaResponse	"Table schema"
+//	@Failure		401				{object}	map[string]interface{}	"Invalid admin token"
+//	@Failure		403				{object}	map[string]interface{}	"Admin endpoints disabled"
+//	@Failure		500				{object}	map[string]interface{}	"Failed to read schema"
+//	@Router			/admin/schema [get]
+func SchemaHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		columns, err := q.TableColumns(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to read columns"})
+			return
+		}
+		dim, err := q.EmbeddingColumnDim(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to read embedding dimension"})
+			return
+		}
+		indexes, err := q.TableIndexes(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to read indexes"})
+			return
+		}
+
+		resp := models.SchemaResponse{
+			Table:              "files",
+			Columns:            make([]models.ColumnSchema, len(columns)),
+			EmbeddingDimension: dim,
+			Indexes:            make([]models.IndexSchema, len(indexes)),
+		}
+		for i, col := range columns {
+			resp.Columns[i] = models.ColumnSchema{Name: col.Name, Type: col.Type, Nullable: col.Nullable}
+		}
+		for i, idx := range indexes {
+			resp.Indexes[i] = models.IndexSchema{Name: idx.Name, Definition: idx.Definition}
+		}
+
+		writeJSON(c, http.StatusOK, resp)
+	}
+}
+
+// StorageHandler godoc
+//
+//	@Summary		Report storage used by files
+//	@Description	Returns the raw embedding payload (sum of dimensions × 4 bytes), the content payload in bytes, and the table's total size including indexes and TOAST from pg_total_relation_size.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string					true	"Admin token"
+//	@Success		200				{object}	models.StorageResponse	"Storage footprint"
+//	@Failure		401				{object}	map[string]interface{}	"Invalid admin token"
+//	@Failure		403				{object}	map[string]interface{}	"Admin endpoints disabled"
+//	@Failure		500				{object}	map[string]interface{}	"Failed to read storage stats"
+//	@Router			/admin/storage [get]
+func StorageHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := q.GetStorageStats(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to read storage stats"})
+			return
+		}
+
+		writeJSON(c, http.StatusOK, models.StorageResponse{
+			Files:          stats.RowCount,
+			EmbeddingBytes: stats.EmbeddingValues * float32Bytes,
+			ContentBytes:   stats.ContentBytes,
+			TotalBytes:     stats.TotalBytes,
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/auth"
+	"github.com/fain17/rag-backend/db"
+)
+
+// AuditFileIDKey is the gin context key a handler sets to the ID of a file it
+// created, for routes without an :id parameter.
+const AuditFileIDKey = "audit_file_id"
+
+// AuditFileIDsKey is the gin context key a handler sets to the IDs of every
+// file it changed, for routes that act on many files at once. Each ID gets its
+// own audit entry.
+const AuditFileIDsKey = "audit_file_ids"
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditMiddleware records who called a mutating route, which file it targeted,
+// and the status it finished with in audit_log. The file is the route's :id,
+// or AuditFileIDKey when the handler set it; a handler that sets
+// AuditFileIDsKey gets one entry per file. A handler panic is recorded as a
+// 500 before the panic continues to the recovery middleware. Recording happens
+// after the handler runs, so a failure to write an entry is logged and does
+// not change the response.
+func AuditMiddleware(q *db.Queries, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		record := func(status int) {
+			entry := db.CreateAuditEntryParams{
+				Action: c.Request.Method + " " + c.FullPath(),
+				Status: int32(status),
+			}
+			if user := auth.UserID(c); user != "" {
+				entry.UserID = pgtype.Text{String: user, Valid: true}
+			}
+			if id := c.GetString(RequestIDKey); id != "" {
+				entry.RequestID = pgtype.Text{String: id, Valid: true}
+			}
+
+			fileIDs := c.GetStringSlice(AuditFileIDsKey)
+			if len(fileIDs) == 0 {
+				fileID := c.GetString(AuditFileIDKey)
+				if fileID == "" {
+					fileID = c.Param("id")
+				}
+				fileIDs = []string{fileID}
+			}
+
+			// Entries are written even if the client has already gone away.
+			ctx := context.WithoutCancel(c.Request.Context())
+			for _, fileID := range fileIDs {
+				entry.FileID = pgtype.UUID{}
+				if parsed, err := uuid.Parse(fileID); err == nil {
+					entry.FileID = pgtype.UUID{Bytes: parsed, Valid: true}
+				}
+				if err := q.CreateAuditEntry(ctx, entry); err != nil {
+					logger.Error("audit entry not recorded",
+						slog.String("action", entry.Action),
+						slog.String("file_id", fileID),
+						slog.String("request_id", entry.RequestID.String),
+						slog.String("error", err.Error()),
+					)
+				}
+			}
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				record(http.StatusInternalServerError)
+				panic(r)
+			}
+		}()
+		c.Next()
+		record(c.Writer.Status())
+	}
+}
+
+// ListAuditHandler godoc
+//
+//	@Summary		List audit log entries
+//	@Description	Returns recorded mutating operations on files, newest first, optionally for one file. Entries with a status of 400 or above are failed attempts.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string					true	"Admin token"
+//	@Param			file_id			query		string					false	"Only entries for this file UUID"
+//	@Param			limit			query		int						false	"Number of entries (1-1000, default 100)"
+//	@Success		200				{array}		models.AuditEntry		"Audit entries"
+//	@Failure		400				{object}	map[string]interface{}	"Invalid file_id or limit"
+//	@Failure		401				{object}	map[string]interface{}	"Invalid admin token"
+//	@Failure		403				{object}	map[string]interface{}	"Admin endpoints disabled"
+//	@Failure		500				{object}	map[string]interface{}	"Failed to list audit entries"
+//	@Router			/audit [get]
+func ListAuditHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params db.ListAuditEntriesParams
+		if raw := c.Query("file_id"); raw != "" {
+			parsed, err := uuid.Parse(raw)
+			if err != nil {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid file_id"})
+				return
+			}
+			params.FileID = pgtype.UUID{Bytes: parsed, Valid: true}
+		}
+
+		params.RowLimit = defaultAuditLimit
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxAuditLimit {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+				return
+			}
+			params.RowLimit = int32(n)
+		}
+
+		rows, err := q.ListAuditEntries(c, params)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to list audit entries"})
+			return
+		}
+
+		entries := make([]models.AuditEntry, len(rows))
+		for i, row := range rows {
+			entries[i] = models.AuditEntry{
+				ID:        row.ID,
+				UserID:    row.UserID.String,
+				Action:    row.Action,
+				Status:    int(row.Status),
+				Success:   row.Status < http.StatusBadRequest,
+				RequestID: row.RequestID.String,
+				CreatedAt: row.CreatedAt.Time,
+			}
+			if row.FileID.Valid {
+				entries[i].FileID = uuid.UUID(row.FileID.Bytes).String()
+			}
+		}
+
+		writeJSON(c, http.StatusOK, entries)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodyBytes rejects request bodies larger than maxBytes with 413. A declared
+// Content-Length over the limit is refused without reading; otherwise the body
+// is read up front through http.MaxBytesReader, so chunked bodies are capped
+// too and no handler ever sees a truncated body it might report as a 400.
+// maxBytes 0 disables the limit.
+func MaxBodyBytes(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes == 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
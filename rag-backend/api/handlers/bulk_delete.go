@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// maxBulkDelete caps the number of IDs per bulk delete request.
+const maxBulkDelete = 1000
+
+// BulkDeleteHandler godoc
+//
+//	@Summary		Delete many files at once
+//	@Description	Permanently deletes up to 1000 files by ID in one query, or moves them to the recycle bin when soft is true (already soft-deleted files keep their original deleted_at). Every ID is validated before anything is deleted. The response lists the IDs deleted and those that matched no file.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.BulkDeleteRequest	true	"IDs to delete"
+//	@Success		200		{object}	models.BulkDeleteResponse	"Deleted and missing IDs"
+//	@Failure		400		{object}	map[string]interface{}		"Invalid body, ID, or batch size"
+//	@Failure		500		{object}	map[string]interface{}		"Delete failed"
+//	@Router			/files/bulk-delete [post]
+func BulkDeleteHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.BulkDeleteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if len(req.IDs) == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "ids must not be empty"})
+			return
+		}
+		if len(req.IDs) > maxBulkDelete {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d ids allowed", maxBulkDelete)})
+			return
+		}
+
+		requested := make([]uuid.UUID, 0, len(req.IDs))
+		ids := make([]pgtype.UUID, 0, len(req.IDs))
+		seen := map[uuid.UUID]bool{}
+		for _, raw := range req.IDs {
+			parsed, err := uuid.Parse(raw)
+			if err != nil {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id", "id": raw})
+				return
+			}
+			if seen[parsed] {
+				continue
+			}
+			seen[parsed] = true
+			requested = append(requested, parsed)
+			ids = append(ids, pgtype.UUID{Bytes: parsed, Valid: true})
+		}
+
+		var deleted []pgtype.UUID
+		var err error
+		if req.Soft {
+			deleted, err = q.SoftDeleteFilesByIDs(c, ids)
+		} else {
+			deleted, err = q.DeleteFilesByIDs(c, ids)
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "bulk delete failed"})
+			return
+		}
+
+		found := make(map[uuid.UUID]bool, len(deleted))
+		for _, id := range deleted {
+			found[id.Bytes] = true
+		}
+		resp := models.BulkDeleteResponse{Deleted: []string{}, NotFound: []string{}, Soft: req.Soft}
+		for _, id := range requested {
+			if found[id] {
+				resp.Deleted = append(resp.Deleted, id.String())
+			} else {
+				resp.NotFound = append(resp.NotFound, id.String())
+			}
+		}
+		c.Set(AuditFileIDsKey, resp.Deleted)
+
+		writeJSON(c, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// CapacityGuard tracks whether the database has grown past its configured
+// size limit and, while it has, rejects writes with 507 so reads keep working
+// instead of inserts failing opaquely once storage runs out.
+type CapacityGuard struct {
+	q        *db.Queries
+	maxBytes int64
+	full     atomic.Bool
+}
+
+// NewCapacityGuard returns a guard for maxBytes; a limit of 0 never rejects.
+func NewCapacityGuard(q *db.Queries, maxBytes int64) *CapacityGuard {
+	return &CapacityGuard{q: q, maxBytes: maxBytes}
+}
+
+// Check measures the database once and updates the reject-writes state,
+// logging when the threshold is crossed in either direction. A failed
+// measurement keeps the previous state.
+func (g *CapacityGuard) Check(ctx context.Context) {
+	if g.maxBytes <= 0 {
+		return
+	}
+	size, err := g.q.GetDatabaseSize(ctx)
+	if err != nil {
+		log.Printf("capacity check failed: %v", err)
+		return
+	}
+
+	full := size >= g.maxBytes
+	if g.full.Swap(full) == full {
+		return
+	}
+	if full {
+		log.Printf("WARNING: database size %d bytes reached MAX_DB_BYTES %d; rejecting writes until space is freed", size, g.maxBytes)
+	} else {
+		log.Printf("database size %d bytes is below MAX_DB_BYTES %d again; accepting writes", size, g.maxBytes)
+	}
+}
+
+// Run checks capacity immediately and then every interval until ctx is done.
+func (g *CapacityGuard) Run(ctx context.Context, interval time.Duration) {
+	g.Check(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.Check(ctx)
+		}
+	}
+}
+
+// RejectWhenFull aborts write requests with 507 while the database is over its limit.
+func (g *CapacityGuard) RejectWhenFull() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if g.full.Load() {
+			c.AbortWithStatusJSON(http.StatusInsufficientStorage, gin.H{"error": "database is near capacity; writes are temporarily disabled"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// CloneHandler godoc
+//
+//	@Summary		Clone a file
+//	@Description	Copies a file's content, embedding, content hash, MIME type, and tags into a new file. The stored embedding is reused rather than recomputed. Without a filename in the body, the copy is named "Copy of <source filename>". Soft-deleted files cannot be cloned.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Source file UUID"
+//	@Param			request	body		models.CloneRequest	false	"Optional filename for the copy"
+//	@Success		200		{object}	models.FileUploadRequest	"The new file"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid UUID format or request body"
+//	@Failure		404		{object}	map[string]interface{}	"Source file not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to clone file"
+//	@Router			/files/{id}/clone [post]
+func CloneHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req models.CloneRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+				return
+			}
+		}
+
+		file, err := q.CloneFile(c, db.CloneFileParams{
+			Filename: pgtype.Text{String: req.Filename, Valid: req.Filename != ""},
+			ID:       pgtype.UUID{Bytes: parsedUUID, Valid: true},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to clone file"})
+			return
+		}
+		// The audit entry belongs to the new file, not the :id it was copied from.
+		c.Set(AuditFileIDKey, uuid.UUID(file.ID.Bytes).String())
+		writeJSON(c, http.StatusOK, file)
+	}
+}
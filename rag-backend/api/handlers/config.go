@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// EmbeddingConfigHandler godoc
+//
+//	@Summary		Get embedding configuration
+//	@Description	Returns the embedding dimension, models, providers, and default similarity metric the server expects. An absent expected_dimension means any length is accepted.
+//	@Tags			config
+//	@Produce		json
+//	@Success		200	{object}	models.EmbeddingConfigResponse	"Embedding configuration"
+//	@Router			/config/embeddings [get]
+func EmbeddingConfigHandler(cfg config.EmbeddingConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		providers := []string{}
+		if cfg.Provider != "" {
+			providers = append(providers, cfg.Provider)
+		}
+		embeddingModels := cfg.Models
+		if embeddingModels == nil {
+			embeddingModels = []string{}
+		}
+
+		writeJSON(c, http.StatusOK, models.EmbeddingConfigResponse{
+			ExpectedDimension: cfg.ExpectedDim,
+			Models:            embeddingModels,
+			Providers:         providers,
+			DefaultMetric:     cfg.DefaultMetric,
+		})
+	}
+}
+
+// MetricsHandler godoc
+//
+//	@Summary		List supported similarity metrics
+//	@Description	Returns each metric accepted by search endpoints with its pgvector operator, whether lower or higher distances are better, and how to read the values.
+//	@Tags			config
+//	@Produce		json
+//	@Success		200	{array}	models.MetricInfo	"Supported metrics"
+//	@Router			/config/metrics [get]
+func MetricsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		metrics := vector.Metrics()
+		resp := make([]models.MetricInfo, len(metrics))
+		for i, m := range metrics {
+			better := "higher"
+			if m.LowerIsBetter {
+				better = "lower"
+			}
+			resp[i] = models.MetricInfo{
+				Name:        m.Name,
+				Operator:    m.Operator,
+				Better:      better,
+				Description: m.Description,
+			}
+		}
+		writeJSON(c, http.StatusOK, resp)
+	}
+}
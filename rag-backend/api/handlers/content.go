@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// GetContentHandler godoc
+//
+//	@Summary		Get raw file content
+//	@Description	Returns the file's content as plain text. Honors the Range header: a satisfiable byte range returns 206 with Content-Range, an unsatisfiable one returns 416.
+//	@Tags			files
+//	@Produce		plain
+//	@Param			id		path		string	true	"File UUID"
+//	@Param			Range	header		string	false	"Byte range (e.g., bytes=0-1023)"
+//	@Success		200		{string}	string					"Full content"
+//	@Success		206		{string}	string					"Requested byte range"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid UUID format"
+//	@Failure		404		{object}	map[string]interface{}	"File not found"
+//	@Failure		416		{string}	string					"Range not satisfiable"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to get file"
+//	@Router			/files/{id}/content [get]
+func GetContentHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		file, err := q.GetFileContent(c, pgtype.UUID{Bytes: parsedUUID, Valid: true})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get file"})
+			return
+		}
+
+		// ServeContent implements Range, If-Range, and the 206/416 responses. The
+		// modtime is updated_at so date validators expire when the content changes.
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		http.ServeContent(c.Writer, c.Request, file.Filename, file.UpdatedAt.Time, strings.NewReader(file.Content))
+	}
+}
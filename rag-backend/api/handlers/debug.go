@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+)
+
+// DebugParseHandler godoc
+//
+//	@Summary		Echo a parsed upload request
+//	@Description	Binds the body as a FileUploadRequest and returns how the server interpreted it, plus validation warnings. Nothing is stored. Only available when DEBUG_ENDPOINTS is enabled.
+//	@Tags			debug
+//	@Accept			json
+//	@Produce		json
+//	@Param			file	body		models.FileUploadRequest	true	"Upload body to inspect"
+//	@Success		200		{object}	models.DebugParseResponse	"Parsed request"
+//	@Failure		400		{object}	map[string]interface{}		"Body could not be parsed"
+//	@Router			/files/debug-parse [post]
+func DebugParseHandler(cfg config.EmbeddingConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.FileUploadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request", "detail": err.Error()})
+			return
+		}
+
+		warnings := []string{}
+		if req.Filename == "" {
+			warnings = append(warnings, "filename is empty")
+		}
+		if req.Content == "" {
+			warnings = append(warnings, "content is empty")
+		}
+		if len(req.Embedding) == 0 {
+			warnings = append(warnings, "embedding is empty")
+		} else if cfg.ExpectedDim > 0 && len(req.Embedding) != cfg.ExpectedDim {
+			warnings = append(warnings, fmt.Sprintf("embedding has %d dimensions, expected %d", len(req.Embedding), cfg.ExpectedDim))
+		}
+		if !req.CreatedAt.IsZero() {
+			warnings = append(warnings, "created_at is ignored on upload")
+		}
+		if req.Deleted {
+			warnings = append(warnings, "deleted is ignored on upload")
+		}
+
+		writeJSON(c, http.StatusOK, models.DebugParseResponse{
+			Filename:        req.Filename,
+			ContentLength:   len(req.Content),
+			EmbeddingLength: len(req.Embedding),
+			CreatedAt:       req.CreatedAt,
+			Deleted:         req.Deleted,
+			Warnings:        warnings,
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DecompressBody transparently inflates request bodies sent with
+// Content-Encoding: gzip. The body is inflated up front so a payload that
+// expands beyond maxBytes (a zip bomb) is rejected with 413 before any
+// handler reads it; a corrupt stream is rejected with 400.
+func DecompressBody(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.EqualFold(strings.TrimSpace(c.GetHeader("Content-Encoding")), "gzip") {
+			c.Next()
+			return
+		}
+
+		zr, err := gzip.NewReader(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid gzip body"})
+			return
+		}
+		defer zr.Close()
+
+		inflated, err := io.ReadAll(io.LimitReader(zr, maxBytes+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid gzip body"})
+			return
+		}
+		if int64(len(inflated)) > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "decompressed body too large"})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(inflated))
+		c.Request.ContentLength = int64(len(inflated))
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.Header.Del("Content-Length")
+		c.Next()
+	}
+}
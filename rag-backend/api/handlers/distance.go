@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// maxDistanceMatrixIDs bounds the O(N²) computation and response size.
+const maxDistanceMatrixIDs = 100
+
+// DistanceMatrixHandler godoc
+//
+//	@Summary		Compute pairwise embedding distances
+//	@Description	Fetches the embeddings of the given files once and returns the NxN distance matrix between them, using pgvector semantics for the metric (l2, cosine, or inner; defaults to DEFAULT_METRIC). At most 100 IDs are accepted.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.DistanceMatrixRequest	true	"File IDs and metric"
+//	@Success		200		{object}	models.DistanceMatrixResponse	"Distance matrix"
+//	@Failure		400		{object}	map[string]interface{}			"Invalid IDs, metric, set size, or mismatched dimensions"
+//	@Failure		404		{object}	map[string]interface{}			"One or more files not found"
+//	@Failure		500		{object}	map[string]interface{}			"Failed to fetch embeddings"
+//	@Router			/files/distance-matrix [post]
+func DistanceMatrixHandler(q *db.Queries, cfg config.EmbeddingConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.DistanceMatrixRequest
+		if err := c.BindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+
+		if len(req.IDs) == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "ids must not be empty"})
+			return
+		}
+		if len(req.IDs) > maxDistanceMatrixIDs {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d ids allowed", maxDistanceMatrixIDs)})
+			return
+		}
+
+		metric := req.Metric
+		if metric == "" {
+			metric = cfg.DefaultMetric
+		}
+		if !vector.ValidMetric(metric) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "unsupported metric"})
+			return
+		}
+
+		dbIDs := make([]pgtype.UUID, len(req.IDs))
+		for i, id := range req.IDs {
+			parsedUUID, err := uuid.Parse(id)
+			if err != nil {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id", "id": id})
+				return
+			}
+			dbIDs[i] = pgtype.UUID{Bytes: parsedUUID, Valid: true}
+		}
+
+		rows, err := q.GetEmbeddingsByIDs(c, dbIDs)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to fetch embeddings"})
+			return
+		}
+
+		byID := make(map[[16]byte][]float32, len(rows))
+		for _, row := range rows {
+			byID[row.ID.Bytes] = row.Embedding.Slice()
+		}
+
+		vectors := make([][]float32, len(dbIDs))
+		var missing []string
+		for i, id := range dbIDs {
+			vec, ok := byID[id.Bytes]
+			if !ok {
+				missing = append(missing, req.IDs[i])
+				continue
+			}
+			vectors[i] = vec
+		}
+		if len(missing) > 0 {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "files not found", "missing": missing})
+			return
+		}
+
+		matrix := make([][]float64, len(vectors))
+		for i := range matrix {
+			matrix[i] = make([]float64, len(vectors))
+		}
+		for i := range vectors {
+			for j := i; j < len(vectors); j++ {
+				d, err := vector.Distance(metric, vectors[i], vectors[j])
+				if errors.Is(err, vector.ErrDimensionMismatch) {
+					writeJSON(c, http.StatusBadRequest, gin.H{"error": "embedding dimensions differ"})
+					return
+				}
+				if err != nil {
+					writeJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				matrix[i][j] = d
+				matrix[j][i] = d
+			}
+		}
+
+		writeJSON(c, http.StatusOK, models.DistanceMatrixResponse{
+			IDs:    req.IDs,
+			Metric: metric,
+			Matrix: matrix,
+		})
+	}
+}
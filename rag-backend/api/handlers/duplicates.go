@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// DuplicatesHandler godoc
+//
+//	@Summary		Report duplicate files
+//	@Description	Groups non-deleted files whose content hashes match, largest groups first. Each group lists file IDs oldest first, so the first ID is the one uploads resolve to. Files without a content hash are not considered.
+//	@Tags			files
+//	@Produce		json
+//	@Success		200	{array}		models.DuplicateGroup	"Groups of files with identical content"
+//	@Failure		500	{object}	map[string]interface{}	"Failed to find duplicates"
+//	@Router			/files/duplicates [get]
+func DuplicatesHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rows, err := q.GetDuplicateGroups(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to find duplicates"})
+			return
+		}
+
+		groups := make([]models.DuplicateGroup, len(rows))
+		for i, row := range rows {
+			ids := make([]string, len(row.Ids))
+			for j, id := range row.Ids {
+				ids[j] = uuid.UUID(id.Bytes).String()
+			}
+			groups[i] = models.DuplicateGroup{ContentHash: row.ContentHash, Count: row.Count, IDs: ids}
+		}
+
+		writeJSON(c, http.StatusOK, groups)
+	}
+}
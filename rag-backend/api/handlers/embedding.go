@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// EmbeddingStatsHandler godoc
+//
+//	@Summary		Get embedding statistics for a file
+//	@Description	Computes the L2 norm, min, max, mean, and zero count of the file's stored embedding without returning the vector itself. Useful for spotting degenerate or unnormalized embeddings.
+//	@Tags			files
+//	@Produce		json
+//	@Param			id	path		string					true	"File UUID"
+//	@Success		200	{object}	models.EmbeddingStats	"Embedding statistics"
+//	@Failure		400	{object}	map[string]interface{}	"Invalid UUID format"
+//	@Failure		404	{object}	map[string]interface{}	"File not found"
+//	@Failure		500	{object}	map[string]interface{}	"Failed to get embedding"
+//	@Router			/files/{id}/embedding/stats [get]
+func EmbeddingStatsHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		embedding, err := q.GetFileEmbedding(c, pgtype.UUID{Bytes: parsedUUID, Valid: true})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get embedding"})
+			return
+		}
+
+		stats := vector.Summarize(embedding.Slice())
+		writeJSON(c, http.StatusOK, models.EmbeddingStats{
+			ID:        parsedUUID.String(),
+			Dimension: stats.Dimension,
+			Norm:      stats.Norm,
+			Min:       stats.Min,
+			Max:       stats.Max,
+			Mean:      stats.Mean,
+			Zeros:     stats.Zeros,
+		})
+	}
+}
+
+// UpdateEmbeddingHandler godoc
+//
+//	@Summary		Replace a file's embedding
+//	@Description	Overwrites only the stored vector, leaving filename and content untouched, so re-embedding jobs need not resend documents. The new embedding must have the same dimension as the one it replaces. The prior state is kept as a version.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"File UUID"
+//	@Param			body	body		models.EmbeddingUpdateRequest	true	"New embedding"
+//	@Success		200		{object}	models.EmbeddingUpdateResponse	"Embedding replaced"
+//	@Failure		400		{object}	map[string]interface{}			"Invalid UUID, missing embedding, or dimension mismatch"
+//	@Failure		404		{object}	map[string]interface{}			"File not found"
+//	@Failure		500		{object}	map[string]interface{}			"Failed to update embedding"
+//	@Router			/files/{id}/embedding [patch]
+func UpdateEmbeddingHandler(q *db.Queries, keepVersions int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req models.EmbeddingUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if len(req.Embedding) == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "embedding is required"})
+			return
+		}
+
+		id := pgtype.UUID{Bytes: parsedUUID, Valid: true}
+		current, err := q.GetFileEmbedding(c, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get embedding"})
+			return
+		}
+		if dim := len(current.Slice()); len(req.Embedding) != dim {
+			writeJSON(c, http.StatusBadRequest, gin.H{
+				"error":    errDimensionMismatch,
+				"expected": dim,
+				"got":      len(req.Embedding),
+			})
+			return
+		}
+
+		var updatedAt pgtype.Timestamptz
+		// The snapshot and the overwrite commit together so no update loses history.
+		err = q.ExecTx(c, func(qtx *db.Queries) error {
+			if err := saveVersion(c, qtx, id, keepVersions); err != nil {
+				return err
+			}
+			var err error
+			updatedAt, err = qtx.UpdateFileEmbedding(c, db.UpdateFileEmbeddingParams{
+				Embedding:          pgvector.NewVector(req.Embedding),
+				EmbeddingTruncated: req.EmbeddingTruncated,
+				ID:                 id,
+			})
+			return err
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to update embedding"})
+			return
+		}
+
+		writeJSON(c, http.StatusOK, models.EmbeddingUpdateResponse{
+			ID:        parsedUUID.String(),
+			Dimension: len(req.Embedding),
+			UpdatedAt: updatedAt.Time,
+		})
+	}
+}
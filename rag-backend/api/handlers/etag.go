@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// weakETag returns a weak entity tag over parts. Tags are weak because
+// ?pretty=true changes the bytes of an otherwise identical representation.
+func weakETag(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// fileETag identifies a file's current state. Every write that changes the
+// stored row bumps updated_at except soft delete and restore, which are
+// covered by deleted_at.
+func fileETag(f db.File) string {
+	return weakETag(etagTime(f.UpdatedAt), etagTime(f.DeletedAt), etagTime(f.ReviewedAt), f.ContentHash.String)
+}
+
+// etagTime renders ts for hashing, with NULL as the empty string.
+func etagTime(ts pgtype.Timestamptz) string {
+	if !ts.Valid {
+		return ""
+	}
+	return ts.Time.UTC().Format(time.RFC3339Nano)
+}
+
+// jsonETag identifies a response by its JSON encoding, for lists with no
+// single version column.
+func jsonETag(v any) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return weakETag(string(raw)), nil
+}
+
+// notModified sets the ETag header and, when If-None-Match already names the
+// tag, responds 304 and reports true so the caller skips the body.
+func notModified(c *gin.Context, tag string) bool {
+	c.Header("ETag", tag)
+	if !etagMatches(c.GetHeader("If-None-Match"), tag) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies If-None-Match's weak comparison: "*" or any listed tag
+// equal to tag once W/ prefixes are ignored.
+func etagMatches(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// maxExistsBatch caps the combined number of hashes and filenames per request.
+const maxExistsBatch = 1000
+
+// ExistsBatchHandler godoc
+//
+//	@Summary		Check which files already exist
+//	@Description	Looks up many content hashes and/or filenames in one query against non-deleted files and reports, for each list, which identifiers exist (with the matching file IDs) and which are missing. Hashes are hex SHA-256 of the content, as stored on upload; rows written before hashes were stored only match by filename. At most 1000 identifiers in total.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.ExistsBatchRequest	true	"Hashes and filenames to check"
+//	@Success		200		{object}	models.ExistsBatchResponse	"Existing and missing identifiers"
+//	@Failure		400		{object}	map[string]interface{}		"Invalid body, hash, or batch size"
+//	@Failure		500		{object}	map[string]interface{}		"Lookup failed"
+//	@Router			/files/exists/batch [post]
+func ExistsBatchHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.ExistsBatchRequest
+		if err := c.BindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+
+		total := len(req.Hashes) + len(req.Filenames)
+		if total == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "hashes or filenames must not be empty"})
+			return
+		}
+		if total > maxExistsBatch {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d identifiers allowed", maxExistsBatch)})
+			return
+		}
+
+		hashes := make([]string, len(req.Hashes))
+		for i, h := range req.Hashes {
+			h = strings.ToLower(h)
+			if b, err := hex.DecodeString(h); err != nil || len(b) != 32 {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid hash", "hash": req.Hashes[i]})
+				return
+			}
+			hashes[i] = h
+		}
+		filenames := append([]string{}, req.Filenames...)
+
+		rows, err := q.FindExistingFiles(c, db.FindExistingFilesParams{
+			Filenames: filenames,
+			Hashes:    hashes,
+		})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to look up files"})
+			return
+		}
+
+		byHash := map[string][]string{}
+		byFilename := map[string][]string{}
+		for _, row := range rows {
+			id := uuid.UUID(row.ID.Bytes).String()
+			if row.ContentHash.Valid {
+				byHash[row.ContentHash.String] = append(byHash[row.ContentHash.String], id)
+			}
+			byFilename[row.Filename] = append(byFilename[row.Filename], id)
+		}
+
+		writeJSON(c, http.StatusOK, models.ExistsBatchResponse{
+			Hashes:    existsResult(hashes, byHash),
+			Filenames: existsResult(filenames, byFilename),
+		})
+	}
+}
+
+// existsResult partitions the requested identifiers using the IDs found for each.
+func existsResult(requested []string, found map[string][]string) models.ExistsResult {
+	result := models.ExistsResult{Existing: map[string][]string{}, Missing: []string{}}
+	for _, key := range requested {
+		if ids, ok := found[key]; ok {
+			result.Existing[key] = ids
+		} else if !slices.Contains(result.Missing, key) {
+			result.Missing = append(result.Missing, key)
+		}
+	}
+	return result
+}
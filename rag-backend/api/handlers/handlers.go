@@ -1,28 +1,33 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	pgvector "github.com/pgvector/pgvector-go"
 
 	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
 	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/embedding"
 )
 
 // GetHandler godoc
 //
 //	@Summary		Get file by ID
-//	@Description	Retrieves a specific file by its UUID. Returns the complete file data including content and embedding vector.
+//	@Description	Retrieves a specific file by its UUID. Returns the complete file data including content and embedding vector. The response carries an ETag that changes whenever the file does; send it back in If-None-Match to get 304 with no body while the file is unchanged.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string	true	"File UUID (e.g., 550e8400-e29b-41d4-a716-446655440000)"
+//	@Param			id				path		string	true	"File UUID (e.g., 550e8400-e29b-41d4-a716-446655440000)"
+//	@Param			If-None-Match	header		string	false	"ETag from an earlier response"
 //	@Success		200	{object}	models.FileUploadRequest	"File data retrieved successfully"
+//	@Success		304	"File unchanged since the given ETag"
 //	@Failure		400	{object}	map[string]interface{}	"Invalid UUID format"
 //	@Failure		404	{object}	map[string]interface{}	"File not found"
 //	@Failure		500	{object}	map[string]interface{}	"Internal server error"
@@ -32,57 +37,111 @@ func GetHandler(q *db.Queries) gin.HandlerFunc {
 		id := c.Param("id")
 		parsedUUID, err := uuid.Parse(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
 			return
 		}
 
 		var dbUUID pgtype.UUID
 		if err := dbUUID.Scan(parsedUUID.String()); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to convert UUID"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to convert UUID"})
 			return
 		}
 
 		file, err := q.GetFile(c, dbUUID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get file"})
+			return
+		}
+		if notModified(c, fileETag(file)) {
 			return
 		}
 
-		c.JSON(http.StatusOK, file)
+		writeJSON(c, http.StatusOK, file)
 	}
 }
 
 // GetAllHandler godoc
 //
 //	@Summary		Get all files
-//	@Description	Retrieves all files from the database. Returns a list of all files with their content and embeddings.
+//	@Description	Retrieves all files from the database, newest first unless sort and order say otherwise. By default each entry is a lightweight summary (ID, filename, size, creation date, deleted flag) without content or embeddings; pass include_content=true for the full records.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{array}	models.FileUploadRequest	"List of all files"
+//	@Param			include_content	query	bool	false	"Return full records including content and embeddings"
+//	@Param			mime_type		query	string	false	"Only files with this MIME type (e.g., application/pdf)"
+//	@Param			sort			query	string	false	"Sort column"		Enums(created_at, filename, size)	default(created_at)
+//	@Param			order			query	string	false	"Sort direction"	Enums(asc, desc)					default(desc)
+//	@Success		200	{array}	models.FileSummary	"List of all files (full records when include_content=true)"
+//	@Failure		400	{object}	map[string]interface{}	"Invalid sort or order"
 //	@Failure		404	{object}	map[string]interface{}	"No files found"
 //	@Failure		500	{object}	map[string]interface{}	"Internal server error"
 //	@Router			/files/getall [get]
 func GetAllHandler(q *db.Queries) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		files, err := q.GetAllFiles(c)
+		sort, descending, errMsg := listOrder(c)
+		if errMsg != "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errMsg})
+			return
+		}
+
+		if c.Query("include_content") == "true" {
+			files, err := q.GetAllFiles(c, db.GetAllFilesParams{
+				MimeType:   mimeTypeFilter(c),
+				Sort:       sort,
+				Descending: descending,
+			})
+			if err != nil {
+				writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+				return
+			}
+
+			writeJSON(c, http.StatusOK, files)
+			return
+		}
+
+		rows, err := q.GetAllFileSummaries(c, db.GetAllFileSummariesParams{
+			MimeType:   mimeTypeFilter(c),
+			Sort:       sort,
+			Descending: descending,
+		})
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
 			return
 		}
 
-		c.JSON(http.StatusOK, files)
+		files := make([]models.FileSummary, len(rows))
+		for i, row := range rows {
+			files[i] = models.FileSummary{
+				FileMetadata: models.FileMetadata{
+					ID:        uuid.UUID(row.ID.Bytes).String(),
+					Filename:  row.Filename,
+					Size:      int(row.Size),
+					CreatedAt: row.CreatedAt.Time,
+				},
+				Deleted:  row.Deleted.Bool,
+				MimeType: row.MimeType,
+			}
+		}
+
+		writeJSON(c, http.StatusOK, files)
 	}
 }
 
 // GetFilesByFilenameHandler godoc
 //
 //	@Summary		Search files by filename
-//	@Description	Searches for files whose filename contains the specified query string. Case-sensitive search.
+//	@Description	Searches for files whose filename contains the specified query string. Matching is case-insensitive unless case_sensitive=true. % and _ in the query match literally. With dedup=true, files with identical content are collapsed to the first match.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
 //	@Param			query	query		string	true	"Search keyword to match in filename (e.g., 'document', 'report')"
+//	@Param			case_sensitive	query	bool	false	"Match the filename case exactly"
+//	@Param			dedup	query		bool	false	"Collapse results with identical content"
+//	@Param			mime_type	query	string	false	"Only files with this MIME type (e.g., application/pdf)"
 //	@Success		200		{array}		models.FileUploadRequest	"Files matching the search query"
 //	@Failure		400		{object}	map[string]interface{}	"Query parameter is required"
 //	@Failure		500		{object}	map[string]interface{}	"Search operation failed"
@@ -91,17 +150,24 @@ func GetFilesByFilenameHandler(q *db.Queries) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		query := c.Query("query")
 		if query == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter is required"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "query parameter is required"})
 			return
 		}
 
-		files, err := q.GetFilesByFilename(c, pgtype.Text{String: query, Valid: true})
+		files, err := q.GetFilesByFilename(c, db.GetFilesByFilenameParams{
+			CaseSensitive: c.Query("case_sensitive") == "true",
+			Query:         escapeLike(query),
+			MimeType:      mimeTypeFilter(c),
+		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "search failed"})
 			return
 		}
+		if c.Query("dedup") == "true" {
+			files = dedupByContentHash(files, storedContentHash)
+		}
 
-		c.JSON(http.StatusOK, files)
+		writeJSON(c, http.StatusOK, files)
 	}
 }
 
@@ -125,13 +191,13 @@ func GetFilesByDateRangeHandler(q *db.Queries) gin.HandlerFunc {
 
 		startDate, err := time.Parse("2006-01-02", start)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start date"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid start date"})
 			return
 		}
 
 		endDate, err := time.Parse("2006-01-02", end)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid end date"})
 			return
 		}
 
@@ -147,51 +213,157 @@ func GetFilesByDateRangeHandler(q *db.Queries) gin.HandlerFunc {
 
 		files, err := q.GetFilesByDateRange(c, params)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get files by date"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get files by date"})
 			return
 		}
 
-		c.JSON(http.StatusOK, files)
+		writeJSON(c, http.StatusOK, files)
 	}
 }
 
 // UploadHandler godoc
 //
 //	@Summary		Upload a file
-//	@Description	Stores a new file with its content and embedding vector. The embedding should be a vector representation of the file content for similarity search.
+//	@Description	Stores a new file with its content, embedding vector, and optional tags. The embedding should be a vector representation of the file content for similarity search. A new file is answered with 201 and a Location header naming it. If a non-deleted file already has the same content (by SHA-256), that file is returned with 200 instead of storing a duplicate; pass force=true to store it anyway. With unique_filename=true, an upload whose filename matches a non-deleted file is rejected with 409 and the existing file's id. If the embedding is omitted and a server-side embedder is configured (OPENAI_API_KEY), the content is embedded before storing. An optional summary is stored with its own summary_embedding for ?mode=summary search; the embedder fills in a missing summary_embedding the same way. When EXPECTED_EMBEDDING_DIM is set, embeddings of any other length are rejected with 400.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
 //	@Param			file	body		models.FileUploadRequest	true	"File data including filename, content, and embedding vector"
-//	@Success		200		{object}	models.FileUploadRequest	"File uploaded successfully"
-//	@Failure		400		{object}	map[string]interface{}	"Invalid request body"
+//	@Param			force	query		bool						false	"Store the file even if identical content already exists"
+//	@Param			unique_filename	query	bool					false	"Reject with 409 if a non-deleted file already has this filename"
+//	@Success		201		{object}	models.FileUploadRequest	"File created; Location holds its URL"
+//	@Success		200		{object}	models.FileUploadRequest	"Existing file with identical content"
+//	@Header			201		{string}	Location					"/files/{id} of the created file"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or embedding dimension mismatch"
+//	@Failure		409		{object}	map[string]interface{}	"unique_filename=true and the filename is taken; body has error and the existing id"
 //	@Failure		500		{object}	map[string]interface{}	"Failed to create file"
+//	@Failure		502		{object}	map[string]interface{}	"Embedding provider failed"
 //	@Router			/files/upload [post]
-func UploadHandler(q *db.Queries) gin.HandlerFunc {
+func UploadHandler(q *db.Queries, cfg config.EmbeddingConfig, embedder embedding.Embedder) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		var req models.FileUploadRequest
 
 		if err := c.BindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request"})
 			return
 		}
-		vec := pgvector.NewVector(req.Embedding)
-		file, err := q.CreateFile(c, db.CreateFileParams{
-			Filename:  req.Filename,
-			Content:   req.Content,
-			Embedding: vec,
+		tags, errMsg := normalizeTags(req.Tags)
+		if errMsg != "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errMsg})
+			return
+		}
+		if c.Query("unique_filename") == "true" {
+			existing, err := q.GetLatestFileByFilename(c, req.Filename)
+			if err == nil {
+				writeJSON(c, http.StatusConflict, gin.H{
+					"error": "filename already exists",
+					"id":    uuid.UUID(existing.ID.Bytes).String(),
+				})
+				return
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to check filename"})
+				return
+			}
+		}
+		hash := contentHashText(req.Content)
+		// Look for a duplicate before embedding so repeat uploads cost nothing.
+		if c.Query("force") != "true" {
+			existing, err := q.GetFileByContentHash(c, hash)
+			if err == nil {
+				c.Set(AuditFileIDKey, uuid.UUID(existing.ID.Bytes).String())
+				writeJSON(c, http.StatusOK, existing)
+				return
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to check for duplicates"})
+				return
+			}
+		}
+		if len(req.Embedding) == 0 && req.Content != "" && embedder != nil {
+			vec, err := embedder.Embed(c, req.Content)
+			if err != nil {
+				writeJSON(c, http.StatusBadGateway, gin.H{"error": "failed to embed content"})
+				return
+			}
+			req.Embedding = vec
+			req.EmbeddingTruncated = false
+		}
+		if req.Summary != "" && len(req.SummaryEmbedding) == 0 && embedder != nil {
+			vec, err := embedder.Embed(c, req.Summary)
+			if err != nil {
+				writeJSON(c, http.StatusBadGateway, gin.H{"error": "failed to embed summary"})
+				return
+			}
+			req.SummaryEmbedding = vec
+		}
+		if dimensionMismatch(req.Embedding, cfg.ExpectedDim) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errDimensionMismatch})
+			return
+		}
+		summary, summaryEmbedding, errMsg := summaryColumns(req, cfg.ExpectedDim)
+		if errMsg != "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errMsg})
+			return
+		}
+		params := db.CreateFileParams{
+			Filename:           req.Filename,
+			Content:            req.Content,
+			Embedding:          pgvector.NewVector(req.Embedding),
+			ContentHash:        hash,
+			MimeType:           detectMimeType([]byte(req.Content)),
+			EmbeddingTruncated: req.EmbeddingTruncated,
+			Tags:               tags,
+			Summary:            summary,
+			SummaryEmbedding:   summaryEmbedding,
+		}
+		// The check above is repeated under a per-hash lock so two concurrent
+		// uploads of the same content cannot both insert. A unique index would
+		// rule out force=true and the duplicates that already exist.
+		var file db.File
+		duplicate := false
+		err := q.ExecTx(c, func(qtx *db.Queries) error {
+			if c.Query("force") != "true" {
+				if err := qtx.LockContentHash(c, hash.String); err != nil {
+					return err
+				}
+				existing, err := qtx.GetFileByContentHash(c, hash)
+				if err == nil {
+					file, duplicate = existing, true
+					return nil
+				}
+				if !errors.Is(err, pgx.ErrNoRows) {
+					return err
+				}
+			}
+			var err error
+			file, err = qtx.CreateFile(c, params)
+			return err
 		})
-		fmt.Print(err)
-
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create file"})
+			// Attached errors are logged by RequestLogger with the request ID.
+			_ = c.Error(err)
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to create file"})
+			return
+		}
+		if duplicate {
+			c.Set(AuditFileIDKey, uuid.UUID(file.ID.Bytes).String())
+			writeJSON(c, http.StatusOK, file)
 			return
 		}
-		c.JSON(http.StatusOK, file)
+		writeCreated(c, file)
 	}
 }
 
+// writeCreated answers a newly stored file with 201 and a Location header naming it.
+func writeCreated(c *gin.Context, file db.File) {
+	id := uuid.UUID(file.ID.Bytes).String()
+	c.Set(AuditFileIDKey, id)
+	c.Header("Location", "/files/"+id)
+	writeJSON(c, http.StatusCreated, file)
+}
+
 // DeleteHandler godoc
 //
 //	@Summary		Delete a file permanently
@@ -202,6 +374,7 @@ func UploadHandler(q *db.Queries) gin.HandlerFunc {
 //	@Param			id	path		string	true	"File UUID to delete"
 //	@Success		204	{object}	nil	"File deleted successfully"
 //	@Failure		400	{object}	map[string]interface{}	"Invalid UUID format"
+//	@Failure		404	{object}	map[string]interface{}	"File not found"
 //	@Failure		500	{object}	map[string]interface{}	"Delete operation failed"
 //	@Router			/files/{id} [delete]
 func DeleteHandler(q *db.Queries) gin.HandlerFunc {
@@ -209,19 +382,23 @@ func DeleteHandler(q *db.Queries) gin.HandlerFunc {
 		id := c.Param("id")
 		parsedUUID, err := uuid.Parse(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
 			return
 		}
 
 		var dbUUID pgtype.UUID
 		if err := dbUUID.Scan(parsedUUID.String()); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to convert UUID"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to convert UUID"})
 			return
 		}
 
-		err = q.DeleteFile(c, dbUUID)
+		deleted, err := q.DeleteFile(c, dbUUID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "delete failed"})
+			return
+		}
+		if deleted == 0 {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
 			return
 		}
 
@@ -232,57 +409,91 @@ func DeleteHandler(q *db.Queries) gin.HandlerFunc {
 // UpdateHandler godoc
 //
 //	@Summary		Update a file
-//	@Description	Updates an existing file's content, filename, and embedding vector. All fields in the request body will replace the existing values.
+//	@Description	Updates an existing file's content, filename, and embedding vector. All fields in the request body will replace the existing values, except tags, which are kept when omitted. The summary is replaced too, so an update without one clears the summary of the old content. The state being replaced is saved as a version first (see /files/{id}/versions). When EXPECTED_EMBEDDING_DIM is set, embeddings of any other length are rejected with 400.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string					true	"File UUID to update"
 //	@Param			file	body		models.FileUploadRequest	true	"Updated file data"
 //	@Success		200		{object}	models.FileUploadRequest	"File updated successfully"
-//	@Failure		400		{object}	map[string]interface{}	"Invalid UUID or request body"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid UUID, request body, or embedding dimension"
+//	@Failure		404		{object}	map[string]interface{}	"File not found"
 //	@Failure		500		{object}	map[string]interface{}	"Update operation failed"
 //	@Router			/files/{id} [put]
-func UpdateHandler(q *db.Queries) gin.HandlerFunc {
+func UpdateHandler(q *db.Queries, cfg config.EmbeddingConfig, keepVersions int) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 		parsedUUID, err := uuid.Parse(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
 			return
 		}
 
 		var dbUUID pgtype.UUID
 		if err := dbUUID.Scan(parsedUUID.String()); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to convert UUID"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to convert UUID"})
 			return
 		}
 
 		var req models.FileUploadRequest
 		if err := c.BindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if dimensionMismatch(req.Embedding, cfg.ExpectedDim) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errDimensionMismatch})
+			return
+		}
+		tags, errMsg := normalizeTags(req.Tags)
+		if errMsg != "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errMsg})
+			return
+		}
+		summary, summaryEmbedding, errMsg := summaryColumns(req, cfg.ExpectedDim)
+		if errMsg != "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errMsg})
 			return
 		}
 
 		vec := pgvector.NewVector(req.Embedding)
-		updated, err := q.UpdateFile(c, db.UpdateFileParams{
-			ID:        dbUUID,
-			Filename:  req.Filename,
-			Content:   req.Content,
-			Embedding: vec,
+		var updated db.File
+		// The snapshot and the overwrite commit together so no update loses history.
+		err = q.ExecTx(c, func(qtx *db.Queries) error {
+			if err := saveVersion(c, qtx, dbUUID, keepVersions); err != nil {
+				return err
+			}
+			var err error
+			updated, err = qtx.UpdateFile(c, db.UpdateFileParams{
+				ID:                 dbUUID,
+				Filename:           req.Filename,
+				Content:            req.Content,
+				Embedding:          vec,
+				ContentHash:        contentHashText(req.Content),
+				MimeType:           detectMimeType([]byte(req.Content)),
+				EmbeddingTruncated: req.EmbeddingTruncated,
+				Tags:               tags,
+				Summary:            summary,
+				SummaryEmbedding:   summaryEmbedding,
+			})
+			return err
 		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "update failed"})
 			return
 		}
 
-		c.JSON(http.StatusOK, updated)
+		writeJSON(c, http.StatusOK, updated)
 	}
 }
 
 // SoftDeleteHandler godoc
 //
 //	@Summary		Soft delete a file
-//	@Description	Marks a file as deleted without removing it from the database. The file can be restored later using the restore endpoint.
+//	@Description	Marks a file as deleted without removing it from the database by recording deleted_at. Deleting an already deleted file keeps the original deleted_at. The file can be restored later using the restore endpoint.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
@@ -297,30 +508,30 @@ func SoftDeleteHandler(q *db.Queries) gin.HandlerFunc {
 
 		parsedUUID, err := uuid.Parse(idParam)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid UUID"})
 			return
 		}
 
 		var dbUUID pgtype.UUID
 		if err := dbUUID.Scan(parsedUUID.String()); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "UUID conversion failed"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "UUID conversion failed"})
 			return
 		}
 
 		err = q.SoftDeleteFile(c, dbUUID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not soft delete file"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "could not soft delete file"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "file soft-deleted successfully"})
+		writeJSON(c, http.StatusOK, gin.H{"message": "file soft-deleted successfully"})
 	}
 }
 
 // UndoSoftDeleteHandler godoc
 //
 //	@Summary		Restore a soft-deleted file
-//	@Description	Restores a previously soft-deleted file by setting its deleted flag back to false. The file becomes available again.
+//	@Description	Restores a previously soft-deleted file by clearing deleted_at, which also clears the deleted flag. The file becomes available again.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
@@ -335,30 +546,30 @@ func UndoSoftDeleteHandler(q *db.Queries) gin.HandlerFunc {
 
 		parsedUUID, err := uuid.Parse(idParam)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid UUID"})
 			return
 		}
 
 		var dbUUID pgtype.UUID
 		if err := dbUUID.Scan(parsedUUID.String()); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "UUID conversion failed"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "UUID conversion failed"})
 			return
 		}
 
 		err = q.UndoSoftDelete(c, dbUUID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not restore file"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "could not restore file"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "file restored successfully"})
+		writeJSON(c, http.StatusOK, gin.H{"message": "file restored successfully"})
 	}
 }
 
 // GetDeletedFilesHandler godoc
 //
 //	@Summary		Get all soft-deleted files
-//	@Description	Retrieves all files that have been soft-deleted (moved to recycle bin). These files can be restored or permanently deleted.
+//	@Description	Retrieves all files that have been soft-deleted (moved to recycle bin), most recently deleted first. Each file includes DeletedAt, when it was moved to the recycle bin. These files can be restored or permanently deleted.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
@@ -369,32 +580,147 @@ func GetDeletedFilesHandler(q *db.Queries) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		files, err := q.GetDeletedFiles(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch deleted files"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "could not fetch deleted files"})
 			return
 		}
 
-		c.JSON(http.StatusOK, files)
+		writeJSON(c, http.StatusOK, files)
 	}
 }
 
+// PurgeFileHandler godoc
+//
+//	@Summary		Permanently delete a file from the recycle bin
+//	@Description	Permanently removes a soft-deleted file. Files that are not in the recycle bin are left untouched and return 404; use DELETE /files/{id} to remove a live file.
+//	@Tags			files
+//	@Produce		json
+//	@Param			id	path		string	true	"File UUID to purge"
+//	@Success		204	{object}	nil	"File purged"
+//	@Failure		400	{object}	map[string]interface{}	"Invalid UUID format"
+//	@Failure		404	{object}	map[string]interface{}	"File not in recycle bin"
+//	@Failure		500	{object}	map[string]interface{}	"Purge failed"
+//	@Router			/files/{id}/purge [delete]
+func PurgeFileHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid UUID"})
+			return
+		}
+
+		purged, err := q.PurgeFile(c, pgtype.UUID{Bytes: parsedUUID, Valid: true})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "could not purge file"})
+			return
+		}
+		if purged == 0 {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not in recycle bin"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// EmptyRecycleBinHandler godoc
+//
+//	@Summary		Empty the recycle bin
+//	@Description	Permanently removes every soft-deleted file and returns how many were purged. Live files are not affected. With dry_run=true nothing is deleted; the response reports how many files would be purged and a sample of them.
+//	@Tags			files
+//	@Produce		json
+//	@Param			dry_run	query		bool					false	"Preview the purge without deleting"
+//	@Success		200		{object}	models.PurgeResponse	"Number of files purged"
+//	@Failure		500		{object}	map[string]interface{}	"Purge failed"
+//	@Router			/files/recycle-bin [delete]
+func EmptyRecycleBinHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("dry_run") == "true" {
+			previewRecycleBinPurge(c, q)
+			return
+		}
+
+		ids, err := q.PurgeRecycleBin(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "could not empty recycle bin"})
+			return
+		}
+
+		purged := make([]string, len(ids))
+		for i, id := range ids {
+			purged[i] = uuid.UUID(id.Bytes).String()
+		}
+		c.Set(AuditFileIDsKey, purged)
+
+		writeJSON(c, http.StatusOK, models.PurgeResponse{Purged: int64(len(ids))})
+	}
+}
+
+// purgeSampleSize caps how many files a dry-run purge lists.
+const purgeSampleSize = 20
+
+// previewRecycleBinPurge reports what emptying the recycle bin would remove without deleting anything.
+func previewRecycleBinPurge(c *gin.Context, q *db.Queries) {
+	count, err := q.CountRecycleBin(c)
+	if err != nil {
+		writeJSON(c, http.StatusInternalServerError, gin.H{"error": "could not count recycle bin"})
+		return
+	}
+	rows, err := q.GetRecycleBinSample(c, purgeSampleSize)
+	if err != nil {
+		writeJSON(c, http.StatusInternalServerError, gin.H{"error": "could not list recycle bin"})
+		return
+	}
+
+	sample := make([]models.FileMetadata, len(rows))
+	for i, row := range rows {
+		sample[i] = models.FileMetadata{
+			ID:        uuid.UUID(row.ID.Bytes).String(),
+			Filename:  row.Filename,
+			Size:      int(row.Size),
+			CreatedAt: row.CreatedAt.Time,
+		}
+	}
+
+	writeJSON(c, http.StatusOK, models.PurgeResponse{Purged: count, DryRun: true, Sample: sample})
+}
+
 // GetFileMetadataHandler godoc
 //
 //	@Summary		Get lightweight file metadata
-//	@Description	Retrieves lightweight metadata for all files including ID, filename, size, and creation date. Does not include file content or embeddings for performance.
+//	@Description	Retrieves lightweight metadata for all files including ID, filename, size, and creation date, newest first unless sort and order say otherwise. Does not include file content or embeddings for performance. The response carries an ETag over the listing; send it back in If-None-Match to get 304 with no body while nothing has changed.
 //	@Tags			files
 //	@Accept			json
 //	@Produce		json
+//	@Param			sort			query	string	false	"Sort column"		Enums(created_at, filename, size)	default(created_at)
+//	@Param			order			query	string	false	"Sort direction"	Enums(asc, desc)					default(desc)
+//	@Param			If-None-Match	header	string	false	"ETag from an earlier response"
 //	@Success		200	{array}	models.FileMetadata	"List of file metadata"
+//	@Success		304	"Listing unchanged since the given ETag"
+//	@Failure		400	{object}	map[string]interface{}	"Invalid sort or order"
 //	@Failure		500	{object}	map[string]interface{}	"Failed to get metadata"
 //	@Router			/files/metadata [get]
 func GetFileMetadataHandler(q *db.Queries) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		files, err := q.GetFileMetadata(c)
+		sort, descending, errMsg := listOrder(c)
+		if errMsg != "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errMsg})
+			return
+		}
+
+		files, err := q.GetFileMetadata(c, db.GetFileMetadataParams{Sort: sort, Descending: descending})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get metadata"})
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get metadata"})
+			return
+		}
+		tag, err := jsonETag(files)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get metadata"})
+			return
+		}
+		if notModified(c, tag) {
 			return
 		}
 
-		c.JSON(http.StatusOK, files)
+		writeJSON(c, http.StatusOK, files)
 	}
 }
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// readinessTimeout bounds the database ping so readiness probes fail fast.
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler godoc
+//
+//	@Summary		Liveness probe
+//	@Description	Returns 200 as long as the process is serving requests. It does not touch the database.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}	"Process is alive"
+//	@Router			/healthz [get]
+func HealthHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writeJSON(c, http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// ReadinessHandler godoc
+//
+//	@Summary		Readiness probe
+//	@Description	Pings the database with a short timeout. Returns 503 when it is unreachable so orchestrators stop routing traffic here.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}	"Ready to serve"
+//	@Failure		503	{object}	map[string]interface{}	"Database unreachable"
+//	@Router			/readyz [get]
+func ReadinessHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c, readinessTimeout)
+		defer cancel()
+
+		if err := q.Ping(ctx); err != nil {
+			writeJSON(c, http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "database unreachable"})
+			return
+		}
+		writeJSON(c, http.StatusOK, gin.H{"status": "ready"})
+	}
+}
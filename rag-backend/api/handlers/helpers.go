@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+
+	"github.com/fain17/rag-backend/api/models"
+)
+
+// contentHash returns the hex-encoded SHA-256 of a file's content.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// contentHashText wraps contentHash for the nullable content_hash column.
+func contentHashText(content string) pgtype.Text {
+	return pgtype.Text{String: contentHash(content), Valid: true}
+}
+
+// defaultMimeType is stored when content has no more specific detectable type.
+const defaultMimeType = "text/plain"
+
+// detectMimeType sniffs content's media type, without parameters such as charset.
+func detectMimeType(content []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(content))
+	if err != nil || mediaType == "application/octet-stream" {
+		return defaultMimeType
+	}
+	return mediaType
+}
+
+// mimeTypeFilter reads the optional ?mime_type= filter shared by list and search endpoints.
+func mimeTypeFilter(c *gin.Context) pgtype.Text {
+	mimeType := c.Query("mime_type")
+	return pgtype.Text{String: mimeType, Valid: mimeType != ""}
+}
+
+// sortColumns are the columns list endpoints accept in ?sort=. The queries map
+// each to a fixed ORDER BY arm, so nothing from the request reaches the SQL text.
+var sortColumns = []string{"created_at", "filename", "size"}
+
+// listOrder reads ?sort= and ?order= for list endpoints, defaulting to newest
+// first. It returns an error message for values outside the whitelist.
+func listOrder(c *gin.Context) (sort string, descending bool, errMsg string) {
+	sort = c.DefaultQuery("sort", "created_at")
+	if !slices.Contains(sortColumns, sort) {
+		return "", false, "sort must be one of " + strings.Join(sortColumns, ", ")
+	}
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+		return sort, true, ""
+	case "asc":
+		return sort, false, ""
+	default:
+		return "", false, "order must be asc or desc"
+	}
+}
+
+const (
+	// maxTags caps how many tags one file may carry.
+	maxTags = 32
+	// maxTagLength caps the length of a single tag in bytes.
+	maxTagLength = 64
+)
+
+// normalizeTags trims tags and drops blanks and duplicates, keeping first-seen
+// order. A nil slice stays nil so updates that omit tags leave them unchanged.
+// It returns an error message when the tags exceed the limits.
+func normalizeTags(tags []string) ([]string, string) {
+	if tags == nil {
+		return nil, ""
+	}
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(out, tag) {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Sprintf("tags must be at most %d bytes", maxTagLength)
+		}
+		out = append(out, tag)
+	}
+	if len(out) > maxTags {
+		return nil, fmt.Sprintf("at most %d tags allowed", maxTags)
+	}
+	return out, ""
+}
+
+// likeEscaper escapes LIKE wildcards using Postgres's default backslash escape.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match literally inside a LIKE or ILIKE pattern.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// errDimensionMismatch is reported when an embedding's length differs from EXPECTED_EMBEDDING_DIM.
+const errDimensionMismatch = "embedding dimension mismatch"
+
+// dimensionMismatch reports whether embedding violates the configured dimension.
+// An expected dimension of 0 disables the check.
+func dimensionMismatch(embedding []float32, expected int) bool {
+	return expected > 0 && len(embedding) != expected
+}
+
+// summaryColumns returns the summary and summary embedding to store for an
+// upload or update; both are NULL when the request has no summary. It returns
+// an error message when only one of the two is sent or the embedding has the
+// wrong dimension.
+func summaryColumns(req models.FileUploadRequest, expectedDim int) (pgtype.Text, *pgvector.Vector, string) {
+	switch {
+	case req.Summary == "" && len(req.SummaryEmbedding) == 0:
+		return pgtype.Text{}, nil, ""
+	case req.Summary == "":
+		return pgtype.Text{}, nil, "summary_embedding requires a summary"
+	case len(req.SummaryEmbedding) == 0:
+		return pgtype.Text{}, nil, "summary requires a summary_embedding"
+	case dimensionMismatch(req.SummaryEmbedding, expectedDim):
+		return pgtype.Text{}, nil, errDimensionMismatch
+	}
+	vec := pgvector.NewVector(req.SummaryEmbedding)
+	return pgtype.Text{String: req.Summary, Valid: true}, &vec, ""
+}
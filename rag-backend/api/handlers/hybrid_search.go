@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	pgvector "github.com/pgvector/pgvector-go"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/embedding"
+	"github.com/fain17/rag-backend/vector"
+)
+
+const (
+	// rrfK damps reciprocal rank fusion so the top few ranks do not dominate;
+	// 60 is the constant from the original RRF paper.
+	rrfK = 60
+	// hybridOverfetch is how many candidates per requested result each search
+	// contributes, so files ranked moderately by both can still surface.
+	hybridOverfetch = 4
+	// defaultVectorWeight splits the fused score evenly between the searches.
+	defaultVectorWeight = 0.5
+)
+
+// HybridSearchHandler godoc
+//
+//	@Summary		Hybrid keyword and vector search
+//	@Description	Runs a case-insensitive substring search over filenames and content (filename matches rank first) and a similarity search on the embedding, then merges them with weighted reciprocal rank fusion: score = (1 - vector_weight) / (60 + keyword_rank) + vector_weight / (60 + vector_rank). Each result reports both contributions and ranks so callers can tune vector_weight. With normalize_scores=true, results found by the vector search also carry relevance, their distance mapped to 0-1 as in advanced search. When embedding is omitted, query is embedded server-side if an embedder is configured. When DEBUG_ENDPOINTS is enabled, debug_timing=true wraps the results with per-stage timings and whether the planner uses the vector index. Soft-deleted files are excluded.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.HybridSearchRequest	true	"Keyword query, embedding, and weighting"
+//	@Param			normalize_scores	query	bool				false	"Add a 0-1 relevance score to vector matches"
+//	@Param			debug_timing	query	bool					false	"Wrap the results with stage timings (DEBUG_ENDPOINTS only)"
+//	@Success		200		{array}		models.HybridSearchResult	"Highest fused score first"
+//	@Success		200		{object}	models.HybridSearchDebugResponse	"Highest fused score first, with timings, when debug_timing=true"
+//	@Failure		400		{object}	map[string]interface{}		"Invalid search parameters"
+//	@Failure		500		{object}	map[string]interface{}		"Search failed"
+//	@Failure		502		{object}	map[string]interface{}		"Query could not be embedded"
+//	@Router			/files/hybrid-search [post]
+func HybridSearchHandler(q *db.Queries, cfg config.EmbeddingConfig, embedder embedding.Embedder, debug bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.HybridSearchRequest
+		if err := c.BindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+
+		query := strings.TrimSpace(req.Query)
+		if query == "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "query is required"})
+			return
+		}
+		var timing models.SearchTiming
+		if len(req.Embedding) == 0 && embedder != nil {
+			start := time.Now()
+			vec, err := embedder.Embed(c, query)
+			if err != nil {
+				writeJSON(c, http.StatusBadGateway, gin.H{"error": "failed to embed query"})
+				return
+			}
+			req.Embedding = vec
+			timing.EmbeddingMs = msSince(start)
+		}
+		if len(req.Embedding) == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "embedding is required"})
+			return
+		}
+		if dimensionMismatch(req.Embedding, cfg.ExpectedDim) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errDimensionMismatch})
+			return
+		}
+
+		metric := req.Metric
+		if metric == "" {
+			metric = cfg.DefaultMetric
+		}
+		if !vector.ValidMetric(metric) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "unsupported metric"})
+			return
+		}
+
+		topK := req.TopK
+		if topK == 0 {
+			topK = defaultTopK
+		}
+		if topK < 1 || topK > maxTopK {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "top_k must be between 1 and 50"})
+			return
+		}
+
+		weight := defaultVectorWeight
+		if req.VectorWeight != nil {
+			weight = *req.VectorWeight
+		}
+		if weight < 0 || weight > 1 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "vector_weight must be between 0 and 1"})
+			return
+		}
+
+		candidates := int32(topK * hybridOverfetch)
+		start := time.Now()
+		keywordRows, err := q.SearchFilesKeyword(c, db.SearchFilesKeywordParams{
+			Query: escapeLike(query),
+			TopK:  candidates,
+		})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "keyword search failed"})
+			return
+		}
+		vectorParams := db.SearchFilesCosineParams{
+			Embedding: pgvector.NewVector(req.Embedding),
+			TopK:      candidates,
+		}
+		vectorRows, err := searchFiles(c, q, cfg, metric, vectorParams)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "vector search failed"})
+			return
+		}
+		timing.RetrievalMs = msSince(start)
+
+		start = time.Now()
+		results := fuseRanks(keywordRows, vectorRows, weight)
+		timing.RerankMs = msSince(start)
+		if c.Query("normalize_scores") == "true" {
+			for i, r := range results {
+				if r.Distance != nil {
+					relevance := vector.Relevance(metric, *r.Distance)
+					results[i].Relevance = &relevance
+				}
+			}
+		}
+		results = results[:min(len(results), topK)]
+
+		if debug && c.Query("debug_timing") == "true" {
+			if timing.IndexUsed, err = explainSearch(c, q, cfg, metric, vectorParams); err != nil {
+				writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to explain search"})
+				return
+			}
+			writeJSON(c, http.StatusOK, models.HybridSearchDebugResponse{Results: results, Timing: timing})
+			return
+		}
+		writeJSON(c, http.StatusOK, results)
+	}
+}
+
+// fuseRanks merges the keyword and vector rankings with weighted reciprocal
+// rank fusion. Files missing from one ranking get no score from it. Ties are
+// broken by created_at, then id, like the other similarity endpoints.
+func fuseRanks(keyword []db.SearchFilesKeywordRow, vectorRanked []models.SearchResult, vectorWeight float64) []models.HybridSearchResult {
+	byID := map[string]*models.HybridSearchResult{}
+	var order []string
+	result := func(id string) *models.HybridSearchResult {
+		r, ok := byID[id]
+		if !ok {
+			r = &models.HybridSearchResult{ID: id}
+			byID[id] = r
+			order = append(order, id)
+		}
+		return r
+	}
+
+	for i, row := range keyword {
+		r := result(uuid.UUID(row.ID.Bytes).String())
+		r.Filename, r.CreatedAt = row.Filename, row.CreatedAt.Time
+		r.KeywordRank = i + 1
+		r.KeywordScore = (1 - vectorWeight) / float64(rrfK+r.KeywordRank)
+	}
+	for i, row := range vectorRanked {
+		r := result(row.ID)
+		r.Filename, r.CreatedAt = row.Filename, row.CreatedAt
+		r.VectorRank = i + 1
+		r.VectorScore = vectorWeight / float64(rrfK+r.VectorRank)
+		distance := row.Distance
+		r.Distance = &distance
+	}
+
+	results := make([]models.HybridSearchResult, len(order))
+	for i, id := range order {
+		r := byID[id]
+		r.Score = r.KeywordScore + r.VectorScore
+		results[i] = *r
+	}
+	slices.SortStableFunc(results, func(a, b models.HybridSearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return results
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/auth"
+	"github.com/fain17/rag-backend/db"
+)
+
+// CreateAPIKeyHandler godoc
+//
+//	@Summary		Mint an API key
+//	@Description	Creates an API key owned by the authenticated user for use in the X-API-Key header. The key is returned only in this response; the server stores just its SHA-256 hash.
+//	@Tags			keys
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"Bearer JWT"
+//	@Success		201				{object}	models.APIKeyResponse	"The new key"
+//	@Failure		401				{object}	map[string]interface{}	"Not authenticated"
+//	@Failure		500				{object}	map[string]interface{}	"Failed to create key"
+//	@Router			/keys [post]
+func CreateAPIKeyHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, err := auth.GenerateAPIKey()
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to generate key"})
+			return
+		}
+
+		row, err := q.CreateAPIKey(c, db.CreateAPIKeyParams{
+			UserID:  auth.UserID(c),
+			KeyHash: auth.HashAPIKey(key),
+		})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to create key"})
+			return
+		}
+
+		writeJSON(c, http.StatusCreated, models.APIKeyResponse{
+			ID:        uuid.UUID(row.ID.Bytes).String(),
+			Key:       key,
+			UserID:    row.UserID,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+}
+
+// RevokeAPIKeyHandler godoc
+//
+//	@Summary		Revoke an API key
+//	@Description	Revokes one of the authenticated user's API keys; it stops authenticating immediately. Keys owned by other users, or already revoked, return 404.
+//	@Tags			keys
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"Bearer JWT"
+//	@Param			id				path		string					true	"API key ID"
+//	@Success		204				{object}	nil						"Key revoked"
+//	@Failure		400				{object}	map[string]interface{}	"Invalid UUID format"
+//	@Failure		401				{object}	map[string]interface{}	"Not authenticated"
+//	@Failure		404				{object}	map[string]interface{}	"Key not found"
+//	@Failure		500				{object}	map[string]interface{}	"Failed to revoke key"
+//	@Router			/keys/{id} [delete]
+func RevokeAPIKeyHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		revoked, err := q.RevokeAPIKey(c, db.RevokeAPIKeyParams{
+			ID:     pgtype.UUID{Bytes: parsedUUID, Valid: true},
+			UserID: auth.UserID(c),
+		})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to revoke key"})
+			return
+		}
+		if revoked == 0 {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "key not found"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
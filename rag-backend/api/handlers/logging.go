@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request correlation ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the gin context key holding the request ID.
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength bounds caller-supplied IDs so they cannot bloat log lines.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware tags each request with an ID, reusing a caller's
+// X-Request-ID when it is short and printable and generating a UUID otherwise.
+// The ID is stored in the context under RequestIDKey and echoed in the response header.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII up to maxRequestIDLength.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestLogger emits one structured line per request with its method, path,
+// status, latency, client IP, and request ID. Run it after RequestIDMiddleware.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		attrs := []any{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("request_id", c.GetString(RequestIDKey)),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		if c.Writer.Status() >= 500 {
+			level = slog.LevelError
+		}
+		logger.Log(c, level, "request", attrs...)
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+const (
+	defaultTopK = 5
+	maxTopK     = 50
+)
+
+// FileWithNeighborsHandler godoc
+//
+//	@Summary		Get a file with its nearest neighbors
+//	@Description	Returns the file plus the top_k most similar non-deleted files by cosine distance between embeddings, in one round-trip. Equal distances are ordered by created_at, then id, so results are stable.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"File UUID"
+//	@Param			top_k	query		int		false	"Number of neighbors (1-50, default 5)"
+//	@Success		200		{object}	models.FileWithNeighborsResponse{file=models.FileUploadRequest}	"File and its neighbors"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid UUID or top_k"
+//	@Failure		404		{object}	map[string]interface{}	"File not found"
+//	@Failure		500		{object}	map[string]interface{}	"Neighbor search failed"
+//	@Router			/files/{id}/with-neighbors [get]
+func FileWithNeighborsHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		topK, ok := parseTopK(c)
+		if !ok {
+			return
+		}
+
+		dbUUID := pgtype.UUID{Bytes: parsedUUID, Valid: true}
+		file, err := q.GetFile(c, dbUUID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get file"})
+			return
+		}
+
+		rows, err := q.GetNearestNeighbors(c, db.GetNearestNeighborsParams{
+			Embedding: file.Embedding,
+			ExcludeID: dbUUID,
+			TopK:      int32(topK),
+		})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "neighbor search failed"})
+			return
+		}
+
+		neighbors := make([]models.Neighbor, len(rows))
+		for i, row := range rows {
+			neighbors[i] = models.Neighbor{
+				ID:       uuid.UUID(row.ID.Bytes).String(),
+				Filename: row.Filename,
+				Distance: row.Distance,
+			}
+		}
+
+		writeJSON(c, http.StatusOK, models.FileWithNeighborsResponse{File: file, Neighbors: neighbors})
+	}
+}
+
+// parseTopK reads the top_k query parameter, writing a 400 response when it is out of range.
+func parseTopK(c *gin.Context) (int, bool) {
+	raw := c.Query("top_k")
+	if raw == "" {
+		return defaultTopK, true
+	}
+	topK, err := strconv.Atoi(raw)
+	if err != nil || topK < 1 || topK > maxTopK {
+		writeJSON(c, http.StatusBadRequest, gin.H{"error": "top_k must be between 1 and 50"})
+		return 0, false
+	}
+	return topK, true
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+const (
+	defaultRepairBatch = 500
+	maxRepairBatch     = 5000
+)
+
+// RepairContentHashesHandler godoc
+//
+//	@Summary		Backfill missing content hashes
+//	@Description	Walks files whose content_hash is NULL in id order, computing and storing the SHA-256 of each file's content. Each batch commits in its own transaction and only fills hashes that are still NULL, so the run is idempotent and can be resumed after a failure by calling it again.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			X-Admin-Token	header		string					true	"Admin token"
+//	@Param			batch_size		query		int						false	"Rows per transaction (1-5000, default 500)"
+//	@Success		200				{object}	models.RepairResponse	"Repair summary"
+//	@Failure		400				{object}	map[string]interface{}	"Invalid batch_size"
+//	@Failure		401				{object}	map[string]interface{}	"Invalid admin token"
+//	@Failure		403				{object}	map[string]interface{}	"Admin endpoints disabled"
+//	@Failure		500				{object}	map[string]interface{}	"Repair failed; rows already repaired stay repaired"
+//	@Router			/admin/repair/content-hashes [post]
+func RepairContentHashesHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		batchSize := defaultRepairBatch
+		if raw := c.Query("batch_size"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxRepairBatch {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "batch_size must be between 1 and 5000"})
+				return
+			}
+			batchSize = n
+		}
+
+		var resp models.RepairResponse
+		after := pgtype.UUID{Valid: true}
+		for {
+			var fetched int
+			var repaired int64
+			var last pgtype.UUID
+
+			err := q.ExecTx(c, func(qtx *db.Queries) error {
+				rows, err := qtx.GetFilesMissingContentHash(c, db.GetFilesMissingContentHashParams{
+					AfterID:   after,
+					BatchSize: int32(batchSize),
+				})
+				if err != nil {
+					return err
+				}
+				for _, row := range rows {
+					n, err := qtx.SetContentHash(c, db.SetContentHashParams{
+						ID:          row.ID,
+						ContentHash: contentHashText(row.Content),
+					})
+					if err != nil {
+						return err
+					}
+					repaired += n
+					last = row.ID
+				}
+				fetched = len(rows)
+				return nil
+			})
+			if err != nil {
+				writeJSON(c, http.StatusInternalServerError, gin.H{"error": "content hash repair failed", "repaired": resp.Repaired})
+				return
+			}
+
+			if fetched == 0 {
+				break
+			}
+			resp.Repaired += repaired
+			resp.Batches++
+			after = last
+			if fetched < batchSize {
+				break
+			}
+		}
+
+		remaining, err := q.CountFilesMissingContentHash(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to count remaining rows", "repaired": resp.Repaired})
+			return
+		}
+		resp.Remaining = remaining
+
+		writeJSON(c, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// prettyHeader requests indented JSON like ?pretty=true, for tools that cannot edit the query string.
+const prettyHeader = "X-Pretty-JSON"
+
+// writeJSON renders obj as compact JSON, or indented JSON when the request
+// asks for it with ?pretty=true or an X-Pretty-JSON: true header. A 500
+// reported after the request's QueryTimeout deadline passed becomes a 504,
+// since the failure was the cancelled query; other 5xx codes, such as a 502
+// from the embedder, are kept. All handlers respond through it so both rules
+// hold on every endpoint.
+func writeJSON(c *gin.Context, code int, obj any) {
+	if code == http.StatusInternalServerError && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		code, obj = http.StatusGatewayTimeout, gin.H{"error": "database query timed out"}
+	}
+	if c.Query("pretty") == "true" || c.GetHeader(prettyHeader) == "true" {
+		c.IndentedJSON(code, obj)
+		return
+	}
+	c.JSON(code, obj)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+const (
+	defaultOldestLimit = 20
+	maxOldestLimit     = 500
+)
+
+// GetOldestFilesHandler godoc
+//
+//	@Summary		List the oldest files
+//	@Description	Returns metadata for the N oldest files by created_at (ascending) with their age, for retention review. Soft-deleted files are excluded unless include_deleted=true.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit			query		int		false	"Number of files (1-500, default 20)"
+//	@Param			include_deleted	query		bool	false	"Include soft-deleted files"
+//	@Success		200				{array}		models.FileAge			"Oldest files first"
+//	@Failure		400				{object}	map[string]interface{}	"Invalid limit"
+//	@Failure		500				{object}	map[string]interface{}	"Failed to list files"
+//	@Router			/files/oldest [get]
+func GetOldestFilesHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultOldestLimit
+		if raw := c.Query("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxOldestLimit {
+				writeJSON(c, http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+				return
+			}
+			limit = n
+		}
+
+		includeDeleted := c.Query("include_deleted") == "true"
+
+		rows, err := q.GetOldestFiles(c, db.GetOldestFilesParams{
+			IncludeDeleted: includeDeleted,
+			RowLimit:       int32(limit),
+		})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to list files"})
+			return
+		}
+
+		now := time.Now()
+		files := make([]models.FileAge, len(rows))
+		for i, row := range rows {
+			files[i] = models.FileAge{
+				FileMetadata: models.FileMetadata{
+					ID:        uuid.UUID(row.ID.Bytes).String(),
+					Filename:  row.Filename,
+					Size:      int(row.Size),
+					CreatedAt: row.CreatedAt.Time,
+				},
+				AgeSeconds: int64(now.Sub(row.CreatedAt.Time).Seconds()),
+			}
+		}
+
+		writeJSON(c, http.StatusOK, files)
+	}
+}
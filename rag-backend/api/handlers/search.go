@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/embedding"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// dedupOverfetch widens the candidate pool when dedup is on so collapsing
+// duplicates still leaves top_k results in most corpora.
+const dedupOverfetch = 4
+
+// Search modes of advanced search: the content embedding or the summary embedding.
+const (
+	searchModeContent = "content"
+	searchModeSummary = "summary"
+)
+
+// AdvancedSearchHandler godoc
+//
+//	@Summary		Advanced similarity search
+//	@Description	Ranks files by distance between their embedding and the query embedding under the chosen metric, restricted in the same query by an optional filename substring and created_at range. Equal distances are ordered by created_at, then id, so results are stable across calls. Soft-deleted files are excluded unless include_deleted is set. With mode=summary, summary embeddings are searched instead of content embeddings, and only files uploaded with a summary can match. With dedup=true, files sharing a content hash are collapsed to the closest one. With normalize_scores=true, each result also carries relevance, the distance mapped to 0-1 where higher is closer: 1/(1+d) for l2, (1+cosine similarity)/2 for cosine, and 1/(1+e^d) (the logistic of the dot product) for inner. When embedding is omitted, text is embedded server-side if an embedder is configured (OPENAI_API_KEY or EMBEDDING_PROVIDER=ollama). When DEBUG_ENDPOINTS is enabled, debug_timing=true wraps the results with per-stage timings and whether the planner uses the vector index. The metadata and rerank fields are reserved and rejected with 400 until those features exist.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.AdvancedSearchRequest	true	"Query embedding and filters"
+//	@Param			mode	query		string							false	"Embedding to search: content (default) or summary"
+//	@Param			dedup	query		bool							false	"Collapse results with identical content"
+//	@Param			mime_type	query	string							false	"Only files with this MIME type (e.g., application/pdf)"
+//	@Param			normalize_scores	query	bool					false	"Add a 0-1 relevance score to each result"
+//	@Param			debug_timing	query	bool						false	"Wrap the results with stage timings (DEBUG_ENDPOINTS only)"
+//	@Success		200		{array}		models.SearchResult				"Closest files first"
+//	@Success		200		{object}	models.SearchDebugResponse		"Closest files first, with timings, when debug_timing=true"
+//	@Failure		400		{object}	map[string]interface{}			"Invalid or unsupported search parameters"
+//	@Failure		500		{object}	map[string]interface{}			"Search failed"
+//	@Failure		502		{object}	map[string]interface{}			"Query text could not be embedded"
+//	@Router			/files/search/advanced [post]
+func AdvancedSearchHandler(q *db.Queries, cfg config.EmbeddingConfig, embedder embedding.Embedder, debug bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.AdvancedSearchRequest
+		if err := c.BindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+
+		switch {
+		case req.Text != "" && embedder == nil:
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "text queries are not supported; send an embedding"})
+			return
+		case len(req.Metadata) > 0:
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "metadata filters are not supported"})
+			return
+		case req.Rerank:
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "rerank is not supported"})
+			return
+		}
+
+		var timing models.SearchTiming
+		if len(req.Embedding) == 0 && req.Text != "" {
+			start := time.Now()
+			vec, err := embedder.Embed(c, req.Text)
+			if err != nil {
+				writeJSON(c, http.StatusBadGateway, gin.H{"error": "failed to embed query"})
+				return
+			}
+			req.Embedding = vec
+			timing.EmbeddingMs = msSince(start)
+		}
+		if len(req.Embedding) == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "embedding is required"})
+			return
+		}
+		if dimensionMismatch(req.Embedding, cfg.ExpectedDim) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errDimensionMismatch})
+			return
+		}
+
+		metric := req.Metric
+		if metric == "" {
+			metric = cfg.DefaultMetric
+		}
+		if !vector.ValidMetric(metric) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "unsupported metric"})
+			return
+		}
+
+		topK := req.TopK
+		if topK == 0 {
+			topK = defaultTopK
+		}
+		if topK < 1 || topK > maxTopK {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "top_k must be between 1 and 50"})
+			return
+		}
+
+		if req.CreatedAfter != nil && req.CreatedBefore != nil && req.CreatedAfter.After(*req.CreatedBefore) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "created_after must not be later than created_before"})
+			return
+		}
+
+		mode := c.DefaultQuery("mode", searchModeContent)
+		if mode != searchModeContent && mode != searchModeSummary {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "mode must be content or summary"})
+			return
+		}
+
+		dedup := c.Query("dedup") == "true"
+		if dedup {
+			topK *= dedupOverfetch
+		}
+
+		params := db.SearchFilesCosineParams{
+			Embedding:        pgvector.NewVector(req.Embedding),
+			IncludeDeleted:   req.IncludeDeleted,
+			FilenameContains: pgtype.Text{String: escapeLike(req.FilenameContains), Valid: req.FilenameContains != ""},
+			MimeType:         mimeTypeFilter(c),
+			TopK:             int32(topK),
+		}
+		if req.CreatedAfter != nil {
+			params.CreatedAfter = pgtype.Timestamptz{Time: *req.CreatedAfter, Valid: true}
+		}
+		if req.CreatedBefore != nil {
+			params.CreatedBefore = pgtype.Timestamptz{Time: *req.CreatedBefore, Valid: true}
+		}
+
+		start := time.Now()
+		var rows []models.SearchResult
+		var err error
+		if mode == searchModeSummary {
+			rows, err = searchSummaries(c, q, metric, params)
+		} else {
+			rows, err = searchFiles(c, q, cfg, metric, params)
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "search failed"})
+			return
+		}
+		timing.RetrievalMs = msSince(start)
+		start = time.Now()
+		if dedup {
+			rows = dedupByContentHash(rows, func(r models.SearchResult) string { return r.ContentHash })
+			rows = rows[:min(len(rows), topK/dedupOverfetch)]
+		}
+		timing.RerankMs = msSince(start)
+		if c.Query("normalize_scores") == "true" {
+			for i := range rows {
+				relevance := vector.Relevance(metric, rows[i].Distance)
+				rows[i].Relevance = &relevance
+			}
+		}
+
+		if debug && c.Query("debug_timing") == "true" {
+			// summary_embedding has no vector index, so only content searches can use one.
+			if mode == searchModeContent {
+				if timing.IndexUsed, err = explainSearch(c, q, cfg, metric, params); err != nil {
+					writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to explain search"})
+					return
+				}
+			}
+			writeJSON(c, http.StatusOK, models.SearchDebugResponse{Results: rows, Timing: timing})
+			return
+		}
+		writeJSON(c, http.StatusOK, rows)
+	}
+}
+
+// msSince returns the time elapsed since start in fractional milliseconds.
+func msSince(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// explainSearch reports whether the query searchFiles would run for metric is
+// planned as a scan of the vector index.
+func explainSearch(c *gin.Context, q *db.Queries, cfg config.EmbeddingConfig, metric string, params db.SearchFilesCosineParams) (bool, error) {
+	quantizedDim := 0
+	if cfg.Quantization == db.QuantizationHalfvec && metric == cfg.DefaultMetric {
+		quantizedDim = cfg.ExpectedDim
+	}
+	return q.ExplainSearch(c, metric, quantizedDim, params)
+}
+
+// searchFiles runs the query for the metric; each metric needs its own operator
+// in the ORDER BY, and pgvector only uses the index when that operator matches
+// the index's operator class, which follows DEFAULT_METRIC. A quantized index
+// is only used through the query that casts to its halfvec type.
+func searchFiles(c *gin.Context, q *db.Queries, cfg config.EmbeddingConfig, metric string, params db.SearchFilesCosineParams) ([]models.SearchResult, error) {
+	var rows []db.SearchFilesCosineRow
+	switch {
+	case cfg.Quantization == db.QuantizationHalfvec && metric == cfg.DefaultMetric:
+		var err error
+		if rows, err = q.SearchFilesQuantized(c, metric, cfg.ExpectedDim, params); err != nil {
+			return nil, err
+		}
+	case metric == vector.MetricL2:
+		l2Rows, err := q.SearchFilesL2(c, db.SearchFilesL2Params(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range l2Rows {
+			rows = append(rows, db.SearchFilesCosineRow(r))
+		}
+	case metric == vector.MetricInner:
+		innerRows, err := q.SearchFilesInner(c, db.SearchFilesInnerParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range innerRows {
+			rows = append(rows, db.SearchFilesCosineRow(r))
+		}
+	default:
+		var err error
+		if rows, err = q.SearchFilesCosine(c, params); err != nil {
+			return nil, err
+		}
+	}
+
+	return searchResults(rows), nil
+}
+
+// searchSummaries is searchFiles over summary embeddings. They are searched
+// exactly, so there is no index or quantization to match.
+func searchSummaries(c *gin.Context, q *db.Queries, metric string, params db.SearchFilesCosineParams) ([]models.SearchResult, error) {
+	var rows []db.SearchFilesCosineRow
+	switch metric {
+	case vector.MetricL2:
+		l2Rows, err := q.SearchSummariesL2(c, db.SearchSummariesL2Params(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range l2Rows {
+			rows = append(rows, db.SearchFilesCosineRow(r))
+		}
+	case vector.MetricInner:
+		innerRows, err := q.SearchSummariesInner(c, db.SearchSummariesInnerParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range innerRows {
+			rows = append(rows, db.SearchFilesCosineRow(r))
+		}
+	default:
+		cosineRows, err := q.SearchSummariesCosine(c, db.SearchSummariesCosineParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range cosineRows {
+			rows = append(rows, db.SearchFilesCosineRow(r))
+		}
+	}
+	return searchResults(rows), nil
+}
+
+// searchResults converts search rows to the response type.
+func searchResults(rows []db.SearchFilesCosineRow) []models.SearchResult {
+	results := make([]models.SearchResult, len(rows))
+	for i, r := range rows {
+		results[i] = models.SearchResult{
+			Neighbor: models.Neighbor{
+				ID:       uuid.UUID(r.ID.Bytes).String(),
+				Filename: r.Filename,
+				Distance: r.Distance,
+			},
+			CreatedAt:   r.CreatedAt.Time,
+			ContentHash: r.ContentHash.String,
+		}
+	}
+	return results
+}
+
+// dedupByContentHash keeps the first item for each content hash, so callers
+// passing ranked results keep the best-ranked representative. Items without
+// a hash are never collapsed.
+func dedupByContentHash[T any](items []T, hash func(T) string) []T {
+	seen := make(map[string]bool, len(items))
+	out := items[:0:0]
+	for _, item := range items {
+		h := hash(item)
+		if h != "" {
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+		}
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	pgvector "github.com/pgvector/pgvector-go"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// maxSyncFiles caps how many items a single sync request may carry.
+const maxSyncFiles = 100
+
+// Sync actions reported per item.
+const (
+	syncCreated   = "created"
+	syncUpdated   = "updated"
+	syncUnchanged = "unchanged"
+	syncFailed    = "failed"
+)
+
+// SyncHandler godoc
+//
+//	@Summary		Idempotently sync a batch of files
+//	@Description	Converges stored files onto the submitted batch. Each item is matched by exact filename against the latest non-deleted file: unknown filenames are created, changed content is updated, and content with an identical SHA-256 hash is left untouched, tags included. Updated files keep their prior state as a version. Re-running the same batch is safe and reports every item as unchanged.
+//	@Tags			files
+//	@Accept			json
+//	@Produce		json
+//	@Param			files	body		models.FileSyncRequest	true	"Files to sync (max 100)"
+//	@Success		200		{array}		models.FileSyncResult	"Action taken per item"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid request body or batch size"
+//	@Router			/files/sync [post]
+func SyncHandler(q *db.Queries, cfg config.EmbeddingConfig, keepVersions int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.FileSyncRequest
+		if err := c.BindJSON(&req); err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+
+		if len(req.Files) == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "files must not be empty"})
+			return
+		}
+		if len(req.Files) > maxSyncFiles {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d files per sync", maxSyncFiles)})
+			return
+		}
+
+		results := make([]models.FileSyncResult, 0, len(req.Files))
+		var changed []string
+		for _, item := range req.Files {
+			result := syncFile(c, q, cfg, keepVersions, item)
+			if result.Action == syncCreated || result.Action == syncUpdated {
+				changed = append(changed, result.ID)
+			}
+			results = append(results, result)
+		}
+		c.Set(AuditFileIDsKey, changed)
+
+		writeJSON(c, http.StatusOK, results)
+	}
+}
+
+// syncFile applies a single sync item and reports what it did.
+func syncFile(c *gin.Context, q *db.Queries, cfg config.EmbeddingConfig, keepVersions int, item models.FileUploadRequest) models.FileSyncResult {
+	result := models.FileSyncResult{Filename: item.Filename, Action: syncFailed}
+
+	if item.Filename == "" {
+		result.Error = "filename is required"
+		return result
+	}
+	if len(item.Embedding) == 0 {
+		result.Error = "embedding is required"
+		return result
+	}
+	if dimensionMismatch(item.Embedding, cfg.ExpectedDim) {
+		result.Error = errDimensionMismatch
+		return result
+	}
+	tags, errMsg := normalizeTags(item.Tags)
+	if errMsg != "" {
+		result.Error = errMsg
+		return result
+	}
+
+	hash := contentHashText(item.Content)
+	vec := pgvector.NewVector(item.Embedding)
+	mimeType := detectMimeType([]byte(item.Content))
+
+	// The lookup and the write run in one transaction so a failure leaves no partial change.
+	err := q.ExecTx(c, func(qtx *db.Queries) error {
+		existing, err := qtx.GetLatestFileByFilename(c, item.Filename)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			created, err := qtx.CreateFile(c, db.CreateFileParams{
+				Filename:           item.Filename,
+				Content:            item.Content,
+				Embedding:          vec,
+				ContentHash:        hash,
+				MimeType:           mimeType,
+				EmbeddingTruncated: item.EmbeddingTruncated,
+				Tags:               tags,
+			})
+			if err != nil {
+				result.Error = "failed to create file"
+				return err
+			}
+			result.ID = uuid.UUID(created.ID.Bytes).String()
+			result.Action = syncCreated
+
+		case err != nil:
+			result.Error = "failed to look up file"
+			return err
+
+		case storedContentHash(existing) == hash.String:
+			result.ID = uuid.UUID(existing.ID.Bytes).String()
+			result.Action = syncUnchanged
+
+		default:
+			if err := saveVersion(c, qtx, existing.ID, keepVersions); err != nil {
+				result.Error = "failed to save version"
+				return err
+			}
+			updated, err := qtx.UpdateFile(c, db.UpdateFileParams{
+				ID:                 existing.ID,
+				Filename:           item.Filename,
+				Content:            item.Content,
+				Embedding:          vec,
+				ContentHash:        hash,
+				MimeType:           mimeType,
+				EmbeddingTruncated: item.EmbeddingTruncated,
+				Tags:               tags,
+			})
+			if err != nil {
+				result.Error = "failed to update file"
+				return err
+			}
+			result.ID = uuid.UUID(updated.ID.Bytes).String()
+			result.Action = syncUpdated
+		}
+		return nil
+	})
+	if err != nil {
+		result.ID = ""
+		result.Action = syncFailed
+		if result.Error == "" {
+			result.Error = "failed to sync file"
+		}
+	}
+
+	return result
+}
+
+// storedContentHash returns a file's hash, computing it for rows written before hashes were stored.
+func storedContentHash(file db.File) string {
+	if file.ContentHash.Valid {
+		return file.ContentHash.String
+	}
+	return contentHash(file.Content)
+}
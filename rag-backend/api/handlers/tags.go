@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// GetFilesByTagHandler godoc
+//
+//	@Summary		List files with a tag
+//	@Description	Returns summaries of non-deleted files carrying the exact tag, newest first.
+//	@Tags			files
+//	@Produce		json
+//	@Param			tag	query		string					true	"Tag to match exactly"
+//	@Success		200	{array}		models.FileSummary		"Files with the tag"
+//	@Failure		400	{object}	map[string]interface{}	"Missing tag"
+//	@Failure		500	{object}	map[string]interface{}	"Lookup failed"
+//	@Router			/files/by-tag [get]
+func GetFilesByTagHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag := strings.TrimSpace(c.Query("tag"))
+		if tag == "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "tag is required"})
+			return
+		}
+
+		rows, err := q.GetFilesByTag(c, tag)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to get files by tag"})
+			return
+		}
+
+		files := make([]models.FileSummary, len(rows))
+		for i, row := range rows {
+			files[i] = models.FileSummary{
+				FileMetadata: models.FileMetadata{
+					ID:        uuid.UUID(row.ID.Bytes).String(),
+					Filename:  row.Filename,
+					Size:      int(row.Size),
+					CreatedAt: row.CreatedAt.Time,
+				},
+				Deleted:  row.Deleted.Bool,
+				MimeType: row.MimeType,
+				Tags:     row.Tags,
+			}
+		}
+
+		writeJSON(c, http.StatusOK, files)
+	}
+}
+
+// ListTagsHandler godoc
+//
+//	@Summary		List tags with counts
+//	@Description	Returns every distinct tag on non-deleted files with how many files carry it, most used first, for building tag filters.
+//	@Tags			files
+//	@Produce		json
+//	@Success		200	{array}		models.TagCount			"Tags and file counts"
+//	@Failure		500	{object}	map[string]interface{}	"Failed to list tags"
+//	@Router			/files/tags [get]
+func ListTagsHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rows, err := q.ListTags(c)
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to list tags"})
+			return
+		}
+
+		tags := make([]models.TagCount, len(rows))
+		for i, row := range rows {
+			tags[i] = models.TagCount{Tag: row.Tag, Count: row.Count}
+		}
+
+		writeJSON(c, http.StatusOK, tags)
+	}
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryTimeout bounds each request's database work by timeout. Queries are
+// cancelled when it passes and the handler's error response becomes a 504 (see
+// writeJSON). Handlers pass the gin context to queries, so the engine must have
+// ContextWithFallback set for the deadline to reach them.
+func QueryTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
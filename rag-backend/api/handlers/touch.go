@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// TouchHandler godoc
+//
+//	@Summary		Touch a file
+//	@Description	Bumps updated_at to now without changing content or embedding. With reviewed=true, reviewed_at is set to now as well.
+//	@Tags			files
+//	@Produce		json
+//	@Param			id			path		string	true	"File UUID"
+//	@Param			reviewed	query		bool	false	"Also mark the file as reviewed"
+//	@Success		200			{object}	models.TouchResponse	"New timestamps"
+//	@Failure		400			{object}	map[string]interface{}	"Invalid UUID format"
+//	@Failure		404			{object}	map[string]interface{}	"File not found"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to touch file"
+//	@Router			/files/{id}/touch [post]
+func TouchHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		row, err := q.TouchFile(c, db.TouchFileParams{
+			Reviewed: c.Query("reviewed") == "true",
+			ID:       pgtype.UUID{Bytes: parsedUUID, Valid: true},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to touch file"})
+			return
+		}
+
+		resp := models.TouchResponse{
+			ID:        parsedUUID.String(),
+			UpdatedAt: row.UpdatedAt.Time,
+		}
+		if row.ReviewedAt.Valid {
+			resp.ReviewedAt = &row.ReviewedAt.Time
+		}
+		writeJSON(c, http.StatusOK, resp)
+	}
+}
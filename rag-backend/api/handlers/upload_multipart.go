@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	pgvector "github.com/pgvector/pgvector-go"
+
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// MultipartUploadHandler godoc
+//
+//	@Summary		Upload a file as multipart form data
+//	@Description	Stores an uploaded text file directly, without pre-serializing it into JSON. The raw file text becomes the content, the upload's base name becomes the filename, and the MIME type is detected from the bytes. Because every stored file needs a vector, the embedding form field is required and holds a JSON array of floats. Requests larger than MAX_UPLOAD_BYTES are rejected with 413.
+//	@Tags			files
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file		formData	file	true	"UTF-8 text file to store"
+//	@Param			embedding	formData	string	true	"Embedding as a JSON array of floats (e.g., [0.1, 0.2])"
+//	@Param			tags		formData	[]string	false	"Tags; repeat the field for each tag"	collectionFormat(multi)
+//	@Success		201			{object}	models.FileUploadRequest	"File created; Location holds its URL"
+//	@Header			201			{string}	Location					"/files/{id} of the created file"
+//	@Failure		400			{object}	map[string]interface{}	"Missing file, non-text content, or invalid embedding"
+//	@Failure		413			{object}	map[string]interface{}	"Upload exceeds MAX_UPLOAD_BYTES"
+//	@Failure		500			{object}	map[string]interface{}	"Failed to create file"
+//	@Router			/files/upload-multipart [post]
+func MultipartUploadHandler(q *db.Queries, cfg config.EmbeddingConfig, maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		if err := c.Request.ParseMultipartForm(maxBytes); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeJSON(c, http.StatusRequestEntityTooLarge, gin.H{"error": "upload too large"})
+				return
+			}
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid multipart form"})
+			return
+		}
+
+		upload, header, err := c.Request.FormFile("file")
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		defer upload.Close()
+
+		raw, err := io.ReadAll(upload)
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return
+		}
+		if !utf8.Valid(raw) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "file must be UTF-8 text"})
+			return
+		}
+
+		var embedding []float32
+		if err := json.Unmarshal([]byte(c.Request.FormValue("embedding")), &embedding); err != nil || len(embedding) == 0 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "embedding must be a non-empty JSON array"})
+			return
+		}
+		if dimensionMismatch(embedding, cfg.ExpectedDim) {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errDimensionMismatch})
+			return
+		}
+
+		tags, errMsg := normalizeTags(c.Request.MultipartForm.Value["tags"])
+		if errMsg != "" {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": errMsg})
+			return
+		}
+
+		content := string(raw)
+		file, err := q.CreateFile(c, db.CreateFileParams{
+			Filename:    filepath.Base(header.Filename),
+			Content:     content,
+			Embedding:   pgvector.NewVector(embedding),
+			ContentHash: contentHashText(content),
+			MimeType:    detectMimeType(raw),
+			Tags:        tags,
+		})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to create file"})
+			return
+		}
+		writeCreated(c, file)
+	}
+}
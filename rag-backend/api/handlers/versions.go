@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// saveVersion snapshots the file's current state before it is overwritten and
+// drops snapshots beyond the newest keep; keep 0 retains them all. It returns
+// pgx.ErrNoRows when the file does not exist.
+func saveVersion(ctx context.Context, qtx *db.Queries, fileID pgtype.UUID, keep int) error {
+	if _, err := qtx.CreateFileVersion(ctx, fileID); err != nil {
+		return err
+	}
+	if keep == 0 {
+		return nil
+	}
+	_, err := qtx.PruneFileVersions(ctx, db.PruneFileVersionsParams{FileID: fileID, Keep: int32(keep)})
+	return err
+}
+
+// ListFileVersionsHandler godoc
+//
+//	@Summary		List a file's prior versions
+//	@Description	Returns the snapshots taken before each update or restore, newest first, without content or embeddings. Only the newest FILE_VERSIONS_MAX are kept. A file that was never updated, or does not exist, has no versions.
+//	@Tags			files
+//	@Produce		json
+//	@Param			id	path		string						true	"File UUID"
+//	@Success		200	{array}		models.FileVersionSummary	"Prior versions"
+//	@Failure		400	{object}	map[string]interface{}		"Invalid UUID format"
+//	@Failure		500	{object}	map[string]interface{}		"Failed to list versions"
+//	@Router			/files/{id}/versions [get]
+func ListFileVersionsHandler(q *db.Queries) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		rows, err := q.ListFileVersions(c, pgtype.UUID{Bytes: parsedUUID, Valid: true})
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to list versions"})
+			return
+		}
+
+		versions := make([]models.FileVersionSummary, len(rows))
+		for i, row := range rows {
+			versions[i] = models.FileVersionSummary{
+				Version:     int(row.Version),
+				Filename:    row.Filename,
+				Size:        int(row.Size),
+				ContentHash: row.ContentHash.String,
+				MimeType:    row.MimeType,
+				Tags:        row.Tags,
+				CreatedAt:   row.CreatedAt.Time,
+			}
+		}
+
+		writeJSON(c, http.StatusOK, versions)
+	}
+}
+
+// RestoreFileVersionHandler godoc
+//
+//	@Summary		Restore a prior version of a file
+//	@Description	Rolls the file's filename, content, embedding, and tags back to the given version. The state being replaced is itself saved as a new version, so a restore can be undone.
+//	@Tags			files
+//	@Produce		json
+//	@Param			id		path		string					true	"File UUID"
+//	@Param			version	path		int						true	"Version number from the versions list"
+//	@Success		200		{object}	map[string]interface{}	"Restored file"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid UUID or version"
+//	@Failure		404		{object}	map[string]interface{}	"Version not found"
+//	@Failure		500		{object}	map[string]interface{}	"Failed to restore version"
+//	@Router			/files/{id}/versions/{version}/restore [post]
+func RestoreFileVersionHandler(q *db.Queries, keepVersions int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parsedUUID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			writeJSON(c, http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+			return
+		}
+
+		id := pgtype.UUID{Bytes: parsedUUID, Valid: true}
+		var restored db.File
+		err = q.ExecTx(c, func(qtx *db.Queries) error {
+			old, err := qtx.GetFileVersion(c, db.GetFileVersionParams{FileID: id, Version: int32(version)})
+			if err != nil {
+				return err
+			}
+			if err := saveVersion(c, qtx, id, keepVersions); err != nil {
+				return err
+			}
+			restored, err = qtx.UpdateFile(c, db.UpdateFileParams{
+				ID:                 id,
+				Filename:           old.Filename,
+				Content:            old.Content,
+				Embedding:          old.Embedding,
+				ContentHash:        old.ContentHash,
+				MimeType:           old.MimeType,
+				EmbeddingTruncated: old.EmbeddingTruncated,
+				Tags:               old.Tags,
+			})
+			return err
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(c, http.StatusNotFound, gin.H{"error": "version not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(c, http.StatusInternalServerError, gin.H{"error": "failed to restore version"})
+			return
+		}
+
+		writeJSON(c, http.StatusOK, restored)
+	}
+}
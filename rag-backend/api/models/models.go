@@ -7,8 +7,18 @@ type FileUploadRequest struct {
 	Filename  string    `json:"filename"`
 	Content   string    `json:"content"`
 	Embedding []float32 `json:"embedding"`
-	CreatedAt time.Time `json:"created_at"`
-	Deleted   bool      `json:"deleted"`
+	// Tags label the file for filtering; on update, omitting tags keeps the current ones.
+	Tags []string `json:"tags,omitempty" example:"finance,2026"`
+	// EmbeddingTruncated records that the embedding was computed from trimmed content.
+	EmbeddingTruncated bool `json:"embedding_truncated,omitempty"`
+	// Summary is an optional short description of the content, searched with ?mode=summary.
+	Summary string `json:"summary,omitempty"`
+	// SummaryEmbedding is the embedding of Summary, required with it unless the server embeds it.
+	SummaryEmbedding []float32 `json:"summary_embedding,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	// Deleted is derived from DeletedAt and kept for compatibility.
+	Deleted   bool       `json:"deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // FileMetadata represents lightweight file information without content or embeddings
@@ -19,3 +29,381 @@ type FileMetadata struct {
 	Size      int       `json:"size"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// FileSummary is the default /files/getall entry: metadata plus the soft-delete flag
+// @Description File listing entry without content or embedding
+type FileSummary struct {
+	FileMetadata
+	Deleted  bool     `json:"deleted"`
+	MimeType string   `json:"mime_type" example:"text/plain"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// EmbeddingConfigResponse describes the embeddings the server accepts
+// @Description Embedding settings clients should match when computing vectors
+type EmbeddingConfigResponse struct {
+	ExpectedDimension int      `json:"expected_dimension,omitempty"`
+	Models            []string `json:"models"`
+	Providers         []string `json:"providers"`
+	DefaultMetric     string   `json:"default_metric"`
+}
+
+// MetricInfo describes a supported similarity metric
+// @Description Metric name, pgvector operator, and how its distances rank
+type MetricInfo struct {
+	Name        string `json:"name" example:"cosine"`
+	Operator    string `json:"operator" example:"<=>"`
+	Better      string `json:"better" example:"lower" enums:"lower,higher"`
+	Description string `json:"description"`
+}
+
+// DebugParseResponse echoes how an upload body was interpreted, without the full embedding
+// @Description Parsed view of a FileUploadRequest with validation warnings
+type DebugParseResponse struct {
+	Filename        string    `json:"filename"`
+	ContentLength   int       `json:"content_length"`
+	EmbeddingLength int       `json:"embedding_length"`
+	CreatedAt       time.Time `json:"created_at"`
+	Deleted         bool      `json:"deleted"`
+	Warnings        []string  `json:"warnings"`
+}
+
+// FileSyncRequest is a batch of files to converge onto the stored corpus
+// @Description Files to create, update, or leave unchanged, matched by filename
+type FileSyncRequest struct {
+	Files []FileUploadRequest `json:"files"`
+}
+
+// FileSyncResult reports the action taken for one item of a sync request
+// @Description Per-item sync outcome: created, updated, unchanged, or failed
+type FileSyncResult struct {
+	Filename string `json:"filename"`
+	ID       string `json:"id,omitempty"`
+	Action   string `json:"action"`
+	Error    string `json:"error,omitempty"`
+}
+
+// DistanceMatrixRequest selects the files whose pairwise distances are computed
+// @Description File IDs (max 100) and the metric to compare their embeddings with
+type DistanceMatrixRequest struct {
+	IDs    []string `json:"ids"`
+	Metric string   `json:"metric"`
+}
+
+// DistanceMatrixResponse holds the NxN distances between the requested files
+// @Description Matrix[i][j] is the distance between IDs[i] and IDs[j]
+type DistanceMatrixResponse struct {
+	IDs    []string    `json:"ids"`
+	Metric string      `json:"metric"`
+	Matrix [][]float64 `json:"matrix"`
+}
+
+// Neighbor is a file close to an anchor file in embedding space
+// @Description Nearby file with its cosine distance to the anchor (lower is closer)
+type Neighbor struct {
+	ID       string  `json:"id"`
+	Filename string  `json:"filename"`
+	Distance float64 `json:"distance"`
+}
+
+// FileWithNeighborsResponse bundles a file with its nearest neighbors
+// @Description A file and the files most similar to it
+type FileWithNeighborsResponse struct {
+	File      interface{} `json:"file"`
+	Neighbors []Neighbor  `json:"neighbors"`
+}
+
+// FileAge is file metadata annotated with how long ago the file was created
+// @Description Lightweight file metadata plus age in seconds, for retention review
+type FileAge struct {
+	FileMetadata
+	AgeSeconds int64 `json:"age_seconds"`
+}
+
+// EmbeddingDimensionCount is one bucket of the embedding dimension histogram
+// @Description Number of files whose embedding has the given dimension
+type EmbeddingDimensionCount struct {
+	Dimension int   `json:"dimension"`
+	Count     int64 `json:"count"`
+}
+
+// EmbeddingDimensionsResponse reports how stored embeddings are distributed by dimension
+// @Description Embedding dimension histogram for spotting wrong-dimension rows
+type EmbeddingDimensionsResponse struct {
+	ExpectedDimension int                       `json:"expected_dimension,omitempty"`
+	Dimensions        []EmbeddingDimensionCount `json:"dimensions"`
+	Mixed             bool                      `json:"mixed"`
+}
+
+// EmbeddingStats summarises a stored embedding without returning it
+// @Description Per-vector statistics for diagnosing embedding quality
+type EmbeddingStats struct {
+	ID        string  `json:"id"`
+	Dimension int     `json:"dimension"`
+	Norm      float64 `json:"norm"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Mean      float64 `json:"mean"`
+	Zeros     int     `json:"zeros"`
+}
+
+// EmbeddingUpdateRequest replaces a file's embedding
+// @Description New vector for an existing file; filename and content are left untouched
+type EmbeddingUpdateRequest struct {
+	// Embedding must have the same dimension as the stored vector; required.
+	Embedding []float32 `json:"embedding"`
+	// EmbeddingTruncated records that the new embedding was computed from trimmed content.
+	EmbeddingTruncated bool `json:"embedding_truncated,omitempty"`
+}
+
+// EmbeddingUpdateResponse confirms an embedding replacement
+// @Description Updated file ID, vector dimension, and new updated_at
+type EmbeddingUpdateResponse struct {
+	ID        string    `json:"id"`
+	Dimension int       `json:"dimension"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AdvancedSearchRequest composes vector similarity with filename and date filters in one query
+// @Description Similarity search over stored embeddings with optional filters. text is embedded server-side when embedding is omitted and an embedder is configured. metadata and rerank are reserved and currently rejected.
+type AdvancedSearchRequest struct {
+	// Embedding is the query vector; required unless text is embedded server-side.
+	Embedding []float32 `json:"embedding"`
+	// Metric is l2, cosine, or inner; defaults to DEFAULT_METRIC.
+	Metric string `json:"metric,omitempty"`
+	// TopK is the number of results (1-50, default 5).
+	TopK int `json:"top_k,omitempty"`
+	// FilenameContains keeps files whose name contains this case-insensitive substring.
+	FilenameContains string `json:"filename_contains,omitempty"`
+	// CreatedAfter and CreatedBefore bound created_at inclusively (RFC 3339).
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	// IncludeDeleted also searches soft-deleted files.
+	IncludeDeleted bool `json:"include_deleted,omitempty"`
+
+	// Text is embedded server-side when embedding is omitted; needs a configured embedder.
+	Text string `json:"text,omitempty"`
+	// Metadata would filter on file metadata; not supported yet.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Rerank would rerank candidates; not supported yet.
+	Rerank bool `json:"rerank,omitempty"`
+}
+
+// SearchResult is one advanced search hit
+// @Description Matching file with its distance to the query embedding under the requested metric (lower is closer)
+type SearchResult struct {
+	Neighbor
+	// Relevance is the distance mapped to 0-1 (higher is closer); only set with ?normalize_scores=true.
+	Relevance   *float64  `json:"relevance,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ContentHash string    `json:"content_hash,omitempty"`
+}
+
+// HybridSearchRequest combines a keyword query with a query embedding
+// @Description Keyword (filename/content substring) and vector search fused by reciprocal rank
+type HybridSearchRequest struct {
+	// Query is matched case-insensitively against filenames and content; required.
+	Query string `json:"query" example:"invoice"`
+	// Embedding is the query vector; when omitted, query is embedded server-side if an embedder is configured.
+	Embedding []float32 `json:"embedding"`
+	// VectorWeight is the share of the fused score taken from the vector ranking (0-1, default 0.5).
+	VectorWeight *float64 `json:"vector_weight,omitempty" example:"0.5"`
+	// Metric is l2, cosine, or inner; defaults to DEFAULT_METRIC.
+	Metric string `json:"metric,omitempty"`
+	// TopK is the number of results (1-50, default 5).
+	TopK int `json:"top_k,omitempty"`
+}
+
+// HybridSearchResult is one fused hit with each ranking's contribution
+// @Description score = keyword_score + vector_score; ranks are 1-based and omitted when that search did not return the file
+type HybridSearchResult struct {
+	ID           string    `json:"id"`
+	Filename     string    `json:"filename"`
+	CreatedAt    time.Time `json:"created_at"`
+	Score        float64   `json:"score"`
+	KeywordScore float64   `json:"keyword_score"`
+	VectorScore  float64   `json:"vector_score"`
+	KeywordRank  int       `json:"keyword_rank,omitempty"`
+	VectorRank   int       `json:"vector_rank,omitempty"`
+	Distance     *float64  `json:"distance,omitempty"`
+	// Relevance is Distance mapped to 0-1 (higher is closer); only set with ?normalize_scores=true.
+	Relevance *float64 `json:"relevance,omitempty"`
+}
+
+// SearchTiming breaks down where a search spent its time
+// @Description Per-stage durations in milliseconds and whether the vector index served the search; only returned with ?debug_timing=true when DEBUG_ENDPOINTS is enabled
+type SearchTiming struct {
+	// EmbeddingMs is the time spent embedding query text server-side; 0 when the request carried an embedding.
+	EmbeddingMs float64 `json:"embedding_ms"`
+	// RetrievalMs is the time spent in the search queries.
+	RetrievalMs float64 `json:"retrieval_ms"`
+	// RerankMs is the time spent reordering the retrieved rows: dedup for advanced search, rank fusion for hybrid search.
+	RerankMs float64 `json:"rerank_ms"`
+	// IndexUsed reports whether the planner serves the similarity query from the vector index, per EXPLAIN.
+	IndexUsed bool `json:"index_used"`
+}
+
+// SearchDebugResponse is an advanced search result list with its timing
+// @Description Returned instead of the plain result array when ?debug_timing=true and DEBUG_ENDPOINTS is enabled
+type SearchDebugResponse struct {
+	Results []SearchResult `json:"results"`
+	Timing  SearchTiming   `json:"timing"`
+}
+
+// HybridSearchDebugResponse is a hybrid search result list with its timing
+// @Description Returned instead of the plain result array when ?debug_timing=true and DEBUG_ENDPOINTS is enabled
+type HybridSearchDebugResponse struct {
+	Results []HybridSearchResult `json:"results"`
+	Timing  SearchTiming         `json:"timing"`
+}
+
+// ColumnSchema describes a column of the files table
+// @Description Column name, Postgres type, and nullability
+type ColumnSchema struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// IndexSchema describes an index on the files table
+// @Description Index name and its CREATE INDEX definition
+type IndexSchema struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// SchemaResponse is the introspected shape of the files table
+// @Description Columns, embedding dimension, and indexes of the files table
+type SchemaResponse struct {
+	Table              string         `json:"table"`
+	Columns            []ColumnSchema `json:"columns"`
+	EmbeddingDimension int            `json:"embedding_dimension"`
+	Indexes            []IndexSchema  `json:"indexes"`
+}
+
+// StorageResponse breaks down how much space the files table uses
+// @Description Embedding and content payload sizes plus the table's total on-disk size
+type StorageResponse struct {
+	Files          int64 `json:"files"`
+	EmbeddingBytes int64 `json:"embedding_bytes"`
+	ContentBytes   int64 `json:"content_bytes"`
+	TotalBytes     int64 `json:"total_bytes"`
+}
+
+// FileVersionSummary describes one saved prior version of a file
+// @Description Snapshot taken before an update or restore, without content or embedding
+type FileVersionSummary struct {
+	Version     int       `json:"version"`
+	Filename    string    `json:"filename"`
+	Size        int       `json:"size"`
+	ContentHash string    `json:"content_hash,omitempty"`
+	MimeType    string    `json:"mime_type"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DuplicateGroup is a set of files sharing identical content
+// @Description Content hash shared by more than one non-deleted file, with their IDs oldest first
+type DuplicateGroup struct {
+	ContentHash string   `json:"content_hash"`
+	Count       int64    `json:"count"`
+	IDs         []string `json:"ids"`
+}
+
+// AuditEntry is one recorded mutating operation
+// @Description Who called which route on which file, and whether it succeeded
+type AuditEntry struct {
+	ID int64 `json:"id"`
+	// UserID is empty when the route was called without authentication.
+	UserID string `json:"user_id,omitempty"`
+	// Action is the HTTP method and route, e.g. "PUT /files/:id".
+	Action string `json:"action"`
+	FileID string `json:"file_id,omitempty"`
+	Status int    `json:"status"`
+	// Success is false for any status of 400 or above.
+	Success   bool      `json:"success"`
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TouchResponse reports the timestamps set by a touch
+// @Description New updated_at, and reviewed_at when set
+type TouchResponse struct {
+	ID         string     `json:"id"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// CloneRequest optionally names the copy made by a clone
+// @Description Filename for the copy; defaults to "Copy of <source filename>"
+type CloneRequest struct {
+	Filename string `json:"filename" example:"report-v2.txt"`
+}
+
+// ExistsBatchRequest lists identifiers to check before an import
+// @Description Content hashes (hex SHA-256) and/or filenames to look up
+type ExistsBatchRequest struct {
+	Hashes    []string `json:"hashes"`
+	Filenames []string `json:"filenames"`
+}
+
+// ExistsResult splits identifiers into those already stored and those not
+// @Description existing maps each found identifier to the IDs of matching files
+type ExistsResult struct {
+	Existing map[string][]string `json:"existing"`
+	Missing  []string            `json:"missing"`
+}
+
+// ExistsBatchResponse reports existence separately for hashes and filenames
+// @Description Which hashes and filenames already exist among non-deleted files
+type ExistsBatchResponse struct {
+	Hashes    ExistsResult `json:"hashes"`
+	Filenames ExistsResult `json:"filenames"`
+}
+
+// RepairResponse reports the outcome of a content hash repair run
+// @Description Number of rows repaired, batches committed, and rows still missing a hash
+type RepairResponse struct {
+	Repaired  int64 `json:"repaired"`
+	Batches   int   `json:"batches"`
+	Remaining int64 `json:"remaining"`
+}
+
+// PurgeResponse reports how many files were permanently removed
+// @Description Number of soft-deleted files purged from the recycle bin. On a dry run, purged is how many would be removed and sample lists some of them.
+type PurgeResponse struct {
+	Purged int64          `json:"purged"`
+	DryRun bool           `json:"dry_run,omitempty"`
+	Sample []FileMetadata `json:"sample,omitempty"`
+}
+
+// APIKeyResponse is returned once when a key is minted
+// @Description The plaintext key is shown only here; store it securely
+type APIKeyResponse struct {
+	ID        string    `json:"id"`
+	Key       string    `json:"key" example:"rag_3f9c..."`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BulkDeleteRequest lists the files to delete in one call
+// @Description File UUIDs to delete; soft moves them to the recycle bin instead
+type BulkDeleteRequest struct {
+	IDs  []string `json:"ids" binding:"required"`
+	Soft bool     `json:"soft"`
+}
+
+// BulkDeleteResponse reports which requested files were deleted
+// @Description IDs deleted and IDs that matched no file
+type BulkDeleteResponse struct {
+	Deleted  []string `json:"deleted"`
+	NotFound []string `json:"not_found"`
+	Soft     bool     `json:"soft"`
+}
+
+// TagCount is one tag with how many files carry it
+// @Description Distinct tag across non-deleted files and its file count
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
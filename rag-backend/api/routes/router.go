@@ -1,6 +1,11 @@
 package routes
 
 import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+
 	_ "github.com/fain17/rag-backend/docs"
 
 	"github.com/gin-gonic/gin"
@@ -8,30 +13,123 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	handlers "github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/auth"
+	"github.com/fain17/rag-backend/config"
 	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/embedding"
 )
 
-func NewRouter(queries *db.Queries) *gin.Engine {
-	r := gin.Default()
+// NewRouter builds the API. embedder may be nil, in which case uploads must carry their own embeddings.
+// Background checks the router starts run until workers is cancelled.
+func NewRouter(workers context.Context, queries *db.Queries, cfg config.Config, embedder embedding.Embedder) *gin.Engine {
+	r := gin.New()
+	// Handlers pass *gin.Context to queries; this makes its deadline and
+	// cancellation those of the request, so QueryTimeout reaches the database.
+	r.ContextWithFallback = true
+
+	// Structured access logs, one JSON line per request, correlated by X-Request-ID
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	r.Use(gin.Recovery(), handlers.RequestIDMiddleware(), handlers.RequestLogger(logger))
+
+	// No request body may exceed MAX_BODY_BYTES, whatever route it is sent to
+	r.Use(handlers.MaxBodyBytes(cfg.MaxBodyBytes))
+
+	// Database work is cancelled after DB_QUERY_TIMEOUT and answered with 504
+	if cfg.DBQueryTimeout > 0 {
+		r.Use(handlers.QueryTimeout(cfg.DBQueryTimeout))
+	}
 	r.SetTrustedProxies([]string{"127.0.0.1"})
 
 	//Swagger Routes
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Probes for container orchestration
+	r.GET("/healthz", handlers.HealthHandler())
+	r.GET("/readyz", handlers.ReadinessHandler(queries))
+
+	// Public discovery routes
+	configGroup := r.Group("/config")
+	configGroup.GET("/embeddings", handlers.EmbeddingConfigHandler(cfg.Embedding))
+	configGroup.GET("/metrics", handlers.MetricsHandler())
+
 	fileGroup := r.Group("/files")
+	if cfg.JWTSecret != "" {
+		fileGroup.Use(auth.JWTOrAPIKeyMiddleware(cfg.JWTSecret, queries))
+	} else {
+		log.Println("WARNING: JWT_SECRET is not set; /files routes are unauthenticated")
+	}
+
+	// Write routes accept gzip-compressed bodies. MAX_BODY_BYTES only sees the
+	// compressed bytes, so it also caps what a body may inflate to.
+	inflateLimit := cfg.MaxDecompressedBytes
+	if cfg.MaxBodyBytes > 0 && cfg.MaxBodyBytes < inflateLimit {
+		inflateLimit = cfg.MaxBodyBytes
+	}
+	decompress := handlers.DecompressBody(inflateLimit)
+
+	// Writes that grow the table are rejected with 507 while the database is over MAX_DB_BYTES
+	capacity := handlers.NewCapacityGuard(queries, cfg.MaxDBBytes)
+	if cfg.MaxDBBytes > 0 {
+		go capacity.Run(workers, cfg.CapacityCheckInterval)
+	}
+	guard := capacity.RejectWhenFull()
+
+	// Routes that change files are recorded in audit_log, failures included
+	audit := handlers.AuditMiddleware(queries, logger)
 
 	// CRUD + search routes
-	fileGroup.POST("/upload", handlers.UploadHandler(queries))
+	fileGroup.POST("/upload", audit, guard, decompress, handlers.UploadHandler(queries, cfg.Embedding, embedder))
+	fileGroup.POST("/upload-multipart", audit, guard, decompress, handlers.MultipartUploadHandler(queries, cfg.Embedding, cfg.MaxUploadBytes))
+	fileGroup.POST("/sync", audit, guard, decompress, handlers.SyncHandler(queries, cfg.Embedding, cfg.MaxFileVersions))
+	fileGroup.POST("/exists/batch", handlers.ExistsBatchHandler(queries))
+	fileGroup.POST("/bulk-delete", audit, handlers.BulkDeleteHandler(queries))
 	fileGroup.GET("/getall", handlers.GetAllHandler(queries))
 	fileGroup.GET("/search", handlers.GetFilesByFilenameHandler(queries))
+	fileGroup.POST("/search/advanced", handlers.AdvancedSearchHandler(queries, cfg.Embedding, embedder, cfg.Debug))
+	fileGroup.POST("/hybrid-search", handlers.HybridSearchHandler(queries, cfg.Embedding, embedder, cfg.Debug))
 	fileGroup.GET("/date-range", handlers.GetFilesByDateRangeHandler(queries))
+	fileGroup.GET("/by-tag", handlers.GetFilesByTagHandler(queries))
+	fileGroup.GET("/tags", handlers.ListTagsHandler(queries))
+	fileGroup.GET("/duplicates", handlers.DuplicatesHandler(queries))
+	fileGroup.POST("/distance-matrix", handlers.DistanceMatrixHandler(queries, cfg.Embedding))
 	fileGroup.GET("/:id", handlers.GetHandler(queries))
-	fileGroup.PUT("/:id", handlers.UpdateHandler(queries))
-	fileGroup.DELETE("/:id", handlers.DeleteHandler(queries))
-	fileGroup.PATCH("/:id/soft-delete", handlers.SoftDeleteHandler(queries))
-	fileGroup.PATCH("/:id/restore", handlers.UndoSoftDeleteHandler(queries))
+	fileGroup.GET("/:id/with-neighbors", handlers.FileWithNeighborsHandler(queries))
+	fileGroup.GET("/:id/content", handlers.GetContentHandler(queries))
+	fileGroup.GET("/:id/embedding/stats", handlers.EmbeddingStatsHandler(queries))
+	fileGroup.POST("/:id/clone", audit, guard, handlers.CloneHandler(queries))
+	fileGroup.POST("/:id/touch", audit, handlers.TouchHandler(queries))
+	fileGroup.PUT("/:id", audit, guard, decompress, handlers.UpdateHandler(queries, cfg.Embedding, cfg.MaxFileVersions))
+	fileGroup.GET("/:id/versions", handlers.ListFileVersionsHandler(queries))
+	fileGroup.POST("/:id/versions/:version/restore", audit, guard, handlers.RestoreFileVersionHandler(queries, cfg.MaxFileVersions))
+	fileGroup.DELETE("/:id", audit, handlers.DeleteHandler(queries))
+	fileGroup.PATCH("/:id/embedding", audit, decompress, handlers.UpdateEmbeddingHandler(queries, cfg.MaxFileVersions))
+	fileGroup.PATCH("/:id/soft-delete", audit, handlers.SoftDeleteHandler(queries))
+	fileGroup.PATCH("/:id/restore", audit, handlers.UndoSoftDeleteHandler(queries))
 	fileGroup.GET("/recycle-bin", handlers.GetDeletedFilesHandler(queries))
+	fileGroup.DELETE("/recycle-bin", audit, handlers.EmptyRecycleBinHandler(queries))
+	fileGroup.DELETE("/:id/purge", audit, handlers.PurgeFileHandler(queries))
 	fileGroup.GET("/metadata", handlers.GetFileMetadataHandler(queries))
+	fileGroup.GET("/oldest", handlers.GetOldestFilesHandler(queries))
+
+	// API keys are minted and revoked by JWT-authenticated users for their own use
+	keyGroup := r.Group("/keys", auth.JWTMiddleware(cfg.JWTSecret))
+	keyGroup.POST("", handlers.CreateAPIKeyHandler(queries))
+	keyGroup.DELETE("/:id", handlers.RevokeAPIKeyHandler(queries))
+
+	// Operator routes, guarded by ADMIN_TOKEN
+	adminGroup := r.Group("/admin", handlers.RequireAdmin(cfg.AdminToken))
+	adminGroup.GET("/embedding-dimensions", handlers.EmbeddingDimensionsHandler(queries, cfg.Embedding.ExpectedDim))
+	adminGroup.GET("/schema", handlers.SchemaHandler(queries))
+	adminGroup.GET("/storage", handlers.StorageHandler(queries))
+	adminGroup.POST("/repair/content-hashes", handlers.RepairContentHashesHandler(queries))
+
+	// The audit trail is for operators only
+	r.GET("/audit", handlers.RequireAdmin(cfg.AdminToken), handlers.ListAuditHandler(queries))
+
+	// Diagnostic routes, only registered when DEBUG_ENDPOINTS is enabled
+	if cfg.Debug {
+		fileGroup.POST("/debug-parse", handlers.DebugParseHandler(cfg.Embedding))
+	}
 
 	return r
 }
@@ -0,0 +1,278 @@
+// Package config loads runtime settings from environment variables.
+package config
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// Config holds all settings the API reads at startup.
+type Config struct {
+	// Addr is the host:port the server listens on, from HOST and PORT.
+	Addr      string
+	Embedding EmbeddingConfig
+	// Debug enables diagnostic endpoints such as /files/debug-parse.
+	Debug bool
+	// AdminToken guards the /admin routes; when empty they are disabled.
+	AdminToken string
+	// JWTSecret is the HS256 key for bearer tokens on /files and /keys; when empty /files is unauthenticated and /keys is disabled.
+	JWTSecret string
+	// VectorIndex is the ANN index ensured on files.embedding at startup.
+	VectorIndex db.VectorIndex
+	// Pool sizes the database connection pool.
+	Pool db.PoolConfig
+	// DBConnect bounds how long startup waits for the database.
+	DBConnect db.ConnectRetry
+	// MaxBodyBytes caps the size of any request body; 0 disables the check.
+	MaxBodyBytes int64
+	// MaxUploadBytes caps the size of a multipart upload request.
+	MaxUploadBytes int64
+	// MaxDecompressedBytes caps how far a gzip request body may inflate.
+	MaxDecompressedBytes int64
+	// MaxDBBytes is the database size at which writes are rejected; 0 disables the check.
+	MaxDBBytes int64
+	// CapacityCheckInterval is how often the database size is measured.
+	CapacityCheckInterval time.Duration
+	// DBQueryTimeout bounds the database work of each request.
+	DBQueryTimeout time.Duration
+	// RecycleBinTTL is how long soft-deleted files are kept before being purged; 0 keeps them forever.
+	RecycleBinTTL time.Duration
+	// MaxFileVersions is how many prior versions are kept per file; 0 keeps them all.
+	MaxFileVersions int
+}
+
+// EmbeddingConfig describes the embeddings the server expects clients to send.
+type EmbeddingConfig struct {
+	// ExpectedDim is the required embedding length; 0 disables the check.
+	ExpectedDim   int
+	Provider      string
+	Model         string
+	Models        []string
+	DefaultMetric string
+	// OpenAIAPIKey enables server-side embedding of uploads that omit one.
+	OpenAIAPIKey string
+	// OllamaURL is the embeddings endpoint used when Provider is "ollama".
+	OllamaURL string
+	// Quantization is the precision of the vector index, db.QuantizationNone
+	// or db.QuantizationHalfvec; searches on DefaultMetric query it to match.
+	Quantization string
+}
+
+// Load reads the configuration from the environment, applying defaults for unset values.
+func Load() (Config, error) {
+	var cfg Config
+	var err error
+
+	port, err := getEnvInt("PORT", 8080)
+	if err != nil {
+		return cfg, err
+	}
+	if port < 1 || port > 65535 {
+		return cfg, fmt.Errorf("PORT must be between 1 and 65535, got %d", port)
+	}
+	cfg.Addr = net.JoinHostPort(os.Getenv("HOST"), strconv.Itoa(port))
+
+	if cfg.Embedding.ExpectedDim, err = getEnvInt("EXPECTED_EMBEDDING_DIM", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.Embedding.ExpectedDim < 0 {
+		return cfg, fmt.Errorf("EXPECTED_EMBEDDING_DIM must not be negative, got %d", cfg.Embedding.ExpectedDim)
+	}
+
+	cfg.Embedding.Provider = os.Getenv("EMBEDDING_PROVIDER")
+	cfg.Embedding.Model = os.Getenv("EMBEDDING_MODEL")
+	cfg.Embedding.OpenAIAPIKey = os.Getenv("OPENAI_API_KEY")
+	cfg.Embedding.OllamaURL = os.Getenv("OLLAMA_URL")
+	cfg.Embedding.Models = getEnvList("EMBEDDING_MODELS")
+	if len(cfg.Embedding.Models) == 0 && cfg.Embedding.Model != "" {
+		cfg.Embedding.Models = []string{cfg.Embedding.Model}
+	}
+	cfg.Embedding.DefaultMetric = getEnv("DEFAULT_METRIC", vector.MetricCosine)
+	if !vector.ValidMetric(cfg.Embedding.DefaultMetric) {
+		return cfg, fmt.Errorf("unsupported DEFAULT_METRIC %q", cfg.Embedding.DefaultMetric)
+	}
+
+	if cfg.Debug, err = getEnvBool("DEBUG_ENDPOINTS", false); err != nil {
+		return cfg, err
+	}
+
+	cfg.AdminToken = os.Getenv("ADMIN_TOKEN")
+	cfg.JWTSecret = os.Getenv("JWT_SECRET")
+
+	cfg.VectorIndex.Type = getEnv("VECTOR_INDEX_TYPE", db.IndexIVFFlat)
+	cfg.VectorIndex.Metric = cfg.Embedding.DefaultMetric
+	cfg.Embedding.Quantization = getEnv("VECTOR_INDEX_QUANTIZATION", db.QuantizationNone)
+	cfg.VectorIndex.Quantization = cfg.Embedding.Quantization
+	cfg.VectorIndex.Dim = cfg.Embedding.ExpectedDim
+	if cfg.VectorIndex.Lists, err = getEnvInt("VECTOR_INDEX_LISTS", 100); err != nil {
+		return cfg, err
+	}
+	if cfg.VectorIndex.M, err = getEnvInt("VECTOR_INDEX_M", 16); err != nil {
+		return cfg, err
+	}
+	if cfg.VectorIndex.EfConstruction, err = getEnvInt("VECTOR_INDEX_EF_CONSTRUCTION", 64); err != nil {
+		return cfg, err
+	}
+	if err := cfg.VectorIndex.Validate(); err != nil {
+		return cfg, err
+	}
+
+	maxConns, err := getEnvInt("DB_MAX_CONNS", 0)
+	if err != nil {
+		return cfg, err
+	}
+	minConns, err := getEnvInt("DB_MIN_CONNS", 0)
+	if err != nil {
+		return cfg, err
+	}
+	if maxConns > math.MaxInt32 || minConns > math.MaxInt32 {
+		return cfg, fmt.Errorf("DB_MAX_CONNS and DB_MIN_CONNS must fit in 32 bits")
+	}
+	cfg.Pool.MaxConns, cfg.Pool.MinConns = int32(maxConns), int32(minConns)
+	if cfg.Pool.MaxConnLifetime, err = getEnvDuration("DB_MAX_CONN_LIFETIME", 0); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Pool.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid DB pool settings: %w", err)
+	}
+
+	if cfg.DBConnect.Retries, err = getEnvInt("DB_CONNECT_RETRIES", 5); err != nil {
+		return cfg, err
+	}
+	if cfg.DBConnect.Timeout, err = getEnvDuration("DB_CONNECT_TIMEOUT", 30*time.Second); err != nil {
+		return cfg, err
+	}
+	if err := cfg.DBConnect.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid DB connect settings: %w", err)
+	}
+
+	maxBody, err := getEnvInt("MAX_BODY_BYTES", 10<<20)
+	if err != nil {
+		return cfg, err
+	}
+	if maxBody < 0 {
+		return cfg, fmt.Errorf("MAX_BODY_BYTES must not be negative, got %d", maxBody)
+	}
+	cfg.MaxBodyBytes = int64(maxBody)
+
+	maxUpload, err := getEnvInt("MAX_UPLOAD_BYTES", 10<<20)
+	if err != nil {
+		return cfg, err
+	}
+	if maxUpload <= 0 {
+		return cfg, fmt.Errorf("MAX_UPLOAD_BYTES must be positive, got %d", maxUpload)
+	}
+	cfg.MaxUploadBytes = int64(maxUpload)
+
+	maxDecompressed, err := getEnvInt("MAX_DECOMPRESSED_BYTES", 50<<20)
+	if err != nil {
+		return cfg, err
+	}
+	if maxDecompressed <= 0 {
+		return cfg, fmt.Errorf("MAX_DECOMPRESSED_BYTES must be positive, got %d", maxDecompressed)
+	}
+	cfg.MaxDecompressedBytes = int64(maxDecompressed)
+
+	maxDB, err := getEnvInt("MAX_DB_BYTES", 0)
+	if err != nil {
+		return cfg, err
+	}
+	if maxDB < 0 {
+		return cfg, fmt.Errorf("MAX_DB_BYTES must not be negative, got %d", maxDB)
+	}
+	cfg.MaxDBBytes = int64(maxDB)
+
+	if cfg.CapacityCheckInterval, err = getEnvDuration("CAPACITY_CHECK_INTERVAL", time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.CapacityCheckInterval <= 0 {
+		return cfg, fmt.Errorf("CAPACITY_CHECK_INTERVAL must be positive, got %s", cfg.CapacityCheckInterval)
+	}
+
+	if cfg.DBQueryTimeout, err = getEnvDuration("DB_QUERY_TIMEOUT", 10*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.DBQueryTimeout <= 0 {
+		return cfg, fmt.Errorf("DB_QUERY_TIMEOUT must be positive, got %s", cfg.DBQueryTimeout)
+	}
+
+	ttlDays, err := getEnvInt("RECYCLE_BIN_TTL_DAYS", 30)
+	if err != nil {
+		return cfg, err
+	}
+	if ttlDays < 0 {
+		return cfg, fmt.Errorf("RECYCLE_BIN_TTL_DAYS must not be negative, got %d", ttlDays)
+	}
+	cfg.RecycleBinTTL = time.Duration(ttlDays) * 24 * time.Hour
+
+	if cfg.MaxFileVersions, err = getEnvInt("FILE_VERSIONS_MAX", 20); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxFileVersions < 0 {
+		return cfg, fmt.Errorf("FILE_VERSIONS_MAX must not be negative, got %d", cfg.MaxFileVersions)
+	}
+
+	return cfg, nil
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, v, err)
+	}
+	return n, nil
+}
+
+func getEnvBool(key string, fallback bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: %w", key, v, err)
+	}
+	return b, nil
+}
+
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, v, err)
+	}
+	return d, nil
+}
+
+// getEnvList splits a comma-separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
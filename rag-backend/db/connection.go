@@ -11,32 +11,87 @@ import (
 	pgvectorpgx "github.com/pgvector/pgvector-go/pgx"
 )
 
-func ConnectDB() *Queries {
+// ConnectDB opens the pool described by DATABASE_URL, sized by poolCfg. It
+// waits for the database according to retry and returns an error, rather than
+// exiting, when it cannot connect.
+func ConnectDB(poolCfg PoolConfig, retry ConnectRetry) (*Queries, error) {
 	ctx := context.Background()
 
-	cfg, err := pgxpool.ParseConfig(os.Getenv("DATABASE_URL"))
+	cfg, err := NewPoolConfig(os.Getenv("DATABASE_URL"), poolCfg)
 	if err != nil {
-		log.Fatal("Failed to parse DB config:", err)
+		return nil, fmt.Errorf("parse DB config: %w", err)
 	}
+	log.Printf("DB pool: max_conns=%d min_conns=%d max_conn_lifetime=%s", cfg.MaxConns, cfg.MinConns, cfg.MaxConnLifetime)
 
-	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	// The extension must exist before the pool opens: every pooled connection
+	// registers the vector type in AfterConnect, which fails without it. The
+	// extension check and the ping are the first contacts with the database,
+	// so both are retried until it accepts connections.
+	var pool *pgxpool.Pool
+	err = retry.Do(ctx, func(attemptCtx context.Context) error {
+		if err := ensureVectorExtension(attemptCtx, cfg.ConnConfig); err != nil {
+			return fmt.Errorf("ensure vector extension: %w", err)
+		}
+		// The pool outlives the retry deadline, so it gets the outer context.
+		if pool == nil {
+			if pool, err = pgxpool.NewWithConfig(ctx, cfg); err != nil {
+				return fmt.Errorf("create pool: %w", err)
+			}
+		}
+		if err := pool.Ping(attemptCtx); err != nil {
+			return fmt.Errorf("ping: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatal("Unable to connect to database:", err)
+		if pool != nil {
+			pool.Close()
+		}
+		return nil, err
 	}
 
-	if _, err := pool.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS vector"); err != nil {
-		log.Fatalf("Failed to ensure vector extension: %v", err)
-	}
+	fmt.Println("✅ Connected to DB")
+
+	return New(pool), nil
+}
 
+// NewPoolConfig parses databaseURL, applies poolCfg, and registers the
+// pgvector types on every connection the pool opens. The hook must be set
+// before pgxpool.NewWithConfig, which copies the config.
+func NewPoolConfig(databaseURL string, poolCfg PoolConfig) (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.Apply(cfg)
 	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
 		return pgvectorpgx.RegisterTypes(ctx, conn)
 	}
+	return cfg, nil
+}
 
-	if err := pool.Ping(ctx); err != nil {
-		log.Fatalf("Database ping failed: %v", err)
+// ensureVectorExtension creates the vector extension over a single
+// connection that does not depend on the vector type being registered.
+func ensureVectorExtension(ctx context.Context, connCfg *pgx.ConnConfig) error {
+	conn, err := pgx.ConnectConfig(ctx, connCfg.Copy())
+	if err != nil {
+		return err
 	}
+	defer conn.Close(ctx)
+	_, err = conn.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS vector")
+	return err
+}
 
-	fmt.Println("✅ Connected to DB")
+// closer is satisfied by *pgxpool.Pool.
+type closer interface {
+	Close()
+}
 
-	return New(pool)
+// Close releases the connection pool. It waits for acquired connections to be
+// returned, so call it after the HTTP server has stopped. Handles without a
+// pool, such as transactions, are left alone.
+func (q *Queries) Close() {
+	if c, ok := q.db.(closer); ok {
+		c.Close()
+	}
 }
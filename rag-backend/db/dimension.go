@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// pg_catalog is outside sqlc's view of the schema, so this query is written by hand.
+const embeddingColumnDim = `SELECT atttypmod FROM pg_attribute
+WHERE attrelid = 'files'::regclass AND attname = 'embedding'`
+
+// EmbeddingColumnDim returns the declared dimension of files.embedding,
+// or -1 when the column was created without one.
+func (q *Queries) EmbeddingColumnDim(ctx context.Context) (int, error) {
+	var dim int32
+	err := q.db.QueryRow(ctx, embeddingColumnDim).Scan(&dim)
+	return int(dim), err
+}
+
+// CheckEmbeddingDim reports a mismatch between the configured embedding
+// dimension and the dimension declared on the embedding column. A configured
+// dimension of 0 means no dimension is enforced, and a column without a
+// declared dimension is accepted because EnsureEmbeddingDim declares it.
+func CheckEmbeddingDim(configured, declared int) error {
+	if configured == 0 || declared <= 0 {
+		return nil
+	}
+	if configured != declared {
+		return fmt.Errorf("EXPECTED_EMBEDDING_DIM is %d but files.embedding is vector(%d)", configured, declared)
+	}
+	return nil
+}
+
+// VerifyEmbeddingDim fails fast when the configured dimension disagrees with the database.
+func (q *Queries) VerifyEmbeddingDim(ctx context.Context, configured int) error {
+	if configured == 0 {
+		return nil
+	}
+	declared, err := q.EmbeddingColumnDim(ctx)
+	if err != nil {
+		return fmt.Errorf("read embedding column dimension: %w", err)
+	}
+	return CheckEmbeddingDim(configured, declared)
+}
+
+// EnsureEmbeddingDim declares files.embedding as vector(configured) when the
+// migrations left it without a dimension, as they do for an empty table, and
+// otherwise checks the declared dimension like VerifyEmbeddingDim. Declaring
+// fails if stored embeddings have another length.
+func (q *Queries) EnsureEmbeddingDim(ctx context.Context, configured int) error {
+	declared, err := q.EmbeddingColumnDim(ctx)
+	if err != nil {
+		return fmt.Errorf("read embedding column dimension: %w", err)
+	}
+	if declared > 0 {
+		return CheckEmbeddingDim(configured, declared)
+	}
+	if configured == 0 {
+		return errors.New("files.embedding has no declared dimension; set EXPECTED_EMBEDDING_DIM to declare it")
+	}
+
+	log.Printf("Declaring files.embedding as vector(%d)", configured)
+	if _, err := q.db.Exec(ctx, fmt.Sprintf("ALTER TABLE files ALTER COLUMN embedding TYPE vector(%d)", configured)); err != nil {
+		return fmt.Errorf("declare embedding dimension: %w", err)
+	}
+	return nil
+}
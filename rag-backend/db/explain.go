@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fain17/rag-backend/vector"
+)
+
+// explainPrefix asks the planner for its plan without running the query.
+const explainPrefix = "EXPLAIN (FORMAT JSON)\n"
+
+// explainPlan is the part of an EXPLAIN (FORMAT JSON) node needed to find index scans.
+type explainPlan struct {
+	NodeType  string        `json:"Node Type"`
+	IndexName string        `json:"Index Name"`
+	Plans     []explainPlan `json:"Plans"`
+}
+
+// usesIndex reports whether the plan, or any node below it, scans the named index.
+func (p explainPlan) usesIndex(name string) bool {
+	if p.IndexName == name {
+		return true
+	}
+	for _, child := range p.Plans {
+		if child.usesIndex(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExplainSearch reports whether the planner would serve the similarity search
+// for metric from the vector index. quantizedDim is the halfvec dimension when
+// the search goes through SearchFilesQuantized, and 0 otherwise. The query is
+// planned, not executed.
+func (q *Queries) ExplainSearch(ctx context.Context, metric string, quantizedDim int, arg SearchFilesCosineParams) (bool, error) {
+	var sql string
+	switch {
+	case quantizedDim > 0:
+		var err error
+		if sql, err = quantizedSearchSQL(metric, quantizedDim); err != nil {
+			return false, err
+		}
+	case metric == vector.MetricL2:
+		sql = searchFilesL2
+	case metric == vector.MetricInner:
+		sql = searchFilesInner
+	case metric == vector.MetricCosine:
+		sql = searchFilesCosine
+	default:
+		return false, fmt.Errorf("unsupported metric %q", metric)
+	}
+
+	var raw []byte
+	err := q.db.QueryRow(ctx, explainPrefix+sql,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	).Scan(&raw)
+	if err != nil {
+		return false, err
+	}
+	var plans []struct {
+		Plan explainPlan `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return false, fmt.Errorf("parse query plan: %w", err)
+	}
+	for _, p := range plans {
+		if p.Plan.usesIndex(vectorIndexName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,18 @@
+package db
+
+import "context"
+
+// pinger is satisfied by *pgxpool.Pool and *pgx.Conn.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks that the database is reachable. Handles without a native Ping,
+// such as transactions, run a trivial query instead.
+func (q *Queries) Ping(ctx context.Context) error {
+	if p, ok := q.db.(pinger); ok {
+		return p.Ping(ctx)
+	}
+	_, err := q.db.Exec(ctx, "SELECT 1")
+	return err
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+)
+
+// Like embeddingColumnDim, these read pg_catalog and are written by hand.
+const tableColumns = `SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull
+FROM pg_attribute a
+WHERE a.attrelid = 'files'::regclass AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`
+
+const tableIndexes = `SELECT indexname, indexdef FROM pg_indexes
+WHERE tablename = 'files'
+ORDER BY indexname`
+
+// ColumnInfo describes one column of the files table.
+type ColumnInfo struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// IndexInfo describes one index on the files table.
+type IndexInfo struct {
+	Name       string
+	Definition string
+}
+
+// TableColumns lists the files table's columns in declaration order.
+func (q *Queries) TableColumns(ctx context.Context) ([]ColumnInfo, error) {
+	rows, err := q.db.Query(ctx, tableColumns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ColumnInfo
+	for rows.Next() {
+		var i ColumnInfo
+		if err := rows.Scan(&i.Name, &i.Type, &i.Nullable); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+// TableIndexes lists the indexes defined on the files table.
+func (q *Queries) TableIndexes(ctx context.Context) ([]IndexInfo, error) {
+	rows, err := q.db.Query(ctx, tableIndexes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IndexInfo
+	for rows.Next() {
+		var i IndexInfo
+		if err := rows.Scan(&i.Name, &i.Definition); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
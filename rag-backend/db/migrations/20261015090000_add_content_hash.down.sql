@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_files_filename;
+DROP INDEX IF EXISTS idx_files_content_hash;
+
+ALTER TABLE files DROP COLUMN IF EXISTS content_hash;
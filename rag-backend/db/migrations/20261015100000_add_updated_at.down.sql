@@ -0,0 +1,2 @@
+ALTER TABLE files DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE files DROP COLUMN IF EXISTS updated_at;
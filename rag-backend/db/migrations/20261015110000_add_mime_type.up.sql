@@ -0,0 +1,3 @@
+ALTER TABLE files ADD COLUMN IF NOT EXISTS mime_type TEXT NOT NULL DEFAULT 'text/plain';
+
+CREATE INDEX IF NOT EXISTS idx_files_mime_type ON files (mime_type);
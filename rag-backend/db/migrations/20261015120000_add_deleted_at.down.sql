@@ -0,0 +1,5 @@
+ALTER TABLE files DROP COLUMN IF EXISTS deleted;
+ALTER TABLE files ADD COLUMN deleted BOOLEAN DEFAULT FALSE;
+UPDATE files SET deleted = (deleted_at IS NOT NULL);
+
+ALTER TABLE files DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,9 @@
+ALTER TABLE files ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- The deletion time of existing soft-deleted rows was never recorded; the last update is the best estimate.
+UPDATE files SET deleted_at = COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+WHERE deleted IS TRUE AND deleted_at IS NULL;
+
+-- deleted is kept for compatibility but now derives from deleted_at.
+ALTER TABLE files DROP COLUMN deleted;
+ALTER TABLE files ADD COLUMN deleted BOOLEAN GENERATED ALWAYS AS (deleted_at IS NOT NULL) STORED;
@@ -0,0 +1,3 @@
+ALTER TABLE files ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_files_tags ON files USING GIN (tags);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS file_versions (
+    file_id UUID NOT NULL REFERENCES files (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    filename TEXT NOT NULL,
+    content TEXT NOT NULL,
+    -- Unconstrained so snapshots survive a change of the files.embedding dimension.
+    embedding VECTOR NOT NULL,
+    content_hash TEXT,
+    mime_type TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (file_id, version)
+);
@@ -0,0 +1,12 @@
+-- No foreign key to files: the trail must outlive the files it describes.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT,
+    action TEXT NOT NULL,
+    file_id UUID,
+    status INTEGER NOT NULL,
+    request_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_file_id ON audit_log (file_id);
@@ -0,0 +1,2 @@
+ALTER TABLE file_versions DROP COLUMN IF EXISTS embedding_truncated;
+ALTER TABLE files DROP COLUMN IF EXISTS embedding_truncated;
@@ -0,0 +1,3 @@
+-- Marks embeddings computed from content that was trimmed to the embedder's limit.
+ALTER TABLE files ADD COLUMN IF NOT EXISTS embedding_truncated BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE file_versions ADD COLUMN IF NOT EXISTS embedding_truncated BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Fails if stored embeddings are not 384-dimensional.
+DROP INDEX IF EXISTS idx_files_embedding;
+ALTER TABLE files ALTER COLUMN embedding TYPE VECTOR(384);
@@ -0,0 +1,10 @@
+-- files.embedding takes its dimension from EXPECTED_EMBEDDING_DIM, declared by
+-- the server at startup. Only an empty table is unpinned; a populated one keeps
+-- its dimension, which the startup check compares with the configuration.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM files) THEN
+        DROP INDEX IF EXISTS idx_files_embedding;
+        ALTER TABLE files ALTER COLUMN embedding TYPE VECTOR;
+    END IF;
+END $$;
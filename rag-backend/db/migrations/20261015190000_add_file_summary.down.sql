@@ -0,0 +1,2 @@
+ALTER TABLE files DROP COLUMN IF EXISTS summary_embedding;
+ALTER TABLE files DROP COLUMN IF EXISTS summary;
@@ -0,0 +1,3 @@
+-- An optional summary of the content, embedded separately for summary search.
+ALTER TABLE files ADD COLUMN IF NOT EXISTS summary TEXT;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS summary_embedding VECTOR;
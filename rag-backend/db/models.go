@@ -6,14 +6,54 @@ package db
 
 import (
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/pgvector/pgvector-go"
+	pgvector "github.com/pgvector/pgvector-go"
 )
 
-type File struct {
+type ApiKey struct {
 	ID        pgtype.UUID
-	Filename  string
-	Content   string
-	Embedding pgvector.Vector
+	UserID    string
+	KeyHash   string
 	CreatedAt pgtype.Timestamptz
-	Deleted   pgtype.Bool
+	RevokedAt pgtype.Timestamptz
+}
+
+type AuditLog struct {
+	ID        int64
+	UserID    pgtype.Text
+	Action    string
+	FileID    pgtype.UUID
+	Status    int32
+	RequestID pgtype.Text
+	CreatedAt pgtype.Timestamptz
+}
+
+type File struct {
+	ID                 pgtype.UUID
+	Filename           string
+	Content            string
+	Embedding          pgvector.Vector
+	CreatedAt          pgtype.Timestamptz
+	ContentHash        pgtype.Text
+	UpdatedAt          pgtype.Timestamptz
+	ReviewedAt         pgtype.Timestamptz
+	MimeType           string
+	DeletedAt          pgtype.Timestamptz
+	Deleted            pgtype.Bool
+	Tags               []string
+	EmbeddingTruncated bool
+	Summary            pgtype.Text
+	SummaryEmbedding   *pgvector.Vector
+}
+
+type FileVersion struct {
+	FileID             pgtype.UUID
+	Version            int32
+	Filename           string
+	Content            string
+	Embedding          pgvector.Vector
+	ContentHash        pgtype.Text
+	MimeType           string
+	Tags               []string
+	CreatedAt          pgtype.Timestamptz
+	EmbeddingTruncated bool
 }
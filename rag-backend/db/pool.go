@@ -0,0 +1,46 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolConfig sizes the connection pool. Zero fields keep pgxpool's defaults
+// (max conns of max(4, NumCPU), no min conns, one hour lifetime).
+type PoolConfig struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+}
+
+// Validate rejects negative values and a minimum above the maximum.
+func (p PoolConfig) Validate() error {
+	if p.MaxConns < 0 {
+		return fmt.Errorf("max conns must not be negative, got %d", p.MaxConns)
+	}
+	if p.MinConns < 0 {
+		return fmt.Errorf("min conns must not be negative, got %d", p.MinConns)
+	}
+	if p.MaxConns > 0 && p.MinConns > p.MaxConns {
+		return fmt.Errorf("min conns %d exceeds max conns %d", p.MinConns, p.MaxConns)
+	}
+	if p.MaxConnLifetime < 0 {
+		return fmt.Errorf("max conn lifetime must not be negative, got %s", p.MaxConnLifetime)
+	}
+	return nil
+}
+
+// Apply copies the non-zero settings onto cfg.
+func (p PoolConfig) Apply(cfg *pgxpool.Config) {
+	if p.MaxConns > 0 {
+		cfg.MaxConns = p.MaxConns
+	}
+	if p.MinConns > 0 {
+		cfg.MinConns = p.MinConns
+	}
+	if p.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = p.MaxConnLifetime
+	}
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PurgeInterval is how often StartPurgeWorker empties the recycle bin.
+const PurgeInterval = time.Hour
+
+// PurgeExpired permanently deletes files that were soft-deleted more than ttl
+// ago and returns how many were removed.
+func (q *Queries) PurgeExpired(ctx context.Context, ttl time.Duration) (int64, error) {
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-ttl), Valid: true}
+	return q.PurgeDeletedBefore(ctx, cutoff)
+}
+
+// StartPurgeWorker purges expired recycle-bin entries immediately and then
+// every interval until ctx is cancelled. A ttl of 0 disables purging.
+func StartPurgeWorker(ctx context.Context, q *Queries, ttl, interval time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	purge := func() {
+		n, err := q.PurgeExpired(ctx, ttl)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("recycle bin purge failed: %v", err)
+			}
+			return
+		}
+		log.Printf("recycle bin purge removed %d file(s) deleted more than %s ago", n, ttl)
+	}
+
+	purge()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purge()
+		}
+	}
+}
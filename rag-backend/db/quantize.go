@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fain17/rag-backend/vector"
+)
+
+// Supported vector index quantizations. halfvec stores the index at half
+// precision; the table, and so every read, keeps the full-precision vectors.
+const (
+	QuantizationNone    = "none"
+	QuantizationHalfvec = "halfvec"
+)
+
+// maxHalfvecIndexDim is the largest halfvec pgvector can index.
+const maxHalfvecIndexDim = 4000
+
+// quantizedOverfetch is how many half-precision candidates are fetched per
+// requested result, so re-ranking at full precision can restore the order
+// rounding shuffled and pull in results it pushed just past the cut.
+const quantizedOverfetch = 4
+
+// halfvecCast casts a vector expression to the indexed half-precision type.
+func halfvecCast(expr string, dim int) string {
+	return fmt.Sprintf("(%s)::halfvec(%d)", expr, dim)
+}
+
+// searchFilesQuantized takes candidates in the order of the quantized index and
+// re-ranks them by exact distance. Its filters match SearchFilesCosine.
+const searchFilesQuantized = `-- name: SearchFilesQuantized :many
+WITH candidates AS (
+  SELECT id FROM files
+  WHERE ($2::boolean OR deleted IS NOT TRUE)
+    AND ($3::text IS NULL OR filename ILIKE '%%' || $3::text || '%%')
+    AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+    AND ($5::timestamptz IS NULL OR created_at <= $5::timestamptz)
+    AND ($6::text IS NULL OR mime_type = $6::text)
+  ORDER BY %[1]s %[2]s %[3]s
+  LIMIT $7 * %[4]d
+)
+SELECT f.id, f.filename, f.created_at, f.content_hash, (f.embedding %[2]s $1::vector)::float8 AS distance
+FROM files f JOIN candidates c ON c.id = f.id
+ORDER BY f.embedding %[2]s $1::vector, f.created_at, f.id
+LIMIT $7`
+
+// SearchFilesQuantized is SearchFilesCosine, SearchFilesL2, or
+// SearchFilesInner for metric, served by a halfvec(dim) index: the index
+// proposes candidates and their full-precision distances decide the result.
+func (q *Queries) SearchFilesQuantized(ctx context.Context, metric string, dim int, arg SearchFilesCosineParams) ([]SearchFilesCosineRow, error) {
+	sql, err := quantizedSearchSQL(metric, dim)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.db.Query(ctx, sql,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchFilesCosineRow
+	for rows.Next() {
+		var i SearchFilesCosineRow
+		if err := rows.Scan(&i.ID, &i.Filename, &i.CreatedAt, &i.ContentHash, &i.Distance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+// quantizedSearchSQL renders searchFilesQuantized for metric over halfvec(dim).
+func quantizedSearchSQL(metric string, dim int) (string, error) {
+	var operator string
+	for _, m := range vector.Metrics() {
+		if m.Name == metric {
+			operator = m.Operator
+		}
+	}
+	if operator == "" {
+		return "", fmt.Errorf("unsupported metric %q", metric)
+	}
+	return fmt.Sprintf(searchFilesQuantized,
+		halfvecCast("embedding", dim), operator, halfvecCast("$1::vector", dim), quantizedOverfetch), nil
+}
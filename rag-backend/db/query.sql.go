@@ -9,9 +9,67 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/pgvector/pgvector-go"
+	pgvector "github.com/pgvector/pgvector-go"
 )
 
+const cloneFile = `-- name: CloneFile :one
+INSERT INTO files (filename, content, embedding, content_hash, mime_type, tags, embedding_truncated, summary, summary_embedding)
+SELECT COALESCE($1::text, 'Copy of ' || src.filename), src.content, src.embedding, src.content_hash, src.mime_type, src.tags, src.embedding_truncated, src.summary, src.summary_embedding
+FROM files src
+WHERE src.id = $2 AND src.deleted IS NOT TRUE
+RETURNING id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding
+`
+
+type CloneFileParams struct {
+	Filename pgtype.Text
+	ID       pgtype.UUID
+}
+
+func (q *Queries) CloneFile(ctx context.Context, arg CloneFileParams) (File, error) {
+	row := q.db.QueryRow(ctx, cloneFile, arg.Filename, arg.ID)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.Filename,
+		&i.Content,
+		&i.Embedding,
+		&i.CreatedAt,
+		&i.ContentHash,
+		&i.UpdatedAt,
+		&i.ReviewedAt,
+		&i.MimeType,
+		&i.DeletedAt,
+		&i.Deleted,
+		&i.Tags,
+		&i.EmbeddingTruncated,
+		&i.Summary,
+		&i.SummaryEmbedding,
+	)
+	return i, err
+}
+
+const countFilesMissingContentHash = `-- name: CountFilesMissingContentHash :one
+SELECT COUNT(*) FROM files WHERE content_hash IS NULL
+`
+
+func (q *Queries) CountFilesMissingContentHash(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countFilesMissingContentHash)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRecycleBin = `-- name: CountRecycleBin :one
+SELECT COUNT(*) FROM files WHERE deleted
+`
+
+func (q *Queries) CountRecycleBin(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecycleBin)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countTotalFiles = `-- name: CountTotalFiles :one
 SELECT COUNT(*) FROM files
 `
@@ -23,20 +81,84 @@ func (q *Queries) CountTotalFiles(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, key_hash)
+VALUES ($1, $2)
+RETURNING id, user_id, created_at
+`
+
+type CreateAPIKeyParams struct {
+	UserID  string
+	KeyHash string
+}
+
+type CreateAPIKeyRow struct {
+	ID        pgtype.UUID
+	UserID    string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (CreateAPIKeyRow, error) {
+	row := q.db.QueryRow(ctx, createAPIKey, arg.UserID, arg.KeyHash)
+	var i CreateAPIKeyRow
+	err := row.Scan(&i.ID, &i.UserID, &i.CreatedAt)
+	return i, err
+}
+
+const createAuditEntry = `-- name: CreateAuditEntry :exec
+INSERT INTO audit_log (user_id, action, file_id, status, request_id)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateAuditEntryParams struct {
+	UserID    pgtype.Text
+	Action    string
+	FileID    pgtype.UUID
+	Status    int32
+	RequestID pgtype.Text
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) error {
+	_, err := q.db.Exec(ctx, createAuditEntry,
+		arg.UserID,
+		arg.Action,
+		arg.FileID,
+		arg.Status,
+		arg.RequestID,
+	)
+	return err
+}
+
 const createFile = `-- name: CreateFile :one
-INSERT INTO files (filename, content, embedding)
-VALUES ($1, $2, $3)
-RETURNING id, filename, content, embedding, created_at, deleted
+INSERT INTO files (filename, content, embedding, content_hash, mime_type, embedding_truncated, tags, summary, summary_embedding)
+VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::text[], '{}'), $8, $9)
+RETURNING id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding
 `
 
 type CreateFileParams struct {
-	Filename  string
-	Content   string
-	Embedding pgvector.Vector
+	Filename           string
+	Content            string
+	Embedding          pgvector.Vector
+	ContentHash        pgtype.Text
+	MimeType           string
+	EmbeddingTruncated bool
+	Tags               []string
+	Summary            pgtype.Text
+	SummaryEmbedding   *pgvector.Vector
 }
 
 func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, error) {
-	row := q.db.QueryRow(ctx, createFile, arg.Filename, arg.Content, arg.Embedding)
+	row := q.db.QueryRow(ctx, createFile,
+		arg.Filename,
+		arg.Content,
+		arg.Embedding,
+		arg.ContentHash,
+		arg.MimeType,
+		arg.EmbeddingTruncated,
+		arg.Tags,
+		arg.Summary,
+		arg.SummaryEmbedding,
+	)
 	var i File
 	err := row.Scan(
 		&i.ID,
@@ -44,26 +166,209 @@ func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, e
 		&i.Content,
 		&i.Embedding,
 		&i.CreatedAt,
+		&i.ContentHash,
+		&i.UpdatedAt,
+		&i.ReviewedAt,
+		&i.MimeType,
+		&i.DeletedAt,
 		&i.Deleted,
+		&i.Tags,
+		&i.EmbeddingTruncated,
+		&i.Summary,
+		&i.SummaryEmbedding,
 	)
 	return i, err
 }
 
-const deleteFile = `-- name: DeleteFile :exec
+const createFileVersion = `-- name: CreateFileVersion :one
+INSERT INTO file_versions (file_id, version, filename, content, embedding, content_hash, mime_type, tags, embedding_truncated)
+SELECT f.id,
+       COALESCE((SELECT MAX(v.version) FROM file_versions v WHERE v.file_id = f.id), 0) + 1,
+       f.filename, f.content, f.embedding, f.content_hash, f.mime_type, f.tags, f.embedding_truncated
+FROM files f
+WHERE f.id = $1
+RETURNING version
+`
+
+// Snapshots the file's current state as its next version number.
+func (q *Queries) CreateFileVersion(ctx context.Context, fileID pgtype.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, createFileVersion, fileID)
+	var version int32
+	err := row.Scan(&version)
+	return version, err
+}
+
+const deleteFile = `-- name: DeleteFile :execrows
 DELETE FROM files WHERE id = $1
 `
 
-func (q *Queries) DeleteFile(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteFile, id)
-	return err
+func (q *Queries) DeleteFile(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFile, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteFilesByIDs = `-- name: DeleteFilesByIDs :many
+DELETE FROM files WHERE id = ANY($1::uuid[])
+RETURNING id
+`
+
+func (q *Queries) DeleteFilesByIDs(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, deleteFilesByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findExistingFiles = `-- name: FindExistingFiles :many
+SELECT id, filename, content_hash
+FROM files
+WHERE deleted IS NOT TRUE
+  AND (filename = ANY($1::text[]) OR content_hash = ANY($2::text[]))
+ORDER BY created_at DESC, id
+`
+
+type FindExistingFilesParams struct {
+	Filenames []string
+	Hashes    []string
+}
+
+type FindExistingFilesRow struct {
+	ID          pgtype.UUID
+	Filename    string
+	ContentHash pgtype.Text
+}
+
+func (q *Queries) FindExistingFiles(ctx context.Context, arg FindExistingFilesParams) ([]FindExistingFilesRow, error) {
+	rows, err := q.db.Query(ctx, findExistingFiles, arg.Filenames, arg.Hashes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindExistingFilesRow
+	for rows.Next() {
+		var i FindExistingFilesRow
+		if err := rows.Scan(&i.ID, &i.Filename, &i.ContentHash); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveAPIKeyByHash = `-- name: GetActiveAPIKeyByHash :one
+SELECT id, user_id, key_hash FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL
+`
+
+type GetActiveAPIKeyByHashRow struct {
+	ID      pgtype.UUID
+	UserID  string
+	KeyHash string
+}
+
+func (q *Queries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (GetActiveAPIKeyByHashRow, error) {
+	row := q.db.QueryRow(ctx, getActiveAPIKeyByHash, keyHash)
+	var i GetActiveAPIKeyByHashRow
+	err := row.Scan(&i.ID, &i.UserID, &i.KeyHash)
+	return i, err
+}
+
+const getAllFileSummaries = `-- name: GetAllFileSummaries :many
+SELECT id, filename, LENGTH(content)::int AS size, created_at, deleted, mime_type
+FROM files
+WHERE $1::text IS NULL OR mime_type = $1::text
+ORDER BY
+  CASE WHEN $2::text = 'created_at' AND $3::boolean THEN created_at END DESC,
+  CASE WHEN $2::text = 'created_at' AND NOT $3::boolean THEN created_at END ASC,
+  CASE WHEN $2::text = 'filename' AND $3::boolean THEN filename END DESC,
+  CASE WHEN $2::text = 'filename' AND NOT $3::boolean THEN filename END ASC,
+  CASE WHEN $2::text = 'size' AND $3::boolean THEN LENGTH(content) END DESC,
+  CASE WHEN $2::text = 'size' AND NOT $3::boolean THEN LENGTH(content) END ASC,
+  id DESC
+`
+
+type GetAllFileSummariesParams struct {
+	MimeType   pgtype.Text
+	Sort       string
+	Descending bool
+}
+
+type GetAllFileSummariesRow struct {
+	ID        pgtype.UUID
+	Filename  string
+	Size      int32
+	CreatedAt pgtype.Timestamptz
+	Deleted   pgtype.Bool
+	MimeType  string
+}
+
+func (q *Queries) GetAllFileSummaries(ctx context.Context, arg GetAllFileSummariesParams) ([]GetAllFileSummariesRow, error) {
+	rows, err := q.db.Query(ctx, getAllFileSummaries, arg.MimeType, arg.Sort, arg.Descending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllFileSummariesRow
+	for rows.Next() {
+		var i GetAllFileSummariesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.Size,
+			&i.CreatedAt,
+			&i.Deleted,
+			&i.MimeType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getAllFiles = `-- name: GetAllFiles :many
-SELECT id, filename, content, embedding, created_at, deleted FROM files ORDER BY id DESC
+SELECT id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding FROM files
+WHERE $1::text IS NULL OR mime_type = $1::text
+ORDER BY
+  CASE WHEN $2::text = 'created_at' AND $3::boolean THEN created_at END DESC,
+  CASE WHEN $2::text = 'created_at' AND NOT $3::boolean THEN created_at END ASC,
+  CASE WHEN $2::text = 'filename' AND $3::boolean THEN filename END DESC,
+  CASE WHEN $2::text = 'filename' AND NOT $3::boolean THEN filename END ASC,
+  CASE WHEN $2::text = 'size' AND $3::boolean THEN LENGTH(content) END DESC,
+  CASE WHEN $2::text = 'size' AND NOT $3::boolean THEN LENGTH(content) END ASC,
+  id DESC
 `
 
-func (q *Queries) GetAllFiles(ctx context.Context) ([]File, error) {
-	rows, err := q.db.Query(ctx, getAllFiles)
+type GetAllFilesParams struct {
+	MimeType   pgtype.Text
+	Sort       string
+	Descending bool
+}
+
+func (q *Queries) GetAllFiles(ctx context.Context, arg GetAllFilesParams) ([]File, error) {
+	rows, err := q.db.Query(ctx, getAllFiles, arg.MimeType, arg.Sort, arg.Descending)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +382,16 @@ func (q *Queries) GetAllFiles(ctx context.Context) ([]File, error) {
 			&i.Content,
 			&i.Embedding,
 			&i.CreatedAt,
+			&i.ContentHash,
+			&i.UpdatedAt,
+			&i.ReviewedAt,
+			&i.MimeType,
+			&i.DeletedAt,
 			&i.Deleted,
+			&i.Tags,
+			&i.EmbeddingTruncated,
+			&i.Summary,
+			&i.SummaryEmbedding,
 		); err != nil {
 			return nil, err
 		}
@@ -89,8 +403,19 @@ func (q *Queries) GetAllFiles(ctx context.Context) ([]File, error) {
 	return items, nil
 }
 
+const getDatabaseSize = `-- name: GetDatabaseSize :one
+SELECT pg_database_size(current_database())::bigint AS size
+`
+
+func (q *Queries) GetDatabaseSize(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getDatabaseSize)
+	var size int64
+	err := row.Scan(&size)
+	return size, err
+}
+
 const getDeletedFiles = `-- name: GetDeletedFiles :many
-SELECT id, filename, content, embedding, created_at, deleted FROM files WHERE deleted = TRUE ORDER BY created_at DESC
+SELECT id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding FROM files WHERE deleted = TRUE ORDER BY deleted_at DESC, created_at DESC
 `
 
 func (q *Queries) GetDeletedFiles(ctx context.Context) ([]File, error) {
@@ -108,7 +433,16 @@ func (q *Queries) GetDeletedFiles(ctx context.Context) ([]File, error) {
 			&i.Content,
 			&i.Embedding,
 			&i.CreatedAt,
+			&i.ContentHash,
+			&i.UpdatedAt,
+			&i.ReviewedAt,
+			&i.MimeType,
+			&i.DeletedAt,
 			&i.Deleted,
+			&i.Tags,
+			&i.EmbeddingTruncated,
+			&i.Summary,
+			&i.SummaryEmbedding,
 		); err != nil {
 			return nil, err
 		}
@@ -120,8 +454,106 @@ func (q *Queries) GetDeletedFiles(ctx context.Context) ([]File, error) {
 	return items, nil
 }
 
+const getDuplicateGroups = `-- name: GetDuplicateGroups :many
+SELECT content_hash::text AS content_hash, COUNT(*) AS count,
+       array_agg(id ORDER BY created_at, id)::uuid[] AS ids
+FROM files
+WHERE content_hash IS NOT NULL AND deleted IS NOT TRUE
+GROUP BY content_hash
+HAVING COUNT(*) > 1
+ORDER BY count DESC, content_hash
+`
+
+type GetDuplicateGroupsRow struct {
+	ContentHash string
+	Count       int64
+	Ids         []pgtype.UUID
+}
+
+func (q *Queries) GetDuplicateGroups(ctx context.Context) ([]GetDuplicateGroupsRow, error) {
+	rows, err := q.db.Query(ctx, getDuplicateGroups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDuplicateGroupsRow
+	for rows.Next() {
+		var i GetDuplicateGroupsRow
+		if err := rows.Scan(&i.ContentHash, &i.Count, &i.Ids); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEmbeddingDimensionCounts = `-- name: GetEmbeddingDimensionCounts :many
+SELECT vector_dims(embedding)::int AS dimension, COUNT(*) AS count
+FROM files
+GROUP BY dimension
+ORDER BY dimension
+`
+
+type GetEmbeddingDimensionCountsRow struct {
+	Dimension int32
+	Count     int64
+}
+
+func (q *Queries) GetEmbeddingDimensionCounts(ctx context.Context) ([]GetEmbeddingDimensionCountsRow, error) {
+	rows, err := q.db.Query(ctx, getEmbeddingDimensionCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEmbeddingDimensionCountsRow
+	for rows.Next() {
+		var i GetEmbeddingDimensionCountsRow
+		if err := rows.Scan(&i.Dimension, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEmbeddingsByIDs = `-- name: GetEmbeddingsByIDs :many
+SELECT id, embedding FROM files
+WHERE id = ANY($1::uuid[])
+`
+
+type GetEmbeddingsByIDsRow struct {
+	ID        pgtype.UUID
+	Embedding pgvector.Vector
+}
+
+func (q *Queries) GetEmbeddingsByIDs(ctx context.Context, ids []pgtype.UUID) ([]GetEmbeddingsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, getEmbeddingsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEmbeddingsByIDsRow
+	for rows.Next() {
+		var i GetEmbeddingsByIDsRow
+		if err := rows.Scan(&i.ID, &i.Embedding); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFile = `-- name: GetFile :one
-SELECT id, filename, content, embedding, created_at, deleted FROM files WHERE id = $1
+SELECT id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding FROM files WHERE id = $1
 `
 
 func (q *Queries) GetFile(ctx context.Context, id pgtype.UUID) (File, error) {
@@ -133,17 +565,96 @@ func (q *Queries) GetFile(ctx context.Context, id pgtype.UUID) (File, error) {
 		&i.Content,
 		&i.Embedding,
 		&i.CreatedAt,
+		&i.ContentHash,
+		&i.UpdatedAt,
+		&i.ReviewedAt,
+		&i.MimeType,
+		&i.DeletedAt,
+		&i.Deleted,
+		&i.Tags,
+		&i.EmbeddingTruncated,
+		&i.Summary,
+		&i.SummaryEmbedding,
+	)
+	return i, err
+}
+
+const getFileByContentHash = `-- name: GetFileByContentHash :one
+SELECT id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding FROM files
+WHERE content_hash = $1 AND deleted IS NOT TRUE
+ORDER BY created_at, id
+LIMIT 1
+`
+
+func (q *Queries) GetFileByContentHash(ctx context.Context, contentHash pgtype.Text) (File, error) {
+	row := q.db.QueryRow(ctx, getFileByContentHash, contentHash)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.Filename,
+		&i.Content,
+		&i.Embedding,
+		&i.CreatedAt,
+		&i.ContentHash,
+		&i.UpdatedAt,
+		&i.ReviewedAt,
+		&i.MimeType,
+		&i.DeletedAt,
 		&i.Deleted,
+		&i.Tags,
+		&i.EmbeddingTruncated,
+		&i.Summary,
+		&i.SummaryEmbedding,
 	)
 	return i, err
 }
 
+const getFileContent = `-- name: GetFileContent :one
+SELECT filename, content, updated_at FROM files WHERE id = $1
+`
+
+type GetFileContentRow struct {
+	Filename  string
+	Content   string
+	UpdatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetFileContent(ctx context.Context, id pgtype.UUID) (GetFileContentRow, error) {
+	row := q.db.QueryRow(ctx, getFileContent, id)
+	var i GetFileContentRow
+	err := row.Scan(&i.Filename, &i.Content, &i.UpdatedAt)
+	return i, err
+}
+
+const getFileEmbedding = `-- name: GetFileEmbedding :one
+SELECT embedding FROM files WHERE id = $1
+`
+
+func (q *Queries) GetFileEmbedding(ctx context.Context, id pgtype.UUID) (pgvector.Vector, error) {
+	row := q.db.QueryRow(ctx, getFileEmbedding, id)
+	var embedding pgvector.Vector
+	err := row.Scan(&embedding)
+	return embedding, err
+}
+
 const getFileMetadata = `-- name: GetFileMetadata :many
 SELECT id, filename, LENGTH(content) AS size, created_at
 FROM files
-ORDER BY created_at DESC
+ORDER BY
+  CASE WHEN $1::text = 'created_at' AND $2::boolean THEN created_at END DESC,
+  CASE WHEN $1::text = 'created_at' AND NOT $2::boolean THEN created_at END ASC,
+  CASE WHEN $1::text = 'filename' AND $2::boolean THEN filename END DESC,
+  CASE WHEN $1::text = 'filename' AND NOT $2::boolean THEN filename END ASC,
+  CASE WHEN $1::text = 'size' AND $2::boolean THEN LENGTH(content) END DESC,
+  CASE WHEN $1::text = 'size' AND NOT $2::boolean THEN LENGTH(content) END ASC,
+  id DESC
 `
 
+type GetFileMetadataParams struct {
+	Sort       string
+	Descending bool
+}
+
 type GetFileMetadataRow struct {
 	ID        pgtype.UUID
 	Filename  string
@@ -151,8 +662,8 @@ type GetFileMetadataRow struct {
 	CreatedAt pgtype.Timestamptz
 }
 
-func (q *Queries) GetFileMetadata(ctx context.Context) ([]GetFileMetadataRow, error) {
-	rows, err := q.db.Query(ctx, getFileMetadata)
+func (q *Queries) GetFileMetadata(ctx context.Context, arg GetFileMetadataParams) ([]GetFileMetadataRow, error) {
+	rows, err := q.db.Query(ctx, getFileMetadata, arg.Sort, arg.Descending)
 	if err != nil {
 		return nil, err
 	}
@@ -176,8 +687,36 @@ func (q *Queries) GetFileMetadata(ctx context.Context) ([]GetFileMetadataRow, er
 	return items, nil
 }
 
+const getFileVersion = `-- name: GetFileVersion :one
+SELECT file_id, version, filename, content, embedding, content_hash, mime_type, tags, created_at, embedding_truncated FROM file_versions
+WHERE file_id = $1 AND version = $2
+`
+
+type GetFileVersionParams struct {
+	FileID  pgtype.UUID
+	Version int32
+}
+
+func (q *Queries) GetFileVersion(ctx context.Context, arg GetFileVersionParams) (FileVersion, error) {
+	row := q.db.QueryRow(ctx, getFileVersion, arg.FileID, arg.Version)
+	var i FileVersion
+	err := row.Scan(
+		&i.FileID,
+		&i.Version,
+		&i.Filename,
+		&i.Content,
+		&i.Embedding,
+		&i.ContentHash,
+		&i.MimeType,
+		&i.Tags,
+		&i.CreatedAt,
+		&i.EmbeddingTruncated,
+	)
+	return i, err
+}
+
 const getFilesByDateRange = `-- name: GetFilesByDateRange :many
-SELECT id, filename, content, embedding, created_at, deleted FROM files
+SELECT id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding FROM files
 WHERE created_at BETWEEN $1 AND $2
 ORDER BY created_at DESC
 `
@@ -202,7 +741,16 @@ func (q *Queries) GetFilesByDateRange(ctx context.Context, arg GetFilesByDateRan
 			&i.Content,
 			&i.Embedding,
 			&i.CreatedAt,
+			&i.ContentHash,
+			&i.UpdatedAt,
+			&i.ReviewedAt,
+			&i.MimeType,
+			&i.DeletedAt,
 			&i.Deleted,
+			&i.Tags,
+			&i.EmbeddingTruncated,
+			&i.Summary,
+			&i.SummaryEmbedding,
 		); err != nil {
 			return nil, err
 		}
@@ -215,13 +763,23 @@ func (q *Queries) GetFilesByDateRange(ctx context.Context, arg GetFilesByDateRan
 }
 
 const getFilesByFilename = `-- name: GetFilesByFilename :many
-SELECT id, filename, content, embedding, created_at, deleted FROM files
-WHERE filename ILIKE '%' || $1 || '%'
+SELECT id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding FROM files
+WHERE CASE WHEN $1::boolean
+        THEN filename LIKE '%' || $2::text || '%'
+        ELSE filename ILIKE '%' || $2::text || '%'
+      END
+  AND ($3::text IS NULL OR mime_type = $3::text)
 ORDER BY id DESC
 `
 
-func (q *Queries) GetFilesByFilename(ctx context.Context, dollar_1 pgtype.Text) ([]File, error) {
-	rows, err := q.db.Query(ctx, getFilesByFilename, dollar_1)
+type GetFilesByFilenameParams struct {
+	CaseSensitive bool
+	Query         string
+	MimeType      pgtype.Text
+}
+
+func (q *Queries) GetFilesByFilename(ctx context.Context, arg GetFilesByFilenameParams) ([]File, error) {
+	rows, err := q.db.Query(ctx, getFilesByFilename, arg.CaseSensitive, arg.Query, arg.MimeType)
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +793,16 @@ func (q *Queries) GetFilesByFilename(ctx context.Context, dollar_1 pgtype.Text)
 			&i.Content,
 			&i.Embedding,
 			&i.CreatedAt,
+			&i.ContentHash,
+			&i.UpdatedAt,
+			&i.ReviewedAt,
+			&i.MimeType,
+			&i.DeletedAt,
 			&i.Deleted,
+			&i.Tags,
+			&i.EmbeddingTruncated,
+			&i.Summary,
+			&i.SummaryEmbedding,
 		); err != nil {
 			return nil, err
 		}
@@ -247,17 +814,999 @@ func (q *Queries) GetFilesByFilename(ctx context.Context, dollar_1 pgtype.Text)
 	return items, nil
 }
 
-const softDeleteFile = `-- name: SoftDeleteFile :exec
-UPDATE files SET deleted = TRUE WHERE id = $1
+const getFilesByTag = `-- name: GetFilesByTag :many
+SELECT id, filename, LENGTH(content)::int AS size, created_at, deleted, mime_type, tags
+FROM files
+WHERE $1::text = ANY(tags) AND deleted IS NOT TRUE
+ORDER BY created_at DESC, id
 `
 
-func (q *Queries) SoftDeleteFile(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, softDeleteFile, id)
+type GetFilesByTagRow struct {
+	ID        pgtype.UUID
+	Filename  string
+	Size      int32
+	CreatedAt pgtype.Timestamptz
+	Deleted   pgtype.Bool
+	MimeType  string
+	Tags      []string
+}
+
+func (q *Queries) GetFilesByTag(ctx context.Context, tag string) ([]GetFilesByTagRow, error) {
+	rows, err := q.db.Query(ctx, getFilesByTag, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFilesByTagRow
+	for rows.Next() {
+		var i GetFilesByTagRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.Size,
+			&i.CreatedAt,
+			&i.Deleted,
+			&i.MimeType,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFilesMissingContentHash = `-- name: GetFilesMissingContentHash :many
+SELECT id, content FROM files
+WHERE content_hash IS NULL AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type GetFilesMissingContentHashParams struct {
+	AfterID   pgtype.UUID
+	BatchSize int32
+}
+
+type GetFilesMissingContentHashRow struct {
+	ID      pgtype.UUID
+	Content string
+}
+
+func (q *Queries) GetFilesMissingContentHash(ctx context.Context, arg GetFilesMissingContentHashParams) ([]GetFilesMissingContentHashRow, error) {
+	rows, err := q.db.Query(ctx, getFilesMissingContentHash, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFilesMissingContentHashRow
+	for rows.Next() {
+		var i GetFilesMissingContentHashRow
+		if err := rows.Scan(&i.ID, &i.Content); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLatestFileByFilename = `-- name: GetLatestFileByFilename :one
+SELECT id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding FROM files
+WHERE filename = $1 AND deleted IS NOT TRUE
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestFileByFilename(ctx context.Context, filename string) (File, error) {
+	row := q.db.QueryRow(ctx, getLatestFileByFilename, filename)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.Filename,
+		&i.Content,
+		&i.Embedding,
+		&i.CreatedAt,
+		&i.ContentHash,
+		&i.UpdatedAt,
+		&i.ReviewedAt,
+		&i.MimeType,
+		&i.DeletedAt,
+		&i.Deleted,
+		&i.Tags,
+		&i.EmbeddingTruncated,
+		&i.Summary,
+		&i.SummaryEmbedding,
+	)
+	return i, err
+}
+
+const getNearestNeighbors = `-- name: GetNearestNeighbors :many
+SELECT id, filename, (embedding <=> $1::vector)::float8 AS distance
+FROM files
+WHERE id <> $2 AND deleted IS NOT TRUE
+ORDER BY embedding <=> $1::vector, created_at, id
+LIMIT $3
+`
+
+type GetNearestNeighborsParams struct {
+	Embedding pgvector.Vector
+	ExcludeID pgtype.UUID
+	TopK      int32
+}
+
+type GetNearestNeighborsRow struct {
+	ID       pgtype.UUID
+	Filename string
+	Distance float64
+}
+
+func (q *Queries) GetNearestNeighbors(ctx context.Context, arg GetNearestNeighborsParams) ([]GetNearestNeighborsRow, error) {
+	rows, err := q.db.Query(ctx, getNearestNeighbors, arg.Embedding, arg.ExcludeID, arg.TopK)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNearestNeighborsRow
+	for rows.Next() {
+		var i GetNearestNeighborsRow
+		if err := rows.Scan(&i.ID, &i.Filename, &i.Distance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOldestFiles = `-- name: GetOldestFiles :many
+SELECT id, filename, LENGTH(content)::int AS size, created_at
+FROM files
+WHERE $1::boolean OR deleted IS NOT TRUE
+ORDER BY created_at ASC, id ASC
+LIMIT $2
+`
+
+type GetOldestFilesParams struct {
+	IncludeDeleted bool
+	RowLimit       int32
+}
+
+type GetOldestFilesRow struct {
+	ID        pgtype.UUID
+	Filename  string
+	Size      int32
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetOldestFiles(ctx context.Context, arg GetOldestFilesParams) ([]GetOldestFilesRow, error) {
+	rows, err := q.db.Query(ctx, getOldestFiles, arg.IncludeDeleted, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOldestFilesRow
+	for rows.Next() {
+		var i GetOldestFilesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.Size,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecycleBinSample = `-- name: GetRecycleBinSample :many
+SELECT id, filename, LENGTH(content) AS size, created_at
+FROM files
+WHERE deleted
+ORDER BY deleted_at DESC, created_at DESC
+LIMIT $1
+`
+
+type GetRecycleBinSampleRow struct {
+	ID        pgtype.UUID
+	Filename  string
+	Size      float64
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetRecycleBinSample(ctx context.Context, limit int32) ([]GetRecycleBinSampleRow, error) {
+	rows, err := q.db.Query(ctx, getRecycleBinSample, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecycleBinSampleRow
+	for rows.Next() {
+		var i GetRecycleBinSampleRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.Size,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStorageStats = `-- name: GetStorageStats :one
+SELECT COUNT(*) AS row_count,
+       COALESCE(SUM(vector_dims(embedding)), 0)::bigint AS embedding_values,
+       COALESCE(SUM(octet_length(content)), 0)::bigint AS content_bytes,
+       pg_total_relation_size('files')::bigint AS total_bytes
+FROM files
+`
+
+type GetStorageStatsRow struct {
+	RowCount        int64
+	EmbeddingValues int64
+	ContentBytes    int64
+	TotalBytes      int64
+}
+
+func (q *Queries) GetStorageStats(ctx context.Context) (GetStorageStatsRow, error) {
+	row := q.db.QueryRow(ctx, getStorageStats)
+	var i GetStorageStatsRow
+	err := row.Scan(
+		&i.RowCount,
+		&i.EmbeddingValues,
+		&i.ContentBytes,
+		&i.TotalBytes,
+	)
+	return i, err
+}
+
+const listAuditEntries = `-- name: ListAuditEntries :many
+SELECT id, user_id, action, file_id, status, request_id, created_at
+FROM audit_log
+WHERE $1::uuid IS NULL OR file_id = $1::uuid
+ORDER BY id DESC
+LIMIT $2
+`
+
+type ListAuditEntriesParams struct {
+	FileID   pgtype.UUID
+	RowLimit int32
+}
+
+func (q *Queries) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, listAuditEntries, arg.FileID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Action,
+			&i.FileID,
+			&i.Status,
+			&i.RequestID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFileVersions = `-- name: ListFileVersions :many
+SELECT version, filename, LENGTH(content)::int AS size, content_hash, mime_type, tags, created_at
+FROM file_versions
+WHERE file_id = $1
+ORDER BY version DESC
+`
+
+type ListFileVersionsRow struct {
+	Version     int32
+	Filename    string
+	Size        int32
+	ContentHash pgtype.Text
+	MimeType    string
+	Tags        []string
+	CreatedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) ListFileVersions(ctx context.Context, fileID pgtype.UUID) ([]ListFileVersionsRow, error) {
+	rows, err := q.db.Query(ctx, listFileVersions, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFileVersionsRow
+	for rows.Next() {
+		var i ListFileVersionsRow
+		if err := rows.Scan(
+			&i.Version,
+			&i.Filename,
+			&i.Size,
+			&i.ContentHash,
+			&i.MimeType,
+			&i.Tags,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTags = `-- name: ListTags :many
+SELECT tag::text AS tag, COUNT(*) AS count
+FROM files, unnest(tags) AS tag
+WHERE deleted IS NOT TRUE
+GROUP BY tag
+ORDER BY count DESC, tag
+`
+
+type ListTagsRow struct {
+	Tag   string
+	Count int64
+}
+
+func (q *Queries) ListTags(ctx context.Context) ([]ListTagsRow, error) {
+	rows, err := q.db.Query(ctx, listTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagsRow
+	for rows.Next() {
+		var i ListTagsRow
+		if err := rows.Scan(&i.Tag, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockContentHash = `-- name: LockContentHash :exec
+SELECT pg_advisory_xact_lock(hashtext($1::text))
+`
+
+// Held until the transaction ends, so uploads of the same content check for
+// a duplicate and insert one at a time.
+func (q *Queries) LockContentHash(ctx context.Context, contentHash string) error {
+	_, err := q.db.Exec(ctx, lockContentHash, contentHash)
+	return err
+}
+
+const pruneFileVersions = `-- name: PruneFileVersions :execrows
+DELETE FROM file_versions fv
+WHERE fv.file_id = $1
+  AND fv.version <= (SELECT MAX(v.version) FROM file_versions v WHERE v.file_id = $1) - $2::int
+`
+
+type PruneFileVersionsParams struct {
+	FileID pgtype.UUID
+	Keep   int32
+}
+
+func (q *Queries) PruneFileVersions(ctx context.Context, arg PruneFileVersionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneFileVersions, arg.FileID, arg.Keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeletedBefore = `-- name: PurgeDeletedBefore :execrows
+DELETE FROM files WHERE deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeletedBefore(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedBefore, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeFile = `-- name: PurgeFile :execrows
+DELETE FROM files WHERE id = $1 AND deleted
+`
+
+func (q *Queries) PurgeFile(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeFile, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeRecycleBin = `-- name: PurgeRecycleBin :many
+DELETE FROM files WHERE deleted
+RETURNING id
+`
+
+func (q *Queries) PurgeRecycleBin(ctx context.Context) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, purgeRecycleBin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeAPIKeyParams struct {
+	ID     pgtype.UUID
+	UserID string
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAPIKey, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchFilesCosine = `-- name: SearchFilesCosine :many
+SELECT id, filename, created_at, content_hash, (embedding <=> $1::vector)::float8 AS distance
+FROM files
+WHERE ($2::boolean OR deleted IS NOT TRUE)
+  AND ($3::text IS NULL OR filename ILIKE '%' || $3::text || '%')
+  AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR created_at <= $5::timestamptz)
+  AND ($6::text IS NULL OR mime_type = $6::text)
+ORDER BY embedding <=> $1::vector, created_at, id
+LIMIT $7
+`
+
+type SearchFilesCosineParams struct {
+	Embedding        pgvector.Vector
+	IncludeDeleted   bool
+	FilenameContains pgtype.Text
+	CreatedAfter     pgtype.Timestamptz
+	CreatedBefore    pgtype.Timestamptz
+	MimeType         pgtype.Text
+	TopK             int32
+}
+
+type SearchFilesCosineRow struct {
+	ID          pgtype.UUID
+	Filename    string
+	CreatedAt   pgtype.Timestamptz
+	ContentHash pgtype.Text
+	Distance    float64
+}
+
+func (q *Queries) SearchFilesCosine(ctx context.Context, arg SearchFilesCosineParams) ([]SearchFilesCosineRow, error) {
+	rows, err := q.db.Query(ctx, searchFilesCosine,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchFilesCosineRow
+	for rows.Next() {
+		var i SearchFilesCosineRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.CreatedAt,
+			&i.ContentHash,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchFilesInner = `-- name: SearchFilesInner :many
+SELECT id, filename, created_at, content_hash, (embedding <#> $1::vector)::float8 AS distance
+FROM files
+WHERE ($2::boolean OR deleted IS NOT TRUE)
+  AND ($3::text IS NULL OR filename ILIKE '%' || $3::text || '%')
+  AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR created_at <= $5::timestamptz)
+  AND ($6::text IS NULL OR mime_type = $6::text)
+ORDER BY embedding <#> $1::vector, created_at, id
+LIMIT $7
+`
+
+type SearchFilesInnerParams struct {
+	Embedding        pgvector.Vector
+	IncludeDeleted   bool
+	FilenameContains pgtype.Text
+	CreatedAfter     pgtype.Timestamptz
+	CreatedBefore    pgtype.Timestamptz
+	MimeType         pgtype.Text
+	TopK             int32
+}
+
+type SearchFilesInnerRow struct {
+	ID          pgtype.UUID
+	Filename    string
+	CreatedAt   pgtype.Timestamptz
+	ContentHash pgtype.Text
+	Distance    float64
+}
+
+func (q *Queries) SearchFilesInner(ctx context.Context, arg SearchFilesInnerParams) ([]SearchFilesInnerRow, error) {
+	rows, err := q.db.Query(ctx, searchFilesInner,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchFilesInnerRow
+	for rows.Next() {
+		var i SearchFilesInnerRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.CreatedAt,
+			&i.ContentHash,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchFilesKeyword = `-- name: SearchFilesKeyword :many
+SELECT id, filename, created_at, (filename ILIKE '%' || $1::text || '%')::boolean AS filename_match
+FROM files
+WHERE deleted IS NOT TRUE
+  AND (filename ILIKE '%' || $1::text || '%' OR content ILIKE '%' || $1::text || '%')
+ORDER BY filename_match DESC, created_at DESC, id
+LIMIT $2
+`
+
+type SearchFilesKeywordParams struct {
+	Query string
+	TopK  int32
+}
+
+type SearchFilesKeywordRow struct {
+	ID            pgtype.UUID
+	Filename      string
+	CreatedAt     pgtype.Timestamptz
+	FilenameMatch bool
+}
+
+func (q *Queries) SearchFilesKeyword(ctx context.Context, arg SearchFilesKeywordParams) ([]SearchFilesKeywordRow, error) {
+	rows, err := q.db.Query(ctx, searchFilesKeyword, arg.Query, arg.TopK)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchFilesKeywordRow
+	for rows.Next() {
+		var i SearchFilesKeywordRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.CreatedAt,
+			&i.FilenameMatch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchFilesL2 = `-- name: SearchFilesL2 :many
+SELECT id, filename, created_at, content_hash, (embedding <-> $1::vector)::float8 AS distance
+FROM files
+WHERE ($2::boolean OR deleted IS NOT TRUE)
+  AND ($3::text IS NULL OR filename ILIKE '%' || $3::text || '%')
+  AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR created_at <= $5::timestamptz)
+  AND ($6::text IS NULL OR mime_type = $6::text)
+ORDER BY embedding <-> $1::vector, created_at, id
+LIMIT $7
+`
+
+type SearchFilesL2Params struct {
+	Embedding        pgvector.Vector
+	IncludeDeleted   bool
+	FilenameContains pgtype.Text
+	CreatedAfter     pgtype.Timestamptz
+	CreatedBefore    pgtype.Timestamptz
+	MimeType         pgtype.Text
+	TopK             int32
+}
+
+type SearchFilesL2Row struct {
+	ID          pgtype.UUID
+	Filename    string
+	CreatedAt   pgtype.Timestamptz
+	ContentHash pgtype.Text
+	Distance    float64
+}
+
+func (q *Queries) SearchFilesL2(ctx context.Context, arg SearchFilesL2Params) ([]SearchFilesL2Row, error) {
+	rows, err := q.db.Query(ctx, searchFilesL2,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchFilesL2Row
+	for rows.Next() {
+		var i SearchFilesL2Row
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.CreatedAt,
+			&i.ContentHash,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSummariesCosine = `-- name: SearchSummariesCosine :many
+SELECT id, filename, created_at, content_hash, (summary_embedding <=> $1::vector)::float8 AS distance
+FROM files
+WHERE summary_embedding IS NOT NULL
+  AND ($2::boolean OR deleted IS NOT TRUE)
+  AND ($3::text IS NULL OR filename ILIKE '%' || $3::text || '%')
+  AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR created_at <= $5::timestamptz)
+  AND ($6::text IS NULL OR mime_type = $6::text)
+ORDER BY summary_embedding <=> $1::vector, created_at, id
+LIMIT $7
+`
+
+type SearchSummariesCosineParams struct {
+	Embedding        pgvector.Vector
+	IncludeDeleted   bool
+	FilenameContains pgtype.Text
+	CreatedAfter     pgtype.Timestamptz
+	CreatedBefore    pgtype.Timestamptz
+	MimeType         pgtype.Text
+	TopK             int32
+}
+
+type SearchSummariesCosineRow struct {
+	ID          pgtype.UUID
+	Filename    string
+	CreatedAt   pgtype.Timestamptz
+	ContentHash pgtype.Text
+	Distance    float64
+}
+
+func (q *Queries) SearchSummariesCosine(ctx context.Context, arg SearchSummariesCosineParams) ([]SearchSummariesCosineRow, error) {
+	rows, err := q.db.Query(ctx, searchSummariesCosine,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchSummariesCosineRow
+	for rows.Next() {
+		var i SearchSummariesCosineRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.CreatedAt,
+			&i.ContentHash,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSummariesInner = `-- name: SearchSummariesInner :many
+SELECT id, filename, created_at, content_hash, (summary_embedding <#> $1::vector)::float8 AS distance
+FROM files
+WHERE summary_embedding IS NOT NULL
+  AND ($2::boolean OR deleted IS NOT TRUE)
+  AND ($3::text IS NULL OR filename ILIKE '%' || $3::text || '%')
+  AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR created_at <= $5::timestamptz)
+  AND ($6::text IS NULL OR mime_type = $6::text)
+ORDER BY summary_embedding <#> $1::vector, created_at, id
+LIMIT $7
+`
+
+type SearchSummariesInnerParams struct {
+	Embedding        pgvector.Vector
+	IncludeDeleted   bool
+	FilenameContains pgtype.Text
+	CreatedAfter     pgtype.Timestamptz
+	CreatedBefore    pgtype.Timestamptz
+	MimeType         pgtype.Text
+	TopK             int32
+}
+
+type SearchSummariesInnerRow struct {
+	ID          pgtype.UUID
+	Filename    string
+	CreatedAt   pgtype.Timestamptz
+	ContentHash pgtype.Text
+	Distance    float64
+}
+
+func (q *Queries) SearchSummariesInner(ctx context.Context, arg SearchSummariesInnerParams) ([]SearchSummariesInnerRow, error) {
+	rows, err := q.db.Query(ctx, searchSummariesInner,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchSummariesInnerRow
+	for rows.Next() {
+		var i SearchSummariesInnerRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.CreatedAt,
+			&i.ContentHash,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSummariesL2 = `-- name: SearchSummariesL2 :many
+SELECT id, filename, created_at, content_hash, (summary_embedding <-> $1::vector)::float8 AS distance
+FROM files
+WHERE summary_embedding IS NOT NULL
+  AND ($2::boolean OR deleted IS NOT TRUE)
+  AND ($3::text IS NULL OR filename ILIKE '%' || $3::text || '%')
+  AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR created_at <= $5::timestamptz)
+  AND ($6::text IS NULL OR mime_type = $6::text)
+ORDER BY summary_embedding <-> $1::vector, created_at, id
+LIMIT $7
+`
+
+type SearchSummariesL2Params struct {
+	Embedding        pgvector.Vector
+	IncludeDeleted   bool
+	FilenameContains pgtype.Text
+	CreatedAfter     pgtype.Timestamptz
+	CreatedBefore    pgtype.Timestamptz
+	MimeType         pgtype.Text
+	TopK             int32
+}
+
+type SearchSummariesL2Row struct {
+	ID          pgtype.UUID
+	Filename    string
+	CreatedAt   pgtype.Timestamptz
+	ContentHash pgtype.Text
+	Distance    float64
+}
+
+func (q *Queries) SearchSummariesL2(ctx context.Context, arg SearchSummariesL2Params) ([]SearchSummariesL2Row, error) {
+	rows, err := q.db.Query(ctx, searchSummariesL2,
+		arg.Embedding,
+		arg.IncludeDeleted,
+		arg.FilenameContains,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MimeType,
+		arg.TopK,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchSummariesL2Row
+	for rows.Next() {
+		var i SearchSummariesL2Row
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.CreatedAt,
+			&i.ContentHash,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setContentHash = `-- name: SetContentHash :execrows
+UPDATE files SET content_hash = $2
+WHERE id = $1 AND content_hash IS NULL
+`
+
+type SetContentHashParams struct {
+	ID          pgtype.UUID
+	ContentHash pgtype.Text
+}
+
+func (q *Queries) SetContentHash(ctx context.Context, arg SetContentHashParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setContentHash, arg.ID, arg.ContentHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteFile = `-- name: SoftDeleteFile :exec
+UPDATE files SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteFile(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, softDeleteFile, id)
 	return err
 }
 
+const softDeleteFilesByIDs = `-- name: SoftDeleteFilesByIDs :many
+UPDATE files SET deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
+WHERE id = ANY($1::uuid[])
+RETURNING id
+`
+
+func (q *Queries) SoftDeleteFilesByIDs(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, softDeleteFilesByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchFile = `-- name: TouchFile :one
+UPDATE files
+  SET updated_at = CURRENT_TIMESTAMP,
+      reviewed_at = CASE WHEN $1::boolean THEN CURRENT_TIMESTAMP ELSE reviewed_at END
+WHERE id = $2
+RETURNING updated_at, reviewed_at
+`
+
+type TouchFileParams struct {
+	Reviewed bool
+	ID       pgtype.UUID
+}
+
+type TouchFileRow struct {
+	UpdatedAt  pgtype.Timestamptz
+	ReviewedAt pgtype.Timestamptz
+}
+
+func (q *Queries) TouchFile(ctx context.Context, arg TouchFileParams) (TouchFileRow, error) {
+	row := q.db.QueryRow(ctx, touchFile, arg.Reviewed, arg.ID)
+	var i TouchFileRow
+	err := row.Scan(&i.UpdatedAt, &i.ReviewedAt)
+	return i, err
+}
+
 const undoSoftDelete = `-- name: UndoSoftDelete :exec
-UPDATE files SET deleted = FALSE WHERE id = $1
+UPDATE files SET deleted_at = NULL WHERE id = $1
 `
 
 func (q *Queries) UndoSoftDelete(ctx context.Context, id pgtype.UUID) error {
@@ -267,16 +1816,24 @@ func (q *Queries) UndoSoftDelete(ctx context.Context, id pgtype.UUID) error {
 
 const updateFile = `-- name: UpdateFile :one
 UPDATE files
-  SET filename = $2, content = $3, embedding = $4
+  SET filename = $2, content = $3, embedding = $4, content_hash = $5, mime_type = $6,
+      embedding_truncated = $7, tags = COALESCE($8::text[], tags),
+      summary = $9, summary_embedding = $10, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, filename, content, embedding, created_at, deleted
+RETURNING id, filename, content, embedding, created_at, content_hash, updated_at, reviewed_at, mime_type, deleted_at, deleted, tags, embedding_truncated, summary, summary_embedding
 `
 
 type UpdateFileParams struct {
-	ID        pgtype.UUID
-	Filename  string
-	Content   string
-	Embedding pgvector.Vector
+	ID                 pgtype.UUID
+	Filename           string
+	Content            string
+	Embedding          pgvector.Vector
+	ContentHash        pgtype.Text
+	MimeType           string
+	EmbeddingTruncated bool
+	Tags               []string
+	Summary            pgtype.Text
+	SummaryEmbedding   *pgvector.Vector
 }
 
 func (q *Queries) UpdateFile(ctx context.Context, arg UpdateFileParams) (File, error) {
@@ -285,6 +1842,12 @@ func (q *Queries) UpdateFile(ctx context.Context, arg UpdateFileParams) (File, e
 		arg.Filename,
 		arg.Content,
 		arg.Embedding,
+		arg.ContentHash,
+		arg.MimeType,
+		arg.EmbeddingTruncated,
+		arg.Tags,
+		arg.Summary,
+		arg.SummaryEmbedding,
 	)
 	var i File
 	err := row.Scan(
@@ -293,7 +1856,36 @@ func (q *Queries) UpdateFile(ctx context.Context, arg UpdateFileParams) (File, e
 		&i.Content,
 		&i.Embedding,
 		&i.CreatedAt,
+		&i.ContentHash,
+		&i.UpdatedAt,
+		&i.ReviewedAt,
+		&i.MimeType,
+		&i.DeletedAt,
 		&i.Deleted,
+		&i.Tags,
+		&i.EmbeddingTruncated,
+		&i.Summary,
+		&i.SummaryEmbedding,
 	)
 	return i, err
 }
+
+const updateFileEmbedding = `-- name: UpdateFileEmbedding :one
+UPDATE files
+  SET embedding = $1, embedding_truncated = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $3
+RETURNING updated_at
+`
+
+type UpdateFileEmbeddingParams struct {
+	Embedding          pgvector.Vector
+	EmbeddingTruncated bool
+	ID                 pgtype.UUID
+}
+
+func (q *Queries) UpdateFileEmbedding(ctx context.Context, arg UpdateFileEmbeddingParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, updateFileEmbedding, arg.Embedding, arg.EmbeddingTruncated, arg.ID)
+	var updated_at pgtype.Timestamptz
+	err := row.Scan(&updated_at)
+	return updated_at, err
+}
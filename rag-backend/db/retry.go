@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// defaultConnectBackoff is the wait before the first retry when none is configured.
+	defaultConnectBackoff = 500 * time.Millisecond
+	// maxConnectBackoff caps the doubling wait between attempts.
+	maxConnectBackoff = 8 * time.Second
+)
+
+// ConnectRetry controls how long startup waits for the database, so the app
+// can start before Postgres is accepting connections.
+type ConnectRetry struct {
+	// Retries is how many times a failed attempt is repeated; 0 tries once.
+	Retries int
+	// Timeout caps the total time spent, including backoff; 0 means no cap.
+	Timeout time.Duration
+	// InitialBackoff is the first wait, doubled after each failure up to 8s.
+	// Zero uses 500ms.
+	InitialBackoff time.Duration
+}
+
+// Validate rejects negative retry counts and timeouts.
+func (r ConnectRetry) Validate() error {
+	if r.Retries < 0 {
+		return fmt.Errorf("connect retries must not be negative, got %d", r.Retries)
+	}
+	if r.Timeout < 0 {
+		return fmt.Errorf("connect timeout must not be negative, got %s", r.Timeout)
+	}
+	return nil
+}
+
+// Do runs op until it succeeds, the retries are used up, or the timeout
+// expires, backing off exponentially between attempts. It returns the last
+// error from op.
+func (r ConnectRetry) Do(ctx context.Context, op func(context.Context) error) error {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	backoff := r.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultConnectBackoff
+	}
+
+	attempts := r.Retries + 1
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = op(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			return fmt.Errorf("database unreachable after %d attempt(s): %w", attempt, err)
+		}
+
+		log.Printf("Database not ready (attempt %d/%d): %v; retrying in %s", attempt, attempts, err, backoff)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database unreachable within %s: %w", r.Timeout, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
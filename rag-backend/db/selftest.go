@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+const selfTestFilename = "__self_test__"
+
+// SelfTest exercises the database end to end: it checks the schema, inserts a
+// probe vector, runs a similarity query against it, and deletes it. Everything
+// runs inside a transaction that is always rolled back, so real data is untouched.
+func (q *Queries) SelfTest(ctx context.Context) error {
+	beginner, ok := q.db.(txBeginner)
+	if !ok {
+		return errors.New("self-test requires a connection pool")
+	}
+
+	var tableExists bool
+	if err := q.db.QueryRow(ctx, "SELECT to_regclass('files') IS NOT NULL").Scan(&tableExists); err != nil {
+		return selfTestStep("check files table", err)
+	}
+	if !tableExists {
+		return selfTestStep("check files table", errors.New("files table does not exist; run the migrations"))
+	}
+	selfTestStep("check files table", nil)
+
+	dim, err := q.EmbeddingColumnDim(ctx)
+	if err == nil && dim <= 0 {
+		err = errors.New("embedding column has no declared dimension; start the server once with EXPECTED_EMBEDDING_DIM set")
+	}
+	if err != nil {
+		return selfTestStep("read embedding dimension", err)
+	}
+	selfTestStep(fmt.Sprintf("read embedding dimension (%d)", dim), nil)
+
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return selfTestStep("begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := q.WithTx(tx)
+
+	probe := make([]float32, dim)
+	for i := range probe {
+		probe[i] = 1
+	}
+	vec := pgvector.NewVector(probe)
+
+	file, err := qtx.CreateFile(ctx, CreateFileParams{
+		Filename:  selfTestFilename,
+		Content:   selfTestFilename,
+		Embedding: vec,
+		MimeType:  "text/plain",
+	})
+	if err != nil {
+		return selfTestStep("insert probe vector", err)
+	}
+	selfTestStep("insert probe vector", nil)
+
+	var distance float64
+	err = tx.QueryRow(ctx,
+		"SELECT embedding <=> $1 FROM files ORDER BY embedding <=> $1 LIMIT 1", vec,
+	).Scan(&distance)
+	if err != nil {
+		return selfTestStep("run similarity query", err)
+	}
+	selfTestStep(fmt.Sprintf("run similarity query (nearest distance %.4f)", distance), nil)
+
+	if _, err := qtx.DeleteFile(ctx, file.ID); err != nil {
+		return selfTestStep("delete probe vector", err)
+	}
+	selfTestStep("delete probe vector", nil)
+
+	return nil
+}
+
+// selfTestStep logs the outcome of one self-test step and passes its error through.
+func selfTestStep(step string, err error) error {
+	if err != nil {
+		log.Printf("self-test: %s: FAILED: %v", step, err)
+		return fmt.Errorf("%s: %w", step, err)
+	}
+	log.Printf("self-test: %s: ok", step)
+	return nil
+}
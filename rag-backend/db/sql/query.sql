@@ -1,23 +1,62 @@
 -- name: CreateFile :one
-INSERT INTO files (filename, content, embedding)
-VALUES ($1, $2, $3)
+INSERT INTO files (filename, content, embedding, content_hash, mime_type, embedding_truncated, tags, summary, summary_embedding)
+VALUES ($1, $2, $3, $4, $5, $6, COALESCE(sqlc.narg(tags)::text[], '{}'), sqlc.narg(summary), sqlc.narg(summary_embedding))
 RETURNING *;
 
 -- name: GetFile :one
 SELECT * FROM files WHERE id = $1;
 
+-- name: GetLatestFileByFilename :one
+SELECT * FROM files
+WHERE filename = $1 AND deleted IS NOT TRUE
+ORDER BY created_at DESC
+LIMIT 1;
+
 -- name: GetAllFiles :many
-SELECT * FROM files ORDER BY id DESC;
+SELECT * FROM files
+WHERE sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text
+ORDER BY
+  CASE WHEN @sort::text = 'created_at' AND @descending::boolean THEN created_at END DESC,
+  CASE WHEN @sort::text = 'created_at' AND NOT @descending::boolean THEN created_at END ASC,
+  CASE WHEN @sort::text = 'filename' AND @descending::boolean THEN filename END DESC,
+  CASE WHEN @sort::text = 'filename' AND NOT @descending::boolean THEN filename END ASC,
+  CASE WHEN @sort::text = 'size' AND @descending::boolean THEN LENGTH(content) END DESC,
+  CASE WHEN @sort::text = 'size' AND NOT @descending::boolean THEN LENGTH(content) END ASC,
+  id DESC;
+
+-- name: GetAllFileSummaries :many
+SELECT id, filename, LENGTH(content)::int AS size, created_at, deleted, mime_type
+FROM files
+WHERE sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text
+ORDER BY
+  CASE WHEN @sort::text = 'created_at' AND @descending::boolean THEN created_at END DESC,
+  CASE WHEN @sort::text = 'created_at' AND NOT @descending::boolean THEN created_at END ASC,
+  CASE WHEN @sort::text = 'filename' AND @descending::boolean THEN filename END DESC,
+  CASE WHEN @sort::text = 'filename' AND NOT @descending::boolean THEN filename END ASC,
+  CASE WHEN @sort::text = 'size' AND @descending::boolean THEN LENGTH(content) END DESC,
+  CASE WHEN @sort::text = 'size' AND NOT @descending::boolean THEN LENGTH(content) END ASC,
+  id DESC;
 
 -- name: GetFilesByFilename :many
 SELECT * FROM files
-WHERE filename ILIKE '%' || $1 || '%'
+WHERE CASE WHEN @case_sensitive::boolean
+        THEN filename LIKE '%' || @query::text || '%'
+        ELSE filename ILIKE '%' || @query::text || '%'
+      END
+  AND (sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text)
 ORDER BY id DESC;
 
 -- name: GetFileMetadata :many
 SELECT id, filename, LENGTH(content) AS size, created_at
 FROM files
-ORDER BY created_at DESC;
+ORDER BY
+  CASE WHEN @sort::text = 'created_at' AND @descending::boolean THEN created_at END DESC,
+  CASE WHEN @sort::text = 'created_at' AND NOT @descending::boolean THEN created_at END ASC,
+  CASE WHEN @sort::text = 'filename' AND @descending::boolean THEN filename END DESC,
+  CASE WHEN @sort::text = 'filename' AND NOT @descending::boolean THEN filename END ASC,
+  CASE WHEN @sort::text = 'size' AND @descending::boolean THEN LENGTH(content) END DESC,
+  CASE WHEN @sort::text = 'size' AND NOT @descending::boolean THEN LENGTH(content) END ASC,
+  id DESC;
 
 
 -- name: GetFilesByDateRange :many
@@ -28,21 +67,290 @@ ORDER BY created_at DESC;
 
 -- name: UpdateFile :one
 UPDATE files
-  SET filename = $2, content = $3, embedding = $4
+  SET filename = $2, content = $3, embedding = $4, content_hash = $5, mime_type = $6,
+      embedding_truncated = $7, tags = COALESCE(sqlc.narg(tags)::text[], tags),
+      summary = sqlc.narg(summary), summary_embedding = sqlc.narg(summary_embedding), updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteFile :exec
+-- name: DeleteFile :execrows
 DELETE FROM files WHERE id = $1;
 
 -- name: SoftDeleteFile :exec
-UPDATE files SET deleted = TRUE WHERE id = $1;
+UPDATE files SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: UndoSoftDelete :exec
-UPDATE files SET deleted = FALSE WHERE id = $1;
+UPDATE files SET deleted_at = NULL WHERE id = $1;
+
+-- name: PurgeFile :execrows
+DELETE FROM files WHERE id = $1 AND deleted;
+
+-- name: PurgeRecycleBin :many
+DELETE FROM files WHERE deleted
+RETURNING id;
+
+-- name: CountRecycleBin :one
+SELECT COUNT(*) FROM files WHERE deleted;
+
+-- name: GetRecycleBinSample :many
+SELECT id, filename, LENGTH(content) AS size, created_at
+FROM files
+WHERE deleted
+ORDER BY deleted_at DESC, created_at DESC
+LIMIT $1;
+
+-- name: PurgeDeletedBefore :execrows
+DELETE FROM files WHERE deleted_at < @cutoff::timestamptz;
 
 -- name: GetDeletedFiles :many
-SELECT * FROM files WHERE deleted = TRUE ORDER BY created_at DESC;
+SELECT * FROM files WHERE deleted = TRUE ORDER BY deleted_at DESC, created_at DESC;
 
 -- name: CountTotalFiles :one
-SELECT COUNT(*) FROM files;
\ No newline at end of file
+SELECT COUNT(*) FROM files;
+-- name: GetEmbeddingsByIDs :many
+SELECT id, embedding FROM files
+WHERE id = ANY(@ids::uuid[]);
+
+-- name: GetNearestNeighbors :many
+SELECT id, filename, (embedding <=> @embedding::vector)::float8 AS distance
+FROM files
+WHERE id <> @exclude_id AND deleted IS NOT TRUE
+ORDER BY embedding <=> @embedding::vector, created_at, id
+LIMIT @top_k;
+
+-- name: GetOldestFiles :many
+SELECT id, filename, LENGTH(content)::int AS size, created_at
+FROM files
+WHERE @include_deleted::boolean OR deleted IS NOT TRUE
+ORDER BY created_at ASC, id ASC
+LIMIT @row_limit;
+
+-- name: GetFileContent :one
+SELECT filename, content, updated_at FROM files WHERE id = $1;
+
+-- name: GetEmbeddingDimensionCounts :many
+SELECT vector_dims(embedding)::int AS dimension, COUNT(*) AS count
+FROM files
+GROUP BY dimension
+ORDER BY dimension;
+
+-- name: GetFileEmbedding :one
+SELECT embedding FROM files WHERE id = $1;
+
+-- name: SearchFilesCosine :many
+SELECT id, filename, created_at, content_hash, (embedding <=> @embedding::vector)::float8 AS distance
+FROM files
+WHERE (@include_deleted::boolean OR deleted IS NOT TRUE)
+  AND (sqlc.narg(filename_contains)::text IS NULL OR filename ILIKE '%' || sqlc.narg(filename_contains)::text || '%')
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at <= sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text)
+ORDER BY embedding <=> @embedding::vector, created_at, id
+LIMIT @top_k;
+
+-- name: SearchFilesKeyword :many
+SELECT id, filename, created_at, (filename ILIKE '%' || @query::text || '%')::boolean AS filename_match
+FROM files
+WHERE deleted IS NOT TRUE
+  AND (filename ILIKE '%' || @query::text || '%' OR content ILIKE '%' || @query::text || '%')
+ORDER BY filename_match DESC, created_at DESC, id
+LIMIT @top_k;
+
+-- name: SearchFilesL2 :many
+SELECT id, filename, created_at, content_hash, (embedding <-> @embedding::vector)::float8 AS distance
+FROM files
+WHERE (@include_deleted::boolean OR deleted IS NOT TRUE)
+  AND (sqlc.narg(filename_contains)::text IS NULL OR filename ILIKE '%' || sqlc.narg(filename_contains)::text || '%')
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at <= sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text)
+ORDER BY embedding <-> @embedding::vector, created_at, id
+LIMIT @top_k;
+
+-- name: SearchFilesInner :many
+SELECT id, filename, created_at, content_hash, (embedding <#> @embedding::vector)::float8 AS distance
+FROM files
+WHERE (@include_deleted::boolean OR deleted IS NOT TRUE)
+  AND (sqlc.narg(filename_contains)::text IS NULL OR filename ILIKE '%' || sqlc.narg(filename_contains)::text || '%')
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at <= sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text)
+ORDER BY embedding <#> @embedding::vector, created_at, id
+LIMIT @top_k;
+
+-- name: SearchSummariesCosine :many
+SELECT id, filename, created_at, content_hash, (summary_embedding <=> @embedding::vector)::float8 AS distance
+FROM files
+WHERE summary_embedding IS NOT NULL
+  AND (@include_deleted::boolean OR deleted IS NOT TRUE)
+  AND (sqlc.narg(filename_contains)::text IS NULL OR filename ILIKE '%' || sqlc.narg(filename_contains)::text || '%')
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at <= sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text)
+ORDER BY summary_embedding <=> @embedding::vector, created_at, id
+LIMIT @top_k;
+
+-- name: SearchSummariesL2 :many
+SELECT id, filename, created_at, content_hash, (summary_embedding <-> @embedding::vector)::float8 AS distance
+FROM files
+WHERE summary_embedding IS NOT NULL
+  AND (@include_deleted::boolean OR deleted IS NOT TRUE)
+  AND (sqlc.narg(filename_contains)::text IS NULL OR filename ILIKE '%' || sqlc.narg(filename_contains)::text || '%')
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at <= sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text)
+ORDER BY summary_embedding <-> @embedding::vector, created_at, id
+LIMIT @top_k;
+
+-- name: SearchSummariesInner :many
+SELECT id, filename, created_at, content_hash, (summary_embedding <#> @embedding::vector)::float8 AS distance
+FROM files
+WHERE summary_embedding IS NOT NULL
+  AND (@include_deleted::boolean OR deleted IS NOT TRUE)
+  AND (sqlc.narg(filename_contains)::text IS NULL OR filename ILIKE '%' || sqlc.narg(filename_contains)::text || '%')
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at <= sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(mime_type)::text IS NULL OR mime_type = sqlc.narg(mime_type)::text)
+ORDER BY summary_embedding <#> @embedding::vector, created_at, id
+LIMIT @top_k;
+
+-- name: GetStorageStats :one
+SELECT COUNT(*) AS row_count,
+       COALESCE(SUM(vector_dims(embedding)), 0)::bigint AS embedding_values,
+       COALESCE(SUM(octet_length(content)), 0)::bigint AS content_bytes,
+       pg_total_relation_size('files')::bigint AS total_bytes
+FROM files;
+
+-- name: UpdateFileEmbedding :one
+UPDATE files
+  SET embedding = @embedding, embedding_truncated = @embedding_truncated, updated_at = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING updated_at;
+
+-- name: TouchFile :one
+UPDATE files
+  SET updated_at = CURRENT_TIMESTAMP,
+      reviewed_at = CASE WHEN @reviewed::boolean THEN CURRENT_TIMESTAMP ELSE reviewed_at END
+WHERE id = @id
+RETURNING updated_at, reviewed_at;
+
+-- name: GetFileByContentHash :one
+SELECT * FROM files
+WHERE content_hash = @content_hash AND deleted IS NOT TRUE
+ORDER BY created_at, id
+LIMIT 1;
+
+-- name: LockContentHash :exec
+-- Held until the transaction ends, so uploads of the same content check for
+-- a duplicate and insert one at a time.
+SELECT pg_advisory_xact_lock(hashtext(@content_hash::text));
+
+-- name: GetDuplicateGroups :many
+SELECT content_hash::text AS content_hash, COUNT(*) AS count,
+       array_agg(id ORDER BY created_at, id)::uuid[] AS ids
+FROM files
+WHERE content_hash IS NOT NULL AND deleted IS NOT TRUE
+GROUP BY content_hash
+HAVING COUNT(*) > 1
+ORDER BY count DESC, content_hash;
+
+-- name: FindExistingFiles :many
+SELECT id, filename, content_hash
+FROM files
+WHERE deleted IS NOT TRUE
+  AND (filename = ANY(@filenames::text[]) OR content_hash = ANY(@hashes::text[]))
+ORDER BY created_at DESC, id;
+
+-- name: GetFilesMissingContentHash :many
+SELECT id, content FROM files
+WHERE content_hash IS NULL AND id > @after_id
+ORDER BY id
+LIMIT @batch_size;
+
+-- name: SetContentHash :execrows
+UPDATE files SET content_hash = $2
+WHERE id = $1 AND content_hash IS NULL;
+
+-- name: CountFilesMissingContentHash :one
+SELECT COUNT(*) FROM files WHERE content_hash IS NULL;
+
+-- name: CloneFile :one
+INSERT INTO files (filename, content, embedding, content_hash, mime_type, tags, embedding_truncated, summary, summary_embedding)
+SELECT COALESCE(sqlc.narg(filename)::text, 'Copy of ' || src.filename), src.content, src.embedding, src.content_hash, src.mime_type, src.tags, src.embedding_truncated, src.summary, src.summary_embedding
+FROM files src
+WHERE src.id = @id AND src.deleted IS NOT TRUE
+RETURNING *;
+
+-- name: GetDatabaseSize :one
+SELECT pg_database_size(current_database())::bigint AS size;
+
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, key_hash)
+VALUES ($1, $2)
+RETURNING id, user_id, created_at;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT id, user_id, key_hash FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: DeleteFilesByIDs :many
+DELETE FROM files WHERE id = ANY(@ids::uuid[])
+RETURNING id;
+
+-- name: SoftDeleteFilesByIDs :many
+UPDATE files SET deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
+WHERE id = ANY(@ids::uuid[])
+RETURNING id;
+
+-- name: GetFilesByTag :many
+SELECT id, filename, LENGTH(content)::int AS size, created_at, deleted, mime_type, tags
+FROM files
+WHERE @tag::text = ANY(tags) AND deleted IS NOT TRUE
+ORDER BY created_at DESC, id;
+
+-- name: ListTags :many
+SELECT tag::text AS tag, COUNT(*) AS count
+FROM files, unnest(tags) AS tag
+WHERE deleted IS NOT TRUE
+GROUP BY tag
+ORDER BY count DESC, tag;
+
+-- name: CreateFileVersion :one
+-- Snapshots the file's current state as its next version number.
+INSERT INTO file_versions (file_id, version, filename, content, embedding, content_hash, mime_type, tags, embedding_truncated)
+SELECT f.id,
+       COALESCE((SELECT MAX(v.version) FROM file_versions v WHERE v.file_id = f.id), 0) + 1,
+       f.filename, f.content, f.embedding, f.content_hash, f.mime_type, f.tags, f.embedding_truncated
+FROM files f
+WHERE f.id = @file_id
+RETURNING version;
+
+-- name: PruneFileVersions :execrows
+DELETE FROM file_versions fv
+WHERE fv.file_id = @file_id
+  AND fv.version <= (SELECT MAX(v.version) FROM file_versions v WHERE v.file_id = @file_id) - @keep::int;
+
+-- name: ListFileVersions :many
+SELECT version, filename, LENGTH(content)::int AS size, content_hash, mime_type, tags, created_at
+FROM file_versions
+WHERE file_id = @file_id
+ORDER BY version DESC;
+
+-- name: GetFileVersion :one
+SELECT * FROM file_versions
+WHERE file_id = @file_id AND version = @version;
+
+-- name: CreateAuditEntry :exec
+INSERT INTO audit_log (user_id, action, file_id, status, request_id)
+VALUES (@user_id, @action, @file_id, @status, @request_id);
+
+-- name: ListAuditEntries :many
+SELECT id, user_id, action, file_id, status, request_id, created_at
+FROM audit_log
+WHERE sqlc.narg(file_id)::uuid IS NULL OR file_id = sqlc.narg(file_id)::uuid
+ORDER BY id DESC
+LIMIT @row_limit;
@@ -5,9 +5,60 @@ CREATE TABLE files (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     filename TEXT NOT NULL,
     content TEXT NOT NULL,
-    embedding VECTOR(384) NOT NULL,
+    -- Declared as vector(EXPECTED_EMBEDDING_DIM) at startup by db.EnsureEmbeddingDim.
+    embedding VECTOR NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    deleted BOOLEAN DEFAULT FALSE
+    content_hash TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    mime_type TEXT NOT NULL DEFAULT 'text/plain',
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted BOOLEAN GENERATED ALWAYS AS (deleted_at IS NOT NULL) STORED,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    embedding_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    summary TEXT,
+    -- Searched exactly; summaries are few and short enough not to need an ANN index.
+    summary_embedding VECTOR
 );
 
-CREATE INDEX idx_files_embedding ON files USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100);
+-- idx_files_embedding is built at startup by db.EnsureVectorIndex once the column has a dimension.
+CREATE INDEX idx_files_content_hash ON files (content_hash);
+CREATE INDEX idx_files_filename ON files (filename);
+CREATE INDEX idx_files_mime_type ON files (mime_type);
+CREATE INDEX idx_files_tags ON files USING GIN (tags);
+
+CREATE TABLE file_versions (
+    file_id UUID NOT NULL REFERENCES files (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    filename TEXT NOT NULL,
+    content TEXT NOT NULL,
+    embedding VECTOR NOT NULL,
+    content_hash TEXT,
+    mime_type TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    embedding_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (file_id, version)
+);
+
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
+
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT,
+    action TEXT NOT NULL,
+    file_id UUID,
+    status INTEGER NOT NULL,
+    request_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_file_id ON audit_log (file_id);
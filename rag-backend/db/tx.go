@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrTxUnsupported is returned when the underlying DBTX cannot begin a transaction.
+var ErrTxUnsupported = errors.New("database handle does not support transactions")
+
+// txBeginner is satisfied by *pgxpool.Pool, *pgx.Conn, and pgx.Tx (as a savepoint).
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// ExecTx runs fn with Queries bound to a new transaction, committing when fn
+// returns nil and rolling back otherwise. It complements the generated WithTx,
+// which binds an existing transaction.
+func (q *Queries) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	beginner, ok := q.db.(txBeginner)
+	if !ok {
+		return ErrTxUnsupported
+	}
+
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
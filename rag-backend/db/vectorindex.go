@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fain17/rag-backend/vector"
+)
+
+// Supported pgvector index access methods.
+const (
+	IndexIVFFlat = "ivfflat"
+	IndexHNSW    = "hnsw"
+)
+
+const vectorIndexName = "idx_files_embedding"
+
+// VectorIndex selects the ANN index built on files.embedding. ivfflat builds
+// quickly but needs Lists tuned to the row count; hnsw gives better recall at
+// the cost of a slower, more memory-hungry build. An index only serves
+// searches with the distance operator of its Metric, which defaults to cosine.
+// With Quantization set to QuantizationHalfvec the index holds the embeddings
+// at half precision as halfvec(Dim), halving its size, while the table keeps
+// full precision.
+type VectorIndex struct {
+	Type           string
+	Metric         string
+	Quantization   string
+	Dim            int
+	Lists          int
+	M              int
+	EfConstruction int
+}
+
+// opClass returns the pgvector operator class indexing the metric's operator.
+func (v VectorIndex) opClass() (string, error) {
+	prefix := "vector"
+	if v.Quantization == QuantizationHalfvec {
+		prefix = "halfvec"
+	}
+	switch v.Metric {
+	case vector.MetricL2:
+		return prefix + "_l2_ops", nil
+	case vector.MetricInner:
+		return prefix + "_ip_ops", nil
+	case vector.MetricCosine, "":
+		return prefix + "_cosine_ops", nil
+	}
+	return "", fmt.Errorf("unsupported vector index metric %q", v.Metric)
+}
+
+// column returns the indexed element: the embedding itself, or its
+// half-precision cast when the index is quantized. Expressions must be
+// parenthesized in CREATE INDEX.
+func (v VectorIndex) column() string {
+	if v.Quantization == QuantizationHalfvec {
+		return "(" + halfvecCast("embedding", v.Dim) + ")"
+	}
+	return "embedding"
+}
+
+// Validate checks the index type and its parameters against pgvector's limits.
+func (v VectorIndex) Validate() error {
+	switch v.Type {
+	case IndexIVFFlat:
+		if v.Lists < 1 || v.Lists > 32768 {
+			return fmt.Errorf("ivfflat lists must be between 1 and 32768, got %d", v.Lists)
+		}
+	case IndexHNSW:
+		if v.M < 2 || v.M > 100 {
+			return fmt.Errorf("hnsw m must be between 2 and 100, got %d", v.M)
+		}
+		if v.EfConstruction < 4 || v.EfConstruction > 1000 {
+			return fmt.Errorf("hnsw ef_construction must be between 4 and 1000, got %d", v.EfConstruction)
+		}
+		if v.EfConstruction < 2*v.M {
+			return fmt.Errorf("hnsw ef_construction (%d) must be at least 2*m (%d)", v.EfConstruction, 2*v.M)
+		}
+	default:
+		return fmt.Errorf("unsupported vector index type %q (want %s or %s)", v.Type, IndexIVFFlat, IndexHNSW)
+	}
+	switch v.Quantization {
+	case QuantizationNone, "":
+	case QuantizationHalfvec:
+		if v.Dim < 1 || v.Dim > maxHalfvecIndexDim {
+			return fmt.Errorf("halfvec quantization needs EXPECTED_EMBEDDING_DIM between 1 and %d, got %d", maxHalfvecIndexDim, v.Dim)
+		}
+	default:
+		return fmt.Errorf("unsupported vector index quantization %q (want %s or %s)", v.Quantization, QuantizationNone, QuantizationHalfvec)
+	}
+	_, err := v.opClass()
+	return err
+}
+
+// CreateSQL returns the CREATE INDEX statement for the configured index type.
+func (v VectorIndex) CreateSQL() (string, error) {
+	if err := v.Validate(); err != nil {
+		return "", err
+	}
+	opClass, _ := v.opClass()
+	var with string
+	if v.Type == IndexIVFFlat {
+		with = fmt.Sprintf("lists = %d", v.Lists)
+	} else {
+		with = fmt.Sprintf("m = %d, ef_construction = %d", v.M, v.EfConstruction)
+	}
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON files USING %s (%s %s) WITH (%s)",
+		vectorIndexName, v.Type, v.column(), opClass, with), nil
+}
+
+// EnsureVectorIndex creates the embedding index if it is missing and rebuilds
+// it when it exists with a different access method or operator class.
+// Parameter changes on an otherwise matching index are left alone; drop the
+// index to apply them.
+func (q *Queries) EnsureVectorIndex(ctx context.Context, v VectorIndex) error {
+	create, err := v.CreateSQL()
+	if err != nil {
+		return err
+	}
+	opClass, _ := v.opClass()
+
+	var indexdef string
+	err = q.db.QueryRow(ctx, "SELECT indexdef FROM pg_indexes WHERE indexname = $1", vectorIndexName).Scan(&indexdef)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("read existing vector index: %w", err)
+	case strings.Contains(indexdef, "USING "+v.Type+" ") && strings.Contains(indexdef, " "+opClass+")"):
+		return nil
+	default:
+		log.Printf("Rebuilding %s as %s with %s", vectorIndexName, v.Type, opClass)
+		if _, err := q.db.Exec(ctx, "DROP INDEX IF EXISTS "+vectorIndexName); err != nil {
+			return fmt.Errorf("drop vector index: %w", err)
+		}
+	}
+
+	if _, err := q.db.Exec(ctx, create); err != nil {
+		return fmt.Errorf("create vector index: %w", err)
+	}
+	return nil
+}
@@ -15,54 +15,48 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/files/date-range": {
+        "/admin/embedding-dimensions": {
             "get": {
-                "description": "Retrieves files created within the specified date range. Both start and end dates are inclusive.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Counts files by vector_dims(embedding) so operators can find rows embedded with the wrong dimension. mixed is true when more than one dimension is present.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
                 ],
-                "summary": "Get files within a date range",
+                "summary": "Histogram of stored embedding dimensions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Start date in YYYY-MM-DD format (e.g., 2024-01-01)",
-                        "name": "start",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date in YYYY-MM-DD format (e.g., 2024-12-31)",
-                        "name": "end",
-                        "in": "query",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Files created within the date range",
+                        "description": "Dimension histogram",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.FileUploadRequest"
-                            }
+                            "$ref": "#/definitions/models.EmbeddingDimensionsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid date format",
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Admin endpoints disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve files by date",
+                        "description": "Failed to count dimensions",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -71,38 +65,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/getall": {
-            "get": {
-                "description": "Retrieves all files from the database. Returns a list of all files with their content and embeddings.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/repair/content-hashes": {
+            "post": {
+                "description": "Walks files whose content_hash is NULL in id order, computing and storing the SHA-256 of each file's content. Each batch commits in its own transaction and only fills hashes that are still NULL, so the run is idempotent and can be resumed after a failure by calling it again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
+                ],
+                "summary": "Backfill missing content hashes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows per transaction (1-5000, default 500)",
+                        "name": "batch_size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all files",
                 "responses": {
                     "200": {
-                        "description": "List of all files",
+                        "description": "Repair summary",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.FileUploadRequest"
-                            }
+                            "$ref": "#/definitions/models.RepairResponse"
                         }
                     },
-                    "404": {
-                        "description": "No files found",
+                    "400": {
+                        "description": "Invalid batch_size",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Admin endpoints disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Repair failed; rows already repaired stay repaired",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -111,31 +128,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/metadata": {
+        "/admin/schema": {
             "get": {
-                "description": "Retrieves lightweight metadata for all files including ID, filename, size, and creation date. Does not include file content or embeddings for performance.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the introspected columns and types of the files table, the declared embedding dimension (-1 when the column has none), and the indexes that exist, to diagnose slow search or failing uploads without database access.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
+                ],
+                "summary": "Describe the files table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Get lightweight file metadata",
                 "responses": {
                     "200": {
-                        "description": "List of file metadata",
+                        "description": "Table schema",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.FileMetadata"
-                            }
+                            "$ref": "#/definitions/models.SchemaResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Admin endpoints disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Failed to get metadata",
+                        "description": "Failed to read schema",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -144,31 +178,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/recycle-bin": {
+        "/admin/storage": {
             "get": {
-                "description": "Retrieves all files that have been soft-deleted (moved to recycle bin). These files can be restored or permanently deleted.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the raw embedding payload (sum of dimensions × 4 bytes), the content payload in bytes, and the table's total size including indexes and TOAST from pg_total_relation_size.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
+                ],
+                "summary": "Report storage used by files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all soft-deleted files",
                 "responses": {
                     "200": {
-                        "description": "List of soft-deleted files",
+                        "description": "Storage footprint",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.FileUploadRequest"
-                            }
+                            "$ref": "#/definitions/models.StorageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Admin endpoints disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch deleted files",
+                        "description": "Failed to read storage stats",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -177,47 +228,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/search": {
+        "/audit": {
             "get": {
-                "description": "Searches for files whose filename contains the specified query string. Case-sensitive search.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns recorded mutating operations on files, newest first, optionally for one file. Entries with a status of 400 or above are failed attempts.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "admin"
                 ],
-                "summary": "Search files by filename",
+                "summary": "List audit log entries",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search keyword to match in filename (e.g., 'document', 'report')",
-                        "name": "query",
-                        "in": "query",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries for this file UUID",
+                        "name": "file_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Files matching the search query",
+                        "description": "Audit entries",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.FileUploadRequest"
+                                "$ref": "#/definitions/models.AuditEntry"
                             }
                         }
                     },
                     "400": {
-                        "description": "Query parameter is required",
+                        "description": "Invalid file_id or limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Admin endpoints disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Search operation failed",
+                        "description": "Failed to list audit entries",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -226,9 +300,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/upload": {
+        "/config/embeddings": {
+            "get": {
+                "description": "Returns the embedding dimension, models, providers, and default similarity metric the server expects. An absent expected_dimension means any length is accepted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get embedding configuration",
+                "responses": {
+                    "200": {
+                        "description": "Embedding configuration",
+                        "schema": {
+                            "$ref": "#/definitions/models.EmbeddingConfigResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/config/metrics": {
+            "get": {
+                "description": "Returns each metric accepted by search endpoints with its pgvector operator, whether lower or higher distances are better, and how to read the values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "List supported similarity metrics",
+                "responses": {
+                    "200": {
+                        "description": "Supported metrics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MetricInfo"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/files/bulk-delete": {
             "post": {
-                "description": "Stores a new file with its content and embedding vector. The embedding should be a vector representation of the file content for similarity search.",
+                "description": "Permanently deletes up to 1000 files by ID in one query, or moves them to the recycle bin when soft is true (already soft-deleted files keep their original deleted_at). Every ID is validated before anything is deleted. The response lists the IDs deleted and those that matched no file.",
                 "consumes": [
                     "application/json"
                 ],
@@ -238,34 +355,34 @@ const docTemplate = `{
                 "tags": [
                     "files"
                 ],
-                "summary": "Upload a file",
+                "summary": "Delete many files at once",
                 "parameters": [
                     {
-                        "description": "File data including filename, content, and embedding vector",
-                        "name": "file",
+                        "description": "IDs to delete",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FileUploadRequest"
+                            "$ref": "#/definitions/models.BulkDeleteRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File uploaded successfully",
+                        "description": "Deleted and missing IDs",
                         "schema": {
-                            "$ref": "#/definitions/models.FileUploadRequest"
+                            "$ref": "#/definitions/models.BulkDeleteResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid body, ID, or batch size",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Failed to create file",
+                        "description": "Delete failed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -274,60 +391,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/{id}": {
+        "/files/by-tag": {
             "get": {
-                "description": "Retrieves a specific file by its UUID. Returns the complete file data including content and embedding vector.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns summaries of non-deleted files carrying the exact tag, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "files"
                 ],
-                "summary": "Get file by ID",
+                "summary": "List files with a tag",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "File UUID (e.g., 550e8400-e29b-41d4-a716-446655440000)",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Tag to match exactly",
+                        "name": "tag",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File data retrieved successfully",
+                        "description": "Files with the tag",
                         "schema": {
-                            "$ref": "#/definitions/models.FileUploadRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileSummary"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid UUID format",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "File not found",
+                        "description": "Missing tag",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Lookup failed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an existing file's content, filename, and embedding vector. All fields in the request body will replace the existing values.",
+            }
+        },
+        "/files/date-range": {
+            "get": {
+                "description": "Retrieves files created within the specified date range. Both start and end dates are inclusive.",
                 "consumes": [
                     "application/json"
                 ],
@@ -337,50 +449,53 @@ const docTemplate = `{
                 "tags": [
                     "files"
                 ],
-                "summary": "Update a file",
+                "summary": "Get files within a date range",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "File UUID to update",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Start date in YYYY-MM-DD format (e.g., 2024-01-01)",
+                        "name": "start",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Updated file data",
-                        "name": "file",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.FileUploadRequest"
-                        }
+                        "type": "string",
+                        "description": "End date in YYYY-MM-DD format (e.g., 2024-12-31)",
+                        "name": "end",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File updated successfully",
+                        "description": "Files created within the date range",
                         "schema": {
-                            "$ref": "#/definitions/models.FileUploadRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileUploadRequest"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid UUID or request body",
+                        "description": "Invalid date format",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Update operation failed",
+                        "description": "Failed to retrieve files by date",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Permanently removes a file from the database. This action cannot be undone.",
+            }
+        },
+        "/files/debug-parse": {
+            "post": {
+                "description": "Binds the body as a FileUploadRequest and returns how the server interpreted it, plus validation warnings. Nothing is stored. Only available when DEBUG_ENDPOINTS is enabled.",
                 "consumes": [
                     "application/json"
                 ],
@@ -388,31 +503,84 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "files"
+                    "debug"
                 ],
-                "summary": "Delete a file permanently",
+                "summary": "Echo a parsed upload request",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "File UUID to delete",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Upload body to inspect",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "File deleted successfully"
+                    "200": {
+                        "description": "Parsed request",
+                        "schema": {
+                            "$ref": "#/definitions/models.DebugParseResponse"
+                        }
                     },
                     "400": {
-                        "description": "Invalid UUID format",
+                        "description": "Body could not be parsed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/distance-matrix": {
+            "post": {
+                "description": "Fetches the embeddings of the given files once and returns the NxN distance matrix between them, using pgvector semantics for the metric (l2, cosine, or inner; defaults to DEFAULT_METRIC). At most 100 IDs are accepted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Compute pairwise embedding distances",
+                "parameters": [
+                    {
+                        "description": "File IDs and metric",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DistanceMatrixRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Distance matrix",
+                        "schema": {
+                            "$ref": "#/definitions/models.DistanceMatrixResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid IDs, metric, set size, or mismatched dimensions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "One or more files not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Delete operation failed",
+                        "description": "Failed to fetch embeddings",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -421,9 +589,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/{id}/restore": {
-            "patch": {
-                "description": "Restores a previously soft-deleted file by setting its deleted flag back to false. The file becomes available again.",
+        "/files/duplicates": {
+            "get": {
+                "description": "Groups non-deleted files whose content hashes match, largest groups first. Each group lists file IDs oldest first, so the first ID is the one uploads resolve to. Files without a content hash are not considered.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Report duplicate files",
+                "responses": {
+                    "200": {
+                        "description": "Groups of files with identical content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DuplicateGroup"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find duplicates",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/exists/batch": {
+            "post": {
+                "description": "Looks up many content hashes and/or filenames in one query against non-deleted files and reports, for each list, which identifiers exist (with the matching file IDs) and which are missing. Hashes are hex SHA-256 of the content, as stored on upload; rows written before hashes were stored only match by filename. At most 1000 identifiers in total.",
                 "consumes": [
                     "application/json"
                 ],
@@ -433,33 +631,34 @@ const docTemplate = `{
                 "tags": [
                     "files"
                 ],
-                "summary": "Restore a soft-deleted file",
+                "summary": "Check which files already exist",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "File UUID to restore",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Hashes and filenames to check",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ExistsBatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File restored successfully",
+                        "description": "Existing and missing identifiers",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ExistsBatchResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid UUID format",
+                        "description": "Invalid body, hash, or batch size",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Restore operation failed",
+                        "description": "Lookup failed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -468,9 +667,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/files/{id}/soft-delete": {
-            "patch": {
-                "description": "Marks a file as deleted without removing it from the database. The file can be restored later using the restore endpoint.",
+        "/files/getall": {
+            "get": {
+                "description": "Retrieves all files from the database, newest first unless sort and order say otherwise. By default each entry is a lightweight summary (ID, filename, size, creation date, deleted flag) without content or embeddings; pass include_content=true for the full records.",
                 "consumes": [
                     "application/json"
                 ],
@@ -480,33 +679,70 @@ const docTemplate = `{
                 "tags": [
                     "files"
                 ],
-                "summary": "Soft delete a file",
+                "summary": "Get all files",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Return full records including content and embeddings",
+                        "name": "include_content",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "File UUID to soft delete",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only files with this MIME type (e.g., application/pdf)",
+                        "name": "mime_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "filename",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort column",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File soft-deleted successfully",
+                        "description": "List of all files (full records when include_content=true)",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileSummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sort or order",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Invalid UUID format",
+                    "404": {
+                        "description": "No files found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Soft delete operation failed",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -514,46 +750,2498 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.FileMetadata": {
-            "description": "Lightweight file metadata for performance-optimized queries",
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "filename": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "size": {
-                    "type": "integer"
+        },
+        "/files/hybrid-search": {
+            "post": {
+                "description": "Runs a case-insensitive substring search over filenames and content (filename matches rank first) and a similarity search on the embedding, then merges them with weighted reciprocal rank fusion: score = (1 - vector_weight) / (60 + keyword_rank) + vector_weight / (60 + vector_rank). Each result reports both contributions and ranks so callers can tune vector_weight. With normalize_scores=true, results found by the vector search also carry relevance, their distance mapped to 0-1 as in advanced search. When embedding is omitted, query is embedded server-side if an embedder is configured. When DEBUG_ENDPOINTS is enabled, debug_timing=true wraps the results with per-stage timings and whether the planner uses the vector index. Soft-deleted files are excluded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Hybrid keyword and vector search",
+                "parameters": [
+                    {
+                        "description": "Keyword query, embedding, and weighting",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HybridSearchRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add a 0-1 relevance score to vector matches",
+                        "name": "normalize_scores",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results with stage timings (DEBUG_ENDPOINTS only)",
+                        "name": "debug_timing",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Highest fused score first, with timings, when debug_timing=true",
+                        "schema": {
+                            "$ref": "#/definitions/models.HybridSearchDebugResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid search parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Search failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Query could not be embedded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
                 }
             }
         },
-        "models.FileUploadRequest": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "deleted": {
-                    "type": "boolean"
-                },
-                "embedding": {
-                    "type": "array",
-                    "items": {
-                        "type": "number"
+        "/files/metadata": {
+            "get": {
+                "description": "Retrieves lightweight metadata for all files including ID, filename, size, and creation date, newest first unless sort and order say otherwise. Does not include file content or embeddings for performance. The response carries an ETag over the listing; send it back in If-None-Match to get 304 with no body while nothing has changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get lightweight file metadata",
+                "parameters": [
+                    {
+                        "enum": [
+                            "created_at",
+                            "filename",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort column",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
-                },
-                "filename": {
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of file metadata",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileMetadata"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Listing unchanged since the given ETag"
+                    },
+                    "400": {
+                        "description": "Invalid sort or order",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/oldest": {
+            "get": {
+                "description": "Returns metadata for the N oldest files by created_at (ascending) with their age, for retention review. Soft-deleted files are excluded unless include_deleted=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "List the oldest files",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of files (1-500, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted files",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Oldest files first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileAge"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list files",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/recycle-bin": {
+            "get": {
+                "description": "Retrieves all files that have been soft-deleted (moved to recycle bin), most recently deleted first. Each file includes DeletedAt, when it was moved to the recycle bin. These files can be restored or permanently deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get all soft-deleted files",
+                "responses": {
+                    "200": {
+                        "description": "List of soft-deleted files",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileUploadRequest"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch deleted files",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently removes every soft-deleted file and returns how many were purged. Live files are not affected. With dry_run=true nothing is deleted; the response reports how many files would be purged and a sample of them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Empty the recycle bin",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Preview the purge without deleting",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of files purged",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurgeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Purge failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/search": {
+            "get": {
+                "description": "Searches for files whose filename contains the specified query string. Matching is case-insensitive unless case_sensitive=true. % and _ in the query match literally. With dedup=true, files with identical content are collapsed to the first match.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Search files by filename",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search keyword to match in filename (e.g., 'document', 'report')",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match the filename case exactly",
+                        "name": "case_sensitive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Collapse results with identical content",
+                        "name": "dedup",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only files with this MIME type (e.g., application/pdf)",
+                        "name": "mime_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Files matching the search query",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileUploadRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Query parameter is required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Search operation failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/search/advanced": {
+            "post": {
+                "description": "Ranks files by distance between their embedding and the query embedding under the chosen metric, restricted in the same query by an optional filename substring and created_at range. Equal distances are ordered by created_at, then id, so results are stable across calls. Soft-deleted files are excluded unless include_deleted is set. With mode=summary, summary embeddings are searched instead of content embeddings, and only files uploaded with a summary can match. With dedup=true, files sharing a content hash are collapsed to the closest one. With normalize_scores=true, each result also carries relevance, the distance mapped to 0-1 where higher is closer: 1/(1+d) for l2, (1+cosine similarity)/2 for cosine, and 1/(1+e^d) (the logistic of the dot product) for inner. When embedding is omitted, text is embedded server-side if an embedder is configured (OPENAI_API_KEY or EMBEDDING_PROVIDER=ollama). When DEBUG_ENDPOINTS is enabled, debug_timing=true wraps the results with per-stage timings and whether the planner uses the vector index. The metadata and rerank fields are reserved and rejected with 400 until those features exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Advanced similarity search",
+                "parameters": [
+                    {
+                        "description": "Query embedding and filters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AdvancedSearchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Embedding to search: content (default) or summary",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Collapse results with identical content",
+                        "name": "dedup",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only files with this MIME type (e.g., application/pdf)",
+                        "name": "mime_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add a 0-1 relevance score to each result",
+                        "name": "normalize_scores",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the results with stage timings (DEBUG_ENDPOINTS only)",
+                        "name": "debug_timing",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Closest files first, with timings, when debug_timing=true",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchDebugResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or unsupported search parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Search failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Query text could not be embedded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/sync": {
+            "post": {
+                "description": "Converges stored files onto the submitted batch. Each item is matched by exact filename against the latest non-deleted file: unknown filenames are created, changed content is updated, and content with an identical SHA-256 hash is left untouched, tags included. Updated files keep their prior state as a version. Re-running the same batch is safe and reports every item as unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Idempotently sync a batch of files",
+                "parameters": [
+                    {
+                        "description": "Files to sync (max 100)",
+                        "name": "files",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FileSyncRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Action taken per item",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileSyncResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or batch size",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/tags": {
+            "get": {
+                "description": "Returns every distinct tag on non-deleted files with how many files carry it, most used first, for building tag filters.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "List tags with counts",
+                "responses": {
+                    "200": {
+                        "description": "Tags and file counts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TagCount"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list tags",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/upload": {
+            "post": {
+                "description": "Stores a new file with its content, embedding vector, and optional tags. The embedding should be a vector representation of the file content for similarity search. A new file is answered with 201 and a Location header naming it. If a non-deleted file already has the same content (by SHA-256), that file is returned with 200 instead of storing a duplicate; pass force=true to store it anyway. With unique_filename=true, an upload whose filename matches a non-deleted file is rejected with 409 and the existing file's id. If the embedding is omitted and a server-side embedder is configured (OPENAI_API_KEY), the content is embedded before storing. An optional summary is stored with its own summary_embedding for ?mode=summary search; the embedder fills in a missing summary_embedding the same way. When EXPECTED_EMBEDDING_DIM is set, embeddings of any other length are rejected with 400.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Upload a file",
+                "parameters": [
+                    {
+                        "description": "File data including filename, content, and embedding vector",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Store the file even if identical content already exists",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject with 409 if a non-deleted file already has this filename",
+                        "name": "unique_filename",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Existing file with identical content",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        }
+                    },
+                    "201": {
+                        "description": "File created; Location holds its URL",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/files/{id} of the created file"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or embedding dimension mismatch",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "unique_filename=true and the filename is taken; body has error and the existing id",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Embedding provider failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/upload-multipart": {
+            "post": {
+                "description": "Stores an uploaded text file directly, without pre-serializing it into JSON. The raw file text becomes the content, the upload's base name becomes the filename, and the MIME type is detected from the bytes. Because every stored file needs a vector, the embedding form field is required and holds a JSON array of floats. Requests larger than MAX_UPLOAD_BYTES are rejected with 413.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Upload a file as multipart form data",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "UTF-8 text file to store",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Embedding as a JSON array of floats (e.g., [0.1, 0.2])",
+                        "name": "embedding",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags; repeat the field for each tag",
+                        "name": "tags",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "File created; Location holds its URL",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/files/{id} of the created file"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing file, non-text content, or invalid embedding",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Upload exceeds MAX_UPLOAD_BYTES",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}": {
+            "get": {
+                "description": "Retrieves a specific file by its UUID. Returns the complete file data including content and embedding vector. The response carries an ETag that changes whenever the file does; send it back in If-None-Match to get 304 with no body while the file is unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get file by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID (e.g., 550e8400-e29b-41d4-a716-446655440000)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File data retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        }
+                    },
+                    "304": {
+                        "description": "File unchanged since the given ETag"
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates an existing file's content, filename, and embedding vector. All fields in the request body will replace the existing values, except tags, which are kept when omitted. The summary is replaced too, so an update without one clears the summary of the old content. The state being replaced is saved as a version first (see /files/{id}/versions). When EXPECTED_EMBEDDING_DIM is set, embeddings of any other length are rejected with 400.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Update a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID to update",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated file data",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID, request body, or embedding dimension",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Update operation failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently removes a file from the database. This action cannot be undone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Delete a file permanently",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "File deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Delete operation failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/clone": {
+            "post": {
+                "description": "Copies a file's content, embedding, content hash, MIME type, and tags into a new file. The stored embedding is reused rather than recomputed. Without a filename in the body, the copy is named \"Copy of \u003csource filename\u003e\". Soft-deleted files cannot be cloned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Clone a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source file UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional filename for the copy",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CloneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The new file",
+                        "schema": {
+                            "$ref": "#/definitions/models.FileUploadRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID format or request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Source file not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to clone file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/content": {
+            "get": {
+                "description": "Returns the file's content as plain text. Honors the Range header: a satisfiable byte range returns 206 with Content-Range, an unsatisfiable one returns 416.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get raw file content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range (e.g., bytes=0-1023)",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Full content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "206": {
+                        "description": "Requested byte range",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "416": {
+                        "description": "Range not satisfiable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/embedding": {
+            "patch": {
+                "description": "Overwrites only the stored vector, leaving filename and content untouched, so re-embedding jobs need not resend documents. The new embedding must have the same dimension as the one it replaces. The prior state is kept as a version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Replace a file's embedding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New embedding",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmbeddingUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Embedding replaced",
+                        "schema": {
+                            "$ref": "#/definitions/models.EmbeddingUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID, missing embedding, or dimension mismatch",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update embedding",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/embedding/stats": {
+            "get": {
+                "description": "Computes the L2 norm, min, max, mean, and zero count of the file's stored embedding without returning the vector itself. Useful for spotting degenerate or unnormalized embeddings.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get embedding statistics for a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Embedding statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.EmbeddingStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get embedding",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/purge": {
+            "delete": {
+                "description": "Permanently removes a soft-deleted file. Files that are not in the recycle bin are left untouched and return 404; use DELETE /files/{id} to remove a live file.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Permanently delete a file from the recycle bin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID to purge",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "File purged"
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not in recycle bin",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Purge failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/restore": {
+            "patch": {
+                "description": "Restores a previously soft-deleted file by clearing deleted_at, which also clears the deleted flag. The file becomes available again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Restore a soft-deleted file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID to restore",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File restored successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Restore operation failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/soft-delete": {
+            "patch": {
+                "description": "Marks a file as deleted without removing it from the database by recording deleted_at. Deleting an already deleted file keeps the original deleted_at. The file can be restored later using the restore endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Soft delete a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID to soft delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File soft-deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Soft delete operation failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/touch": {
+            "post": {
+                "description": "Bumps updated_at to now without changing content or embedding. With reviewed=true, reviewed_at is set to now as well.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Touch a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also mark the file as reviewed",
+                        "name": "reviewed",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New timestamps",
+                        "schema": {
+                            "$ref": "#/definitions/models.TouchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to touch file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/versions": {
+            "get": {
+                "description": "Returns the snapshots taken before each update or restore, newest first, without content or embeddings. Only the newest FILE_VERSIONS_MAX are kept. A file that was never updated, or does not exist, has no versions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "List a file's prior versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prior versions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FileVersionSummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list versions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/versions/{version}/restore": {
+            "post": {
+                "description": "Rolls the file's filename, content, embedding, and tags back to the given version. The state being replaced is itself saved as a new version, so a restore can be undone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Restore a prior version of a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number from the versions list",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID or version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to restore version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{id}/with-neighbors": {
+            "get": {
+                "description": "Returns the file plus the top_k most similar non-deleted files by cosine distance between embeddings, in one round-trip. Equal distances are ordered by created_at, then id, so results are stable.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Get a file with its nearest neighbors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File UUID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of neighbors (1-50, default 5)",
+                        "name": "top_k",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File and its neighbors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.FileWithNeighborsResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "file": {
+                                            "$ref": "#/definitions/models.FileUploadRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid UUID or top_k",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Neighbor search failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Returns 200 as long as the process is serving requests. It does not touch the database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Process is alive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/keys": {
+            "post": {
+                "description": "Creates an API key owned by the authenticated user for use in the X-API-Key header. The key is returned only in this response; the server stores just its SHA-256 hash.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keys"
+                ],
+                "summary": "Mint an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The new key",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKeyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/keys/{id}": {
+            "delete": {
+                "description": "Revokes one of the authenticated user's API keys; it stops authenticating immediately. Keys owned by other users, or already revoked, return 404.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Key revoked"
+                    },
+                    "400": {
+                        "description": "Invalid UUID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Key not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Pings the database with a short timeout. Returns 503 when it is unreachable so orchestrators stop routing traffic here.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Ready to serve",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Database unreachable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIKeyResponse": {
+            "description": "The plaintext key is shown only here; store it securely",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "rag_3f9c..."
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdvancedSearchRequest": {
+            "description": "Similarity search over stored embeddings with optional filters. text is embedded server-side when embedding is omitted and an embedder is configured. metadata and rerank are reserved and currently rejected.",
+            "type": "object",
+            "properties": {
+                "created_after": {
+                    "description": "CreatedAfter and CreatedBefore bound created_at inclusively (RFC 3339).",
+                    "type": "string"
+                },
+                "created_before": {
+                    "type": "string"
+                },
+                "embedding": {
+                    "description": "Embedding is the query vector; required unless text is embedded server-side.",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "filename_contains": {
+                    "description": "FilenameContains keeps files whose name contains this case-insensitive substring.",
+                    "type": "string"
+                },
+                "include_deleted": {
+                    "description": "IncludeDeleted also searches soft-deleted files.",
+                    "type": "boolean"
+                },
+                "metadata": {
+                    "description": "Metadata would filter on file metadata; not supported yet.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "metric": {
+                    "description": "Metric is l2, cosine, or inner; defaults to DEFAULT_METRIC.",
+                    "type": "string"
+                },
+                "rerank": {
+                    "description": "Rerank would rerank candidates; not supported yet.",
+                    "type": "boolean"
+                },
+                "text": {
+                    "description": "Text is embedded server-side when embedding is omitted; needs a configured embedder.",
+                    "type": "string"
+                },
+                "top_k": {
+                    "description": "TopK is the number of results (1-50, default 5).",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "description": "Who called which route on which file, and whether it succeeded",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is the HTTP method and route, e.g. \"PUT /files/:id\".",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "success": {
+                    "description": "Success is false for any status of 400 or above.",
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "description": "UserID is empty when the route was called without authentication.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkDeleteRequest": {
+            "description": "File UUIDs to delete; soft moves them to the recycle bin instead",
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "soft": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.BulkDeleteResponse": {
+            "description": "IDs deleted and IDs that matched no file",
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "soft": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CloneRequest": {
+            "description": "Filename for the copy; defaults to \"Copy of \u003csource filename\u003e\"",
+            "type": "object",
+            "properties": {
+                "filename": {
+                    "type": "string",
+                    "example": "report-v2.txt"
+                }
+            }
+        },
+        "models.ColumnSchema": {
+            "description": "Column name, Postgres type, and nullability",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "nullable": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DebugParseResponse": {
+            "description": "Parsed view of a FileUploadRequest with validation warnings",
+            "type": "object",
+            "properties": {
+                "content_length": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "embedding_length": {
+                    "type": "integer"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.DistanceMatrixRequest": {
+            "description": "File IDs (max 100) and the metric to compare their embeddings with",
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "metric": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DistanceMatrixResponse": {
+            "description": "Matrix[i][j] is the distance between IDs[i] and IDs[j]",
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matrix": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "metric": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DuplicateGroup": {
+            "description": "Content hash shared by more than one non-deleted file, with their IDs oldest first",
+            "type": "object",
+            "properties": {
+                "content_hash": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.EmbeddingConfigResponse": {
+            "description": "Embedding settings clients should match when computing vectors",
+            "type": "object",
+            "properties": {
+                "default_metric": {
+                    "type": "string"
+                },
+                "expected_dimension": {
+                    "type": "integer"
+                },
+                "models": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.EmbeddingDimensionCount": {
+            "description": "Number of files whose embedding has the given dimension",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "dimension": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EmbeddingDimensionsResponse": {
+            "description": "Embedding dimension histogram for spotting wrong-dimension rows",
+            "type": "object",
+            "properties": {
+                "dimensions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EmbeddingDimensionCount"
+                    }
+                },
+                "expected_dimension": {
+                    "type": "integer"
+                },
+                "mixed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.EmbeddingStats": {
+            "description": "Per-vector statistics for diagnosing embedding quality",
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "norm": {
+                    "type": "number"
+                },
+                "zeros": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EmbeddingUpdateRequest": {
+            "description": "New vector for an existing file; filename and content are left untouched",
+            "type": "object",
+            "properties": {
+                "embedding": {
+                    "description": "Embedding must have the same dimension as the stored vector; required.",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "embedding_truncated": {
+                    "description": "EmbeddingTruncated records that the new embedding was computed from trimmed content.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.EmbeddingUpdateResponse": {
+            "description": "Updated file ID, vector dimension, and new updated_at",
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExistsBatchRequest": {
+            "description": "Content hashes (hex SHA-256) and/or filenames to look up",
+            "type": "object",
+            "properties": {
+                "filenames": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "hashes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ExistsBatchResponse": {
+            "description": "Which hashes and filenames already exist among non-deleted files",
+            "type": "object",
+            "properties": {
+                "filenames": {
+                    "$ref": "#/definitions/models.ExistsResult"
+                },
+                "hashes": {
+                    "$ref": "#/definitions/models.ExistsResult"
+                }
+            }
+        },
+        "models.ExistsResult": {
+            "description": "existing maps each found identifier to the IDs of matching files",
+            "type": "object",
+            "properties": {
+                "existing": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.FileAge": {
+            "description": "Lightweight file metadata plus age in seconds, for retention review",
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FileMetadata": {
+            "description": "Lightweight file metadata for performance-optimized queries",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FileSummary": {
+            "description": "File listing entry without content or embedding",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string",
+                    "example": "text/plain"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.FileSyncRequest": {
+            "description": "Files to create, update, or leave unchanged, matched by filename",
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FileUploadRequest"
+                    }
+                }
+            }
+        },
+        "models.FileSyncResult": {
+            "description": "Per-item sync outcome: created, updated, unchanged, or failed",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FileUploadRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "description": "Deleted is derived from DeletedAt and kept for compatibility.",
+                    "type": "boolean"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "embedding": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "embedding_truncated": {
+                    "description": "EmbeddingTruncated records that the embedding was computed from trimmed content.",
+                    "type": "boolean"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "summary": {
+                    "description": "Summary is an optional short description of the content, searched with ?mode=summary.",
+                    "type": "string"
+                },
+                "summary_embedding": {
+                    "description": "SummaryEmbedding is the embedding of Summary, required with it unless the server embeds it.",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "tags": {
+                    "description": "Tags label the file for filtering; on update, omitting tags keeps the current ones.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "finance",
+                        "2026"
+                    ]
+                }
+            }
+        },
+        "models.FileVersionSummary": {
+            "description": "Snapshot taken before an update or restore, without content or embedding",
+            "type": "object",
+            "properties": {
+                "content_hash": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FileWithNeighborsResponse": {
+            "description": "A file and the files most similar to it",
+            "type": "object",
+            "properties": {
+                "file": {},
+                "neighbors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Neighbor"
+                    }
+                }
+            }
+        },
+        "models.HybridSearchDebugResponse": {
+            "description": "Returned instead of the plain result array when ?debug_timing=true and DEBUG_ENDPOINTS is enabled",
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HybridSearchResult"
+                    }
+                },
+                "timing": {
+                    "$ref": "#/definitions/models.SearchTiming"
+                }
+            }
+        },
+        "models.HybridSearchRequest": {
+            "description": "Keyword (filename/content substring) and vector search fused by reciprocal rank",
+            "type": "object",
+            "properties": {
+                "embedding": {
+                    "description": "Embedding is the query vector; when omitted, query is embedded server-side if an embedder is configured.",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "metric": {
+                    "description": "Metric is l2, cosine, or inner; defaults to DEFAULT_METRIC.",
+                    "type": "string"
+                },
+                "query": {
+                    "description": "Query is matched case-insensitively against filenames and content; required.",
+                    "type": "string",
+                    "example": "invoice"
+                },
+                "top_k": {
+                    "description": "TopK is the number of results (1-50, default 5).",
+                    "type": "integer"
+                },
+                "vector_weight": {
+                    "description": "VectorWeight is the share of the fused score taken from the vector ranking (0-1, default 0.5).",
+                    "type": "number",
+                    "example": 0.5
+                }
+            }
+        },
+        "models.HybridSearchResult": {
+            "description": "score = keyword_score + vector_score; ranks are 1-based and omitted when that search did not return the file",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "keyword_rank": {
+                    "type": "integer"
+                },
+                "keyword_score": {
+                    "type": "number"
+                },
+                "relevance": {
+                    "description": "Relevance is Distance mapped to 0-1 (higher is closer); only set with ?normalize_scores=true.",
+                    "type": "number"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "vector_rank": {
+                    "type": "integer"
+                },
+                "vector_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.IndexSchema": {
+            "description": "Index name and its CREATE INDEX definition",
+            "type": "object",
+            "properties": {
+                "definition": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MetricInfo": {
+            "description": "Metric name, pgvector operator, and how its distances rank",
+            "type": "object",
+            "properties": {
+                "better": {
+                    "type": "string",
+                    "enum": [
+                        "lower",
+                        "higher"
+                    ],
+                    "example": "lower"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "cosine"
+                },
+                "operator": {
+                    "type": "string",
+                    "example": "\u003c=\u003e"
+                }
+            }
+        },
+        "models.Neighbor": {
+            "description": "Nearby file with its cosine distance to the anchor (lower is closer)",
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "type": "number"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PurgeResponse": {
+            "description": "Number of soft-deleted files purged from the recycle bin. On a dry run, purged is how many would be removed and sample lists some of them.",
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "purged": {
+                    "type": "integer"
+                },
+                "sample": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FileMetadata"
+                    }
+                }
+            }
+        },
+        "models.RepairResponse": {
+            "description": "Number of rows repaired, batches committed, and rows still missing a hash",
+            "type": "object",
+            "properties": {
+                "batches": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "repaired": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SchemaResponse": {
+            "description": "Columns, embedding dimension, and indexes of the files table",
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ColumnSchema"
+                    }
+                },
+                "embedding_dimension": {
+                    "type": "integer"
+                },
+                "indexes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IndexSchema"
+                    }
+                },
+                "table": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SearchDebugResponse": {
+            "description": "Returned instead of the plain result array when ?debug_timing=true and DEBUG_ENDPOINTS is enabled",
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SearchResult"
+                    }
+                },
+                "timing": {
+                    "$ref": "#/definitions/models.SearchTiming"
+                }
+            }
+        },
+        "models.SearchResult": {
+            "description": "Matching file with its distance to the query embedding under the requested metric (lower is closer)",
+            "type": "object",
+            "properties": {
+                "content_hash": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "relevance": {
+                    "description": "Relevance is the distance mapped to 0-1 (higher is closer); only set with ?normalize_scores=true.",
+                    "type": "number"
+                }
+            }
+        },
+        "models.SearchTiming": {
+            "description": "Per-stage durations in milliseconds and whether the vector index served the search; only returned with ?debug_timing=true when DEBUG_ENDPOINTS is enabled",
+            "type": "object",
+            "properties": {
+                "embedding_ms": {
+                    "description": "EmbeddingMs is the time spent embedding query text server-side; 0 when the request carried an embedding.",
+                    "type": "number"
+                },
+                "index_used": {
+                    "description": "IndexUsed reports whether the planner serves the similarity query from the vector index, per EXPLAIN.",
+                    "type": "boolean"
+                },
+                "rerank_ms": {
+                    "description": "RerankMs is the time spent reordering the retrieved rows: dedup for advanced search, rank fusion for hybrid search.",
+                    "type": "number"
+                },
+                "retrieval_ms": {
+                    "description": "RetrievalMs is the time spent in the search queries.",
+                    "type": "number"
+                }
+            }
+        },
+        "models.StorageResponse": {
+            "description": "Embedding and content payload sizes plus the table's total on-disk size",
+            "type": "object",
+            "properties": {
+                "content_bytes": {
+                    "type": "integer"
+                },
+                "embedding_bytes": {
+                    "type": "integer"
+                },
+                "files": {
+                    "type": "integer"
+                },
+                "total_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TagCount": {
+            "description": "Distinct tag across non-deleted files and its file count",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TouchResponse": {
+            "description": "New updated_at, and reviewed_at when set",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
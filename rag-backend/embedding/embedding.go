@@ -0,0 +1,27 @@
+// Package embedding computes embeddings server-side so callers can upload
+// plain text without running a model themselves.
+package embedding
+
+import (
+	"context"
+
+	"github.com/fain17/rag-backend/config"
+)
+
+// Embedder turns text into an embedding vector.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// FromConfig returns the embedder described by cfg, or nil when no provider
+// is configured and callers must send their own embeddings. EMBEDDING_PROVIDER
+// set to "ollama" selects Ollama; otherwise OpenAI is used when OPENAI_API_KEY is set.
+func FromConfig(cfg config.EmbeddingConfig) Embedder {
+	if cfg.Provider == ProviderOllama {
+		return NewOllama(cfg.OllamaURL, cfg.Model)
+	}
+	if cfg.OpenAIAPIKey == "" {
+		return nil
+	}
+	return NewOpenAI(cfg.OpenAIAPIKey, cfg.Model, cfg.ExpectedDim)
+}
@@ -0,0 +1,91 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ProviderOllama selects OllamaEmbedder via EMBEDDING_PROVIDER.
+const ProviderOllama = "ollama"
+
+// DefaultOllamaURL is the embeddings endpoint of a local Ollama server.
+const DefaultOllamaURL = "http://localhost:11434/api/embeddings"
+
+// DefaultOllamaModel produces 384-dimensional vectors, matching the files schema.
+const DefaultOllamaModel = "all-minilm"
+
+// ollamaTimeout bounds each request so a hung Ollama instance cannot block uploads.
+const ollamaTimeout = 30 * time.Second
+
+// OllamaEmbedder embeds text with a self-hosted Ollama server, for deployments
+// that cannot reach hosted APIs.
+type OllamaEmbedder struct {
+	URL    string
+	Model  string
+	Client *http.Client
+}
+
+// NewOllama returns an Ollama embedder, applying the defaults for an empty url or model.
+func NewOllama(url, model string) *OllamaEmbedder {
+	if url == "" {
+		url = DefaultOllamaURL
+	}
+	if model == "" {
+		model = DefaultOllamaModel
+	}
+	return &OllamaEmbedder{
+		URL:    url,
+		Model:  model,
+		Client: &http.Client{Timeout: ollamaTimeout},
+	}
+}
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type ollamaResponse struct {
+	Embedding []float32 `json:"embedding"`
+	Error     string    `json:"error"`
+}
+
+// Embed implements Embedder.
+func (o *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(ollamaRequest{Model: o.Model, Prompt: text})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama embeddings request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("ollama embeddings response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if out.Error != "" {
+			return nil, fmt.Errorf("ollama embeddings: %s (status %d)", out.Error, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("ollama embeddings: status %d", resp.StatusCode)
+	}
+	if len(out.Embedding) == 0 {
+		return nil, errors.New("ollama embeddings: empty response")
+	}
+	return out.Embedding, nil
+}
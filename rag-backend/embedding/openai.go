@@ -0,0 +1,92 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultOpenAIModel is used when EMBEDDING_MODEL is unset.
+const DefaultOpenAIModel = "text-embedding-3-small"
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAI embeds text with the OpenAI embeddings API.
+type OpenAI struct {
+	APIKey string
+	Model  string
+	// Dimensions asks the model to shorten its output; 0 keeps the model's native size.
+	Dimensions int
+	// BaseURL overrides the API root, e.g. for a compatible proxy.
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewOpenAI returns an OpenAI embedder, falling back to DefaultOpenAIModel when model is empty.
+func NewOpenAI(apiKey, model string, dimensions int) *OpenAI {
+	if model == "" {
+		model = DefaultOpenAIModel
+	}
+	return &OpenAI{
+		APIKey:     apiKey,
+		Model:      model,
+		Dimensions: dimensions,
+		BaseURL:    defaultOpenAIBaseURL,
+		Client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type openAIRequest struct {
+	Input      string `json:"input"`
+	Model      string `json:"model"`
+	Dimensions int    `json:"dimensions,omitempty"`
+}
+
+type openAIResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Embed implements Embedder.
+func (o *OpenAI) Embed(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(openAIRequest{Input: text, Model: o.Model, Dimensions: o.Dimensions})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai embeddings request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("openai embeddings response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if out.Error != nil {
+			return nil, fmt.Errorf("openai embeddings: %s (status %d)", out.Error.Message, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("openai embeddings: status %d", resp.StatusCode)
+	}
+	if len(out.Data) == 0 || len(out.Data[0].Embedding) == 0 {
+		return nil, errors.New("openai embeddings: empty response")
+	}
+	return out.Data[0].Embedding, nil
+}
@@ -10,23 +10,92 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 
 	api "github.com/fain17/rag-backend/api/routes"
+	"github.com/fain17/rag-backend/config"
 	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/embedding"
 )
 
+// shutdownTimeout bounds how long in-flight requests may run after SIGINT/SIGTERM.
+const shutdownTimeout = 15 * time.Second
+
 func main() {
+	selfTest := flag.Bool("self-test", false, "verify the database end to end and exit without serving")
+	flag.Parse()
 
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system env variables")
 	}
 
-	queries := db.ConnectDB()
-	r := api.NewRouter(queries)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Invalid configuration:", err)
+	}
+
+	queries, err := db.ConnectDB(cfg.Pool, cfg.DBConnect)
+	if err != nil {
+		log.Fatalf("Unable to connect to database: %v", err)
+	}
+	defer queries.Close()
+
+	if err := queries.VerifyEmbeddingDim(context.Background(), cfg.Embedding.ExpectedDim); err != nil {
+		log.Fatalf("Embedding dimension check failed: %v", err)
+	}
 
-	r.Run(":8080")
+	// The self-test is a diagnostic and exits before anything changes the schema.
+	if envSelfTest, _ := strconv.ParseBool(os.Getenv("SELF_TEST")); *selfTest || envSelfTest {
+		if err := queries.SelfTest(context.Background()); err != nil {
+			log.Fatalf("Self-test failed: %v", err)
+		}
+		log.Println("Self-test passed")
+		return
+	}
 
+	if err := queries.EnsureEmbeddingDim(context.Background(), cfg.Embedding.ExpectedDim); err != nil {
+		log.Fatalf("Embedding dimension setup failed: %v", err)
+	}
+	if err := queries.EnsureVectorIndex(context.Background(), cfg.VectorIndex); err != nil {
+		log.Fatalf("Vector index setup failed: %v", err)
+	}
+
+	// Background work stops when shutdown begins, before the pool is closed.
+	workers, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	r := api.NewRouter(workers, queries, cfg, embedding.FromConfig(cfg.Embedding))
+	go db.StartPurgeWorker(workers, queries, cfg.RecycleBinTTL, db.PurgeInterval)
+
+	server := &http.Server{Addr: cfg.Addr, Handler: r}
+	log.Printf("Listening on %s", cfg.Addr)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down, draining in-flight requests")
+	stopWorkers()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Forced shutdown: %v", err)
+	}
+	// The deferred queries.Close releases the pool once requests have drained.
 }
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/api/routes"
+	"github.com/fain17/rag-backend/config"
+)
+
+// newDimensionStore answers GetEmbeddingDimensionCounts by grouping the given dimensions like Postgres would
+func newDimensionStore(dims []int) *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetEmbeddingDimensionCounts", func(args ...any) ([][]any, error) {
+		counts := map[int]int64{}
+		var order []int
+		for _, d := range dims {
+			if counts[d] == 0 {
+				order = append(order, d)
+			}
+			counts[d]++
+		}
+		rows := make([][]any, len(order))
+		for i, d := range order {
+			rows[i] = []any{int32(d), counts[d]}
+		}
+		return rows, nil
+	})
+	return fake
+}
+
+func getAdmin(fake *fakeDB, cfg config.Config, path, token string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := routes.NewRouter(context.Background(), fake.queries(), cfg, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	if token != "" {
+		req.Header.Set("X-Admin-Token", token)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestEmbeddingDimensionsHistogram verifies the counts for a deliberately mixed set of embeddings
+func TestEmbeddingDimensionsHistogram(t *testing.T) {
+	fake := newDimensionStore([]int{384, 384, 384, 1536, 384, 768})
+	cfg := config.Config{AdminToken: "secret", Embedding: config.EmbeddingConfig{ExpectedDim: 384}}
+
+	w := getAdmin(fake, cfg, "/admin/embedding-dimensions", "secret")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.EmbeddingDimensionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 384, resp.ExpectedDimension)
+	assert.True(t, resp.Mixed)
+	assert.Equal(t, []models.EmbeddingDimensionCount{
+		{Dimension: 384, Count: 4},
+		{Dimension: 1536, Count: 1},
+		{Dimension: 768, Count: 1},
+	}, resp.Dimensions)
+}
+
+// TestEmbeddingDimensionsUniform verifies a single dimension is not reported as mixed
+func TestEmbeddingDimensionsUniform(t *testing.T) {
+	fake := newDimensionStore([]int{3, 3})
+
+	w := getAdmin(fake, config.Config{AdminToken: "secret"}, "/admin/embedding-dimensions", "secret")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.EmbeddingDimensionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Mixed)
+	assert.Equal(t, []models.EmbeddingDimensionCount{{Dimension: 3, Count: 2}}, resp.Dimensions)
+}
+
+// TestAdminAuth verifies admin routes reject missing or wrong tokens and are disabled without ADMIN_TOKEN
+func TestAdminAuth(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		configured string
+		presented  string
+		expected   int
+	}{
+		{"Disabled", "", "anything", http.StatusForbidden},
+		{"MissingToken", "secret", "", http.StatusUnauthorized},
+		{"WrongToken", "secret", "guess", http.StatusUnauthorized},
+		{"ValidToken", "secret", "secret", http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newDimensionStore(nil)
+			w := getAdmin(fake, config.Config{AdminToken: tc.configured}, "/admin/embedding-dimensions", tc.presented)
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected != http.StatusOK {
+				assert.Zero(t, fake.called("GetEmbeddingDimensionCounts"))
+			}
+		})
+	}
+}
+
+// TestSchemaHandler verifies the vector column and its dimension appear in the introspected schema
+func TestSchemaHandler(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull", func(args ...any) ([][]any, error) {
+		return [][]any{
+			{"id", "uuid", false},
+			{"filename", "text", false},
+			{"embedding", "vector(384)", false},
+			{"content_hash", "text", true},
+		}, nil
+	})
+	fake.on("SELECT atttypmod FROM pg_attribute", func(args ...any) ([][]any, error) {
+		return [][]any{{int32(384)}}, nil
+	})
+	fake.on("SELECT indexname, indexdef FROM pg_indexes", func(args ...any) ([][]any, error) {
+		return [][]any{
+			{"files_pkey", "CREATE UNIQUE INDEX files_pkey ON public.files USING btree (id)"},
+			{"idx_files_embedding", "CREATE INDEX idx_files_embedding ON public.files USING hnsw (embedding vector_cosine_ops)"},
+		}, nil
+	})
+
+	w := getAdmin(fake, config.Config{AdminToken: "secret"}, "/admin/schema", "secret")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.SchemaResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "files", resp.Table)
+	assert.Equal(t, 384, resp.EmbeddingDimension)
+	assert.Contains(t, resp.Columns, models.ColumnSchema{Name: "embedding", Type: "vector(384)", Nullable: false})
+	assert.Contains(t, resp.Columns, models.ColumnSchema{Name: "content_hash", Type: "text", Nullable: true})
+	require.Len(t, resp.Indexes, 2)
+	assert.Equal(t, "idx_files_embedding", resp.Indexes[1].Name)
+
+	assert.Equal(t, http.StatusUnauthorized, getAdmin(fake, config.Config{AdminToken: "secret"}, "/admin/schema", "").Code)
+}
+
+// TestStorageHandler verifies embedding bytes are computed as total dimensions × 4
+func TestStorageHandler(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("GetStorageStats", func(args ...any) ([][]any, error) {
+		// 1000 files of 384 dimensions
+		return [][]any{{int64(1000), int64(384 * 1000), int64(52_000), int64(2_500_000)}}, nil
+	})
+
+	w := getAdmin(fake, config.Config{AdminToken: "secret"}, "/admin/storage", "secret")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.StorageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.StorageResponse{
+		Files:          1000,
+		EmbeddingBytes: 1000 * 384 * 4,
+		ContentBytes:   52_000,
+		TotalBytes:     2_500_000,
+	}, resp)
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/auth"
+)
+
+type storedKey struct {
+	id      pgtype.UUID
+	userID  string
+	hash    string
+	revoked bool
+}
+
+// newKeyStore keeps api_keys rows in memory for the key queries
+func newKeyStore() (*fakeDB, map[string]*storedKey) {
+	keys := map[string]*storedKey{}
+	fake := newFakeDB()
+	fake.on("CreateAPIKey", func(args ...any) ([][]any, error) {
+		k := &storedKey{id: pgtype.UUID{Bytes: uuid.New(), Valid: true}, userID: args[0].(string), hash: args[1].(string)}
+		keys[k.hash] = k
+		return [][]any{{k.id, k.userID, pgtype.Timestamptz{Time: time.Now(), Valid: true}}}, nil
+	})
+	fake.on("GetActiveAPIKeyByHash", func(args ...any) ([][]any, error) {
+		k, ok := keys[args[0].(string)]
+		if !ok || k.revoked {
+			return nil, nil
+		}
+		return [][]any{{k.id, k.userID, k.hash}}, nil
+	})
+	fake.on("RevokeAPIKey", func(args ...any) ([][]any, error) {
+		for _, k := range keys {
+			if k.id == args[0].(pgtype.UUID) && k.userID == args[1].(string) && !k.revoked {
+				k.revoked = true
+				return [][]any{{}}, nil
+			}
+		}
+		return nil, nil
+	})
+	return fake, keys
+}
+
+func setupKeyRouter(fake *fakeDB) *gin.Engine {
+	router := setupHandlersTestRouter()
+	keys := router.Group("/keys", auth.JWTMiddleware(testJWTSecret))
+	keys.POST("", handlers.CreateAPIKeyHandler(fake.queries()))
+	keys.DELETE("/:id", handlers.RevokeAPIKeyHandler(fake.queries()))
+	files := router.Group("/files", auth.JWTOrAPIKeyMiddleware(testJWTSecret, fake.queries()))
+	files.GET("/getall", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": auth.UserID(c)})
+	})
+	return router
+}
+
+func bearerFor(userID string) string {
+	return "Bearer " + signJWT("HS256", testJWTSecret, map[string]any{"user_id": userID, "exp": time.Now().Add(time.Hour).Unix()})
+}
+
+func sendKeyRequest(router *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestAPIKeyLifecycle verifies a minted key authenticates as its owner until revoked, and only its hash is stored
+func TestAPIKeyLifecycle(t *testing.T) {
+	fake, stored := newKeyStore()
+	router := setupKeyRouter(fake)
+
+	w := sendKeyRequest(router, "POST", "/keys", map[string]string{"Authorization": bearerFor("alice")})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var minted models.APIKeyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &minted))
+	assert.True(t, strings.HasPrefix(minted.Key, "rag_"))
+	assert.Equal(t, "alice", minted.UserID)
+	require.Len(t, stored, 1)
+	for hash := range stored {
+		assert.Equal(t, auth.HashAPIKey(minted.Key), hash)
+		assert.NotContains(t, hash, minted.Key, "the plaintext key is never stored")
+	}
+
+	w = sendKeyRequest(router, "GET", "/files/getall", map[string]string{"X-API-Key": minted.Key})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id":"alice"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, sendKeyRequest(router, "DELETE", "/keys/"+minted.ID, map[string]string{"Authorization": bearerFor("mallory")}).Code,
+		"other users cannot revoke the key")
+	assert.Equal(t, http.StatusNoContent, sendKeyRequest(router, "DELETE", "/keys/"+minted.ID, map[string]string{"Authorization": bearerFor("alice")}).Code)
+	assert.Equal(t, http.StatusNotFound, sendKeyRequest(router, "DELETE", "/keys/"+minted.ID, map[string]string{"Authorization": bearerFor("alice")}).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, sendKeyRequest(router, "GET", "/files/getall", map[string]string{"X-API-Key": minted.Key}).Code,
+		"revoked keys stop authenticating")
+}
+
+// TestAPIKeyMiddlewareRejects verifies unknown keys get 401 and bearer tokens still work without a key
+func TestAPIKeyMiddlewareRejects(t *testing.T) {
+	fake, _ := newKeyStore()
+	router := setupKeyRouter(fake)
+
+	assert.Equal(t, http.StatusUnauthorized, sendKeyRequest(router, "GET", "/files/getall", map[string]string{"X-API-Key": "rag_unknown"}).Code)
+	assert.Equal(t, http.StatusUnauthorized, sendKeyRequest(router, "GET", "/files/getall", nil).Code)
+	assert.Equal(t, http.StatusOK, sendKeyRequest(router, "GET", "/files/getall", map[string]string{"Authorization": bearerFor("bob")}).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, sendKeyRequest(router, "POST", "/keys", nil).Code, "minting requires a JWT")
+	assert.Equal(t, http.StatusBadRequest, sendKeyRequest(router, "DELETE", "/keys/not-a-uuid", map[string]string{"Authorization": bearerFor("bob")}).Code)
+}
@@ -0,0 +1,277 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/auth"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// newAuditedRouter serves routes behind AuditMiddleware the way NewRouter does,
+// with the caller authenticated as alice.
+func newAuditedRouter(fake *fakeDB, logs *bytes.Buffer) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery(), handlers.RequestIDMiddleware(), func(c *gin.Context) {
+		c.Set(auth.UserIDKey, "alice")
+	})
+	audit := handlers.AuditMiddleware(fake.queries(), slog.New(slog.NewTextHandler(logs, nil)))
+
+	router.DELETE("/files/:id", audit, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.PUT("/files/:id", audit, func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+	})
+	router.PATCH("/files/:id/soft-delete", audit, func(c *gin.Context) { panic("boom") })
+	router.POST("/files/upload", audit, func(c *gin.Context) {
+		c.Set(handlers.AuditFileIDKey, c.GetHeader("X-Created"))
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func recordAudits(fake *fakeDB) *[][]any {
+	var entries [][]any
+	fake.on("CreateAuditEntry", func(args ...any) ([][]any, error) {
+		entries = append(entries, args)
+		return nil, nil
+	})
+	return &entries
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	id := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		fake := newFakeDB()
+		entries := recordAudits(fake)
+
+		req, _ := http.NewRequest("DELETE", "/files/"+id.String(), nil)
+		req.Header.Set(handlers.RequestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		newAuditedRouter(fake, &bytes.Buffer{}).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Len(t, *entries, 1)
+		assert.Equal(t, []any{
+			pgtype.Text{String: "alice", Valid: true},
+			"DELETE /files/:id",
+			pgtype.UUID{Bytes: id, Valid: true},
+			int32(http.StatusNoContent),
+			pgtype.Text{String: "req-1", Valid: true},
+		}, (*entries)[0])
+	})
+
+	t.Run("FailureKeepsStatus", func(t *testing.T) {
+		fake := newFakeDB()
+		entries := recordAudits(fake)
+
+		req, _ := http.NewRequest("PUT", "/files/"+id.String(), nil)
+		w := httptest.NewRecorder()
+		newAuditedRouter(fake, &bytes.Buffer{}).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Len(t, *entries, 1)
+		assert.Equal(t, int32(http.StatusNotFound), (*entries)[0][3])
+	})
+
+	t.Run("PanicRecordedAsFailure", func(t *testing.T) {
+		fake := newFakeDB()
+		entries := recordAudits(fake)
+
+		req, _ := http.NewRequest("PATCH", "/files/"+id.String()+"/soft-delete", nil)
+		w := httptest.NewRecorder()
+		newAuditedRouter(fake, &bytes.Buffer{}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Len(t, *entries, 1)
+		assert.Equal(t, int32(http.StatusInternalServerError), (*entries)[0][3])
+	})
+
+	t.Run("HandlerSuppliesFileID", func(t *testing.T) {
+		fake := newFakeDB()
+		entries := recordAudits(fake)
+
+		req, _ := http.NewRequest("POST", "/files/upload", nil)
+		req.Header.Set("X-Created", id.String())
+		w := httptest.NewRecorder()
+		newAuditedRouter(fake, &bytes.Buffer{}).ServeHTTP(w, req)
+
+		require.Len(t, *entries, 1)
+		assert.Equal(t, "POST /files/upload", (*entries)[0][1])
+		assert.Equal(t, pgtype.UUID{Bytes: id, Valid: true}, (*entries)[0][2])
+	})
+
+	t.Run("WriteFailureIsLoggedNotReturned", func(t *testing.T) {
+		fake := newFakeDB()
+		fake.on("CreateAuditEntry", func(args ...any) ([][]any, error) {
+			return nil, errors.New("disk full")
+		})
+		var logs bytes.Buffer
+
+		req, _ := http.NewRequest("DELETE", "/files/"+id.String(), nil)
+		w := httptest.NewRecorder()
+		newAuditedRouter(fake, &logs).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Contains(t, logs.String(), "audit entry not recorded")
+		assert.Contains(t, logs.String(), "disk full")
+	})
+}
+
+// TestBulkDeleteAuditsEachFile checks a bulk delete leaves one entry per
+// removed file, and a rejected one a single entry with no file.
+func TestBulkDeleteAuditsEachFile(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	fake := newBulkDeleteStore(a, b)
+	entries := recordAudits(fake)
+	router := newAuditedRouter(fake, &bytes.Buffer{})
+	router.POST("/files/bulk-delete", handlers.AuditMiddleware(fake.queries(), slog.Default()), handlers.BulkDeleteHandler(fake.queries()))
+
+	post := func(ids []string) int {
+		body, _ := json.Marshal(models.BulkDeleteRequest{IDs: ids})
+		req, _ := http.NewRequest("POST", "/files/bulk-delete", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, post([]string{a.String(), uuid.NewString(), b.String()}))
+	require.Len(t, *entries, 2, "the missing id was not deleted and is not recorded")
+	for i, id := range []uuid.UUID{a, b} {
+		assert.Equal(t, "POST /files/bulk-delete", (*entries)[i][1])
+		assert.Equal(t, pgtype.UUID{Bytes: id, Valid: true}, (*entries)[i][2])
+		assert.Equal(t, int32(http.StatusOK), (*entries)[i][3])
+	}
+
+	require.Equal(t, http.StatusBadRequest, post(nil))
+	require.Len(t, *entries, 3)
+	assert.Equal(t, pgtype.UUID{}, (*entries)[2][2])
+	assert.Equal(t, int32(http.StatusBadRequest), (*entries)[2][3])
+}
+
+// TestAuditRecordsAffectedFiles checks routes that create or remove files
+// without a matching :id record the files they actually changed.
+func TestAuditRecordsAffectedFiles(t *testing.T) {
+	t.Run("CloneRecordsNewFile", func(t *testing.T) {
+		sourceID := uuid.New()
+		fake := newCloneStore(db.File{ID: pgtype.UUID{Bytes: sourceID, Valid: true}, Filename: "template.txt"})
+		entries := recordAudits(fake)
+		router := newAuditedRouter(fake, &bytes.Buffer{})
+		router.POST("/files/:id/clone", handlers.AuditMiddleware(fake.queries(), slog.Default()), handlers.CloneHandler(fake.queries()))
+
+		req, _ := http.NewRequest("POST", "/files/"+sourceID.String()+"/clone", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var clone clonedFile
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &clone))
+		require.Len(t, *entries, 1)
+		assert.Equal(t, pgtype.UUID{Bytes: uuid.MustParse(clone.ID), Valid: true}, (*entries)[0][2])
+	})
+
+	t.Run("SyncRecordsCreatedAndUpdated", func(t *testing.T) {
+		changed := db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "changed.txt", Content: "old content"}
+		same := db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "same.txt", Content: "same content"}
+		fake, store, _ := newFileStore(changed, same)
+		entries := recordAudits(fake)
+		router := newAuditedRouter(fake, &bytes.Buffer{})
+		router.POST("/files/sync", handlers.AuditMiddleware(fake.queries(), slog.Default()), handlers.SyncHandler(fake.queries(), config.EmbeddingConfig{}, 0))
+
+		body, _ := json.Marshal(models.FileSyncRequest{Files: []models.FileUploadRequest{
+			{Filename: "new.txt", Content: "brand new", Embedding: []float32{0.1}},
+			{Filename: "same.txt", Content: "same content", Embedding: []float32{0.2}},
+			{Filename: "changed.txt", Content: "new content", Embedding: []float32{0.3}},
+		}})
+		req, _ := http.NewRequest("POST", "/files/sync", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		require.Len(t, *entries, 2, "unchanged files are not recorded")
+		assert.Equal(t, store["new.txt"].ID, (*entries)[0][2])
+		assert.Equal(t, changed.ID, (*entries)[1][2])
+	})
+
+	t.Run("EmptyRecycleBinRecordsPurgedFiles", func(t *testing.T) {
+		a, b := uuid.New(), uuid.New()
+		fake := newFakeDB()
+		fake.on("PurgeRecycleBin", func(args ...any) ([][]any, error) {
+			return [][]any{{pgtype.UUID{Bytes: a, Valid: true}}, {pgtype.UUID{Bytes: b, Valid: true}}}, nil
+		})
+		entries := recordAudits(fake)
+		router := newAuditedRouter(fake, &bytes.Buffer{})
+		router.DELETE("/files/recycle-bin", handlers.AuditMiddleware(fake.queries(), slog.Default()), handlers.EmptyRecycleBinHandler(fake.queries()))
+
+		req, _ := http.NewRequest("DELETE", "/files/recycle-bin", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		require.Len(t, *entries, 2)
+		assert.Equal(t, pgtype.UUID{Bytes: a, Valid: true}, (*entries)[0][2])
+		assert.Equal(t, pgtype.UUID{Bytes: b, Valid: true}, (*entries)[1][2])
+		assert.Contains(t, fake.lastSQL("PurgeRecycleBin"), "RETURNING id")
+	})
+}
+
+func TestListAuditHandler(t *testing.T) {
+	id := uuid.New()
+	at := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	fake := newFakeDB()
+	var params []any
+	fake.on("ListAuditEntries", func(args ...any) ([][]any, error) {
+		params = args
+		return [][]any{
+			{int64(2), pgtype.Text{String: "alice", Valid: true}, "PUT /files/:id", pgtype.UUID{Bytes: id, Valid: true}, int32(404), pgtype.Text{String: "req-2", Valid: true}, pgtype.Timestamptz{Time: at, Valid: true}},
+			{int64(1), pgtype.Text{}, "POST /files/upload", pgtype.UUID{Bytes: id, Valid: true}, int32(200), pgtype.Text{}, pgtype.Timestamptz{Time: at.Add(-time.Minute), Valid: true}},
+		}, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.GET("/audit", handlers.ListAuditHandler(fake.queries()))
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/audit"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?file_id=" + id.String() + "&limit=10")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []any{pgtype.UUID{Bytes: id, Valid: true}, int32(10)}, params)
+
+	var entries []models.AuditEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, models.AuditEntry{
+		ID: 2, UserID: "alice", Action: "PUT /files/:id", FileID: id.String(),
+		Status: 404, Success: false, RequestID: "req-2", CreatedAt: at,
+	}, entries[0])
+	assert.True(t, entries[1].Success)
+	assert.Empty(t, entries[1].UserID)
+
+	require.Equal(t, http.StatusOK, get("").Code)
+	assert.Equal(t, []any{pgtype.UUID{}, int32(100)}, params, "no filter and the default limit")
+
+	for _, query := range []string{"?file_id=nope", "?limit=0", "?limit=1001", "?limit=x"} {
+		assert.Equal(t, http.StatusBadRequest, get(query).Code, query)
+	}
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/auth"
+)
+
+const testJWTSecret = "test-secret"
+
+// signJWT builds a token with the given header algorithm and claims, signed with HS256 under secret
+func signJWT(alg, secret string, claims map[string]any) string {
+	enc := func(v any) string {
+		raw, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	unsigned := enc(map[string]string{"alg": alg, "typ": "JWT"}) + "." + enc(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func setupAuthRouter(secret string) *gin.Engine {
+	router := setupHandlersTestRouter()
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	files := router.Group("/files", auth.JWTMiddleware(secret))
+	files.GET("/getall", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": auth.UserID(c)})
+	})
+	return router
+}
+
+func sendWithAuth(router *gin.Engine, path, authorization string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestJWTMiddlewareValidToken verifies a valid token passes and exposes its user_id claim
+func TestJWTMiddlewareValidToken(t *testing.T) {
+	router := setupAuthRouter(testJWTSecret)
+	token := signJWT("HS256", testJWTSecret, map[string]any{"user_id": "u-42", "exp": time.Now().Add(time.Hour).Unix()})
+
+	w := sendWithAuth(router, "/files/getall", "Bearer "+token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id":"u-42"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusOK, sendWithAuth(router, "/healthz", "").Code, "health stays open")
+}
+
+// TestJWTMiddlewareRejects verifies missing, expired, malformed, and forged tokens get 401
+func TestJWTMiddlewareRejects(t *testing.T) {
+	router := setupAuthRouter(testJWTSecret)
+	future := time.Now().Add(time.Hour).Unix()
+
+	// Swap the payload of a valid token for one claiming another user, keeping the original signature.
+	valid := strings.Split(signJWT("HS256", testJWTSecret, map[string]any{"user_id": "u", "exp": future}), ".")
+	forged := strings.Split(signJWT("HS256", "", map[string]any{"user_id": "admin", "exp": future}), ".")
+	tampered := valid[0] + "." + forged[1] + "." + valid[2]
+
+	cases := map[string]string{
+		"missing":          "",
+		"not bearer":       "Basic dXNlcjpwYXNz",
+		"empty bearer":     "Bearer ",
+		"expired":          "Bearer " + signJWT("HS256", testJWTSecret, map[string]any{"user_id": "u", "exp": time.Now().Add(-time.Minute).Unix()}),
+		"malformed":        "Bearer not.a-jwt",
+		"garbage segments": "Bearer a.b.c",
+		"wrong secret":     "Bearer " + signJWT("HS256", "other", map[string]any{"user_id": "u", "exp": future}),
+		"alg none":         "Bearer " + signJWT("none", testJWTSecret, map[string]any{"user_id": "u", "exp": future}),
+		"no exp":           "Bearer " + signJWT("HS256", testJWTSecret, map[string]any{"user_id": "u"}),
+		"no user_id":       "Bearer " + signJWT("HS256", testJWTSecret, map[string]any{"exp": future}),
+		"not yet valid":    "Bearer " + signJWT("HS256", testJWTSecret, map[string]any{"user_id": "u", "exp": future, "nbf": future}),
+		"tampered payload": "Bearer " + tampered,
+	}
+	for name, header := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := sendWithAuth(router, "/files/getall", header)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.NotContains(t, w.Body.String(), "user_id")
+		})
+	}
+
+	expired := signJWT("HS256", testJWTSecret, map[string]any{"user_id": "u", "exp": time.Now().Add(-time.Minute).Unix()})
+	assert.JSONEq(t, `{"error":"token expired"}`, sendWithAuth(router, "/files/getall", "Bearer "+expired).Body.String())
+}
+
+// TestJWTMiddlewareEmptySecret verifies an unset secret fails closed instead of accepting unsigned tokens
+func TestJWTMiddlewareEmptySecret(t *testing.T) {
+	router := setupAuthRouter("")
+	token := signJWT("HS256", "", map[string]any{"user_id": "u", "exp": time.Now().Add(time.Hour).Unix()})
+	assert.Equal(t, http.StatusUnauthorized, sendWithAuth(router, "/files/getall", "Bearer "+token).Code)
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/routes"
+	"github.com/fain17/rag-backend/config"
+)
+
+// TestOversizedUploadRejected posts past MAX_BODY_BYTES through the full router
+// and expects 413 before any handler touches the database.
+func TestOversizedUploadRejected(t *testing.T) {
+	fake := newFakeDB()
+	router := routes.NewRouter(context.Background(), fake.queries(), config.Config{MaxBodyBytes: 1024}, nil)
+
+	for _, path := range []string{"/files/upload", "/files/sync", "/files/bulk-delete"} {
+		body := `{"filename":"big.txt","content":"` + strings.Repeat("a", 4096) + `"}`
+		req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, path)
+		assert.JSONEq(t, `{"error":"request body too large"}`, w.Body.String(), path)
+	}
+	assert.Empty(t, fake.calls)
+}
+
+// TestGzipBodyInflatingPastLimitRejected sends a gzip body well under MAX_BODY_BYTES
+// on the wire that inflates past it, and expects 413 rather than the larger
+// MAX_DECOMPRESSED_BYTES cap to apply.
+func TestGzipBodyInflatingPastLimitRejected(t *testing.T) {
+	fake := newFakeDB()
+	router := routes.NewRouter(context.Background(), fake.queries(), config.Config{MaxBodyBytes: 1024, MaxDecompressedBytes: 1 << 20}, nil)
+
+	body := gzipBytes(t, []byte(`{"filename":"big.txt","content":"`+strings.Repeat("a", 8192)+`"}`))
+	require.Less(t, len(body), 1024)
+	req, _ := http.NewRequest("POST", "/files/upload", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Zero(t, fake.called("CreateFile"))
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	newRouter := func(limit int64) *gin.Engine {
+		router := setupHandlersTestRouter()
+		router.Use(handlers.MaxBodyBytes(limit))
+		router.POST("/echo", func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			c.String(http.StatusOK, "%d", len(body))
+		})
+		return router
+	}
+	post := func(router *gin.Engine, body io.Reader, contentLength int64) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/echo", body)
+		req.ContentLength = contentLength
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("WithinLimit", func(t *testing.T) {
+		w := post(newRouter(16), strings.NewReader("0123456789abcdef"), 16)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "16", w.Body.String())
+	})
+
+	t.Run("DeclaredLengthOverLimit", func(t *testing.T) {
+		w := post(newRouter(16), strings.NewReader(strings.Repeat("x", 17)), 17)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("ChunkedOverLimit", func(t *testing.T) {
+		// ContentLength -1 is an unknown length, as with chunked transfer encoding.
+		w := post(newRouter(16), io.MultiReader(strings.NewReader(strings.Repeat("x", 10)), strings.NewReader(strings.Repeat("y", 10))), -1)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("ZeroDisables", func(t *testing.T) {
+		w := post(newRouter(0), bytes.NewReader(make([]byte, 1<<16)), 1<<16)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "65536", w.Body.String())
+	})
+}
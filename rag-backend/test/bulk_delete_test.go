@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+)
+
+// newBulkDeleteStore answers both bulk delete queries with the requested IDs that exist
+func newBulkDeleteStore(existing ...uuid.UUID) *fakeDB {
+	answer := func(args ...any) ([][]any, error) {
+		var rows [][]any
+		for _, id := range args[0].([]pgtype.UUID) {
+			for _, e := range existing {
+				if id.Bytes == e {
+					rows = append(rows, []any{id})
+				}
+			}
+		}
+		return rows, nil
+	}
+	fake := newFakeDB()
+	fake.on("DeleteFilesByIDs", answer)
+	fake.on("SoftDeleteFilesByIDs", answer)
+	return fake
+}
+
+func postBulkDelete(fake *fakeDB, body any) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/bulk-delete", handlers.BulkDeleteHandler(fake.queries()))
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/bulk-delete", bytes.NewBuffer(raw))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestBulkDeleteHandler verifies hard and soft bulk deletes report deleted and missing IDs
+func TestBulkDeleteHandler(t *testing.T) {
+	a, b, missing := uuid.New(), uuid.New(), uuid.New()
+
+	for _, soft := range []bool{false, true} {
+		fake := newBulkDeleteStore(a, b)
+		w := postBulkDelete(fake, models.BulkDeleteRequest{IDs: []string{a.String(), missing.String(), b.String(), a.String()}, Soft: soft})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.BulkDeleteResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{a.String(), b.String()}, resp.Deleted, "duplicates are collapsed")
+		assert.Equal(t, []string{missing.String()}, resp.NotFound)
+		assert.Equal(t, soft, resp.Soft)
+
+		if soft {
+			assert.Equal(t, 1, fake.called("SoftDeleteFilesByIDs"))
+			assert.Zero(t, fake.called("DeleteFilesByIDs"))
+			assert.Contains(t, fake.lastSQL("SoftDeleteFilesByIDs"), "COALESCE(deleted_at", "re-deleting keeps the original time")
+		} else {
+			assert.Equal(t, 1, fake.called("DeleteFilesByIDs"))
+			assert.Zero(t, fake.called("SoftDeleteFilesByIDs"))
+		}
+	}
+}
+
+// TestBulkDeleteValidation verifies bad input is rejected before anything is deleted
+func TestBulkDeleteValidation(t *testing.T) {
+	tooMany := make([]string, 1001)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+	cases := map[string]any{
+		"missing ids":  map[string]any{},
+		"empty ids":    models.BulkDeleteRequest{IDs: []string{}},
+		"invalid uuid": models.BulkDeleteRequest{IDs: []string{uuid.NewString(), "nope"}},
+		"too many":     models.BulkDeleteRequest{IDs: tooMany},
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := newBulkDeleteStore()
+			assert.Equal(t, http.StatusBadRequest, postBulkDelete(fake, body).Code)
+			assert.Zero(t, fake.called("DeleteFilesByIDs")+fake.called("SoftDeleteFilesByIDs"))
+		})
+	}
+
+	var resp map[string]any
+	w := postBulkDelete(newBulkDeleteStore(), models.BulkDeleteRequest{IDs: []string{"nope"}})
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "nope", resp["id"], "the offending id is reported")
+}
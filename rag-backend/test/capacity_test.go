@@ -0,0 +1,52 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/api/handlers"
+)
+
+// TestCapacityGuard verifies writes get 507 over the limit while reads continue, and recover once space frees up
+func TestCapacityGuard(t *testing.T) {
+	size := int64(2000)
+	fake := newFakeDB()
+	fake.on("GetDatabaseSize", func(args ...any) ([][]any, error) {
+		return [][]any{{size}}, nil
+	})
+	guard := handlers.NewCapacityGuard(fake.queries(), 1000)
+
+	router := setupHandlersTestRouter()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/files/upload", guard.RejectWhenFull(), ok)
+	router.GET("/files/getall", ok)
+	send := func(method, path string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("POST", "/files/upload"), "writes are allowed before the first check")
+
+	guard.Check(context.Background())
+	assert.Equal(t, http.StatusInsufficientStorage, send("POST", "/files/upload"))
+	assert.Equal(t, http.StatusOK, send("GET", "/files/getall"))
+
+	size = 500
+	guard.Check(context.Background())
+	assert.Equal(t, http.StatusOK, send("POST", "/files/upload"))
+}
+
+// TestCapacityGuardDisabled verifies a zero limit never queries or rejects
+func TestCapacityGuardDisabled(t *testing.T) {
+	fake := newFakeDB()
+	guard := handlers.NewCapacityGuard(fake.queries(), 0)
+	guard.Check(context.Background())
+	assert.Zero(t, fake.called("GetDatabaseSize"))
+}
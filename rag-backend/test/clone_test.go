@@ -0,0 +1,105 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/db"
+)
+
+// newCloneStore answers CloneFile by copying the source row like the INSERT ... SELECT would
+func newCloneStore(source db.File) *fakeDB {
+	fake := newFakeDB()
+	fake.on("CloneFile", func(args ...any) ([][]any, error) {
+		filename, id := args[0].(pgtype.Text), args[1].(pgtype.UUID)
+		if id != source.ID || source.Deleted.Bool {
+			return nil, nil
+		}
+		clone := source
+		clone.ID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+		clone.Filename = "Copy of " + source.Filename
+		if filename.Valid {
+			clone.Filename = filename.String
+		}
+		return [][]any{fileRow(clone)}, nil
+	})
+	return fake
+}
+
+func postClone(fake *fakeDB, id, body string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/:id/clone", handlers.CloneHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/"+id+"/clone", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+type clonedFile struct {
+	ID        string          `json:"ID"`
+	Filename  string          `json:"Filename"`
+	Content   string          `json:"Content"`
+	Embedding pgvector.Vector `json:"Embedding"`
+	Tags      []string        `json:"Tags"`
+}
+
+// TestCloneHandler verifies the clone gets a new ID but keeps content and embedding
+func TestCloneHandler(t *testing.T) {
+	sourceID := uuid.New()
+	source := db.File{
+		ID:          pgtype.UUID{Bytes: sourceID, Valid: true},
+		Filename:    "template.txt",
+		Content:     "template body",
+		Embedding:   pgvector.NewVector([]float32{0.25, 0.5, 0.75}),
+		ContentHash: pgtype.Text{String: sha256Hex("template body"), Valid: true},
+		Tags:        []string{"templates", "2026"},
+	}
+	fake := newCloneStore(source)
+
+	w := postClone(fake, sourceID.String(), `{"filename": "branch.txt"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var clone clonedFile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &clone))
+	assert.NotEqual(t, sourceID.String(), clone.ID)
+	assert.Equal(t, "branch.txt", clone.Filename)
+	assert.Equal(t, source.Content, clone.Content)
+	assert.Equal(t, source.Embedding.Slice(), clone.Embedding.Slice())
+	assert.Equal(t, source.Tags, clone.Tags)
+	assert.Contains(t, fake.lastSQL("CloneFile"), "INSERT INTO files", "the copy must be made in SQL without re-embedding")
+	assert.Contains(t, fake.lastSQL("CloneFile"), "src.tags", "the copy must keep the source's tags")
+
+	t.Run("DefaultFilename", func(t *testing.T) {
+		w := postClone(fake, sourceID.String(), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &clone))
+		assert.Equal(t, "Copy of template.txt", clone.Filename)
+	})
+}
+
+// TestCloneHandlerErrors covers missing sources and malformed input
+func TestCloneHandlerErrors(t *testing.T) {
+	fake := newCloneStore(db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}})
+
+	w := postClone(fake, uuid.New().String(), "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = postClone(fake, "not-a-uuid", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postClone(fake, uuid.New().String(), "{not json")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 1, fake.called("CloneFile"))
+}
@@ -0,0 +1,189 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// TestConfigLoadDefaults verifies the defaults applied when no embedding variables are set
+func TestConfigLoadDefaults(t *testing.T) {
+	t.Setenv("EXPECTED_EMBEDDING_DIM", "")
+	t.Setenv("EMBEDDING_PROVIDER", "")
+	t.Setenv("EMBEDDING_MODEL", "")
+	t.Setenv("EMBEDDING_MODELS", "")
+	t.Setenv("DEFAULT_METRIC", "")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.Embedding.ExpectedDim)
+	assert.Empty(t, cfg.Embedding.Models)
+	assert.Equal(t, "cosine", cfg.Embedding.DefaultMetric)
+}
+
+// TestConfigLoadListenAddress verifies PORT and HOST resolve the listen address and bad ports are rejected
+func TestConfigLoadListenAddress(t *testing.T) {
+	t.Setenv("PORT", "")
+	t.Setenv("HOST", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.Addr)
+
+	t.Setenv("PORT", "3000")
+	t.Setenv("HOST", "127.0.0.1")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:3000", cfg.Addr)
+
+	t.Setenv("HOST", "::1")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "[::1]:3000", cfg.Addr)
+
+	for _, port := range []string{"0", "65536", "http"} {
+		t.Setenv("PORT", port)
+		_, err := config.Load()
+		assert.Error(t, err, port)
+	}
+}
+
+// TestConfigLoadRecycleBinTTL verifies the TTL defaults to 30 days, 0 disables it, and negatives are rejected
+func TestConfigLoadRecycleBinTTL(t *testing.T) {
+	t.Setenv("RECYCLE_BIN_TTL_DAYS", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, cfg.RecycleBinTTL)
+
+	t.Setenv("RECYCLE_BIN_TTL_DAYS", "0")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.RecycleBinTTL)
+
+	t.Setenv("RECYCLE_BIN_TTL_DAYS", "-1")
+	_, err = config.Load()
+	assert.Error(t, err)
+}
+
+// TestConfigLoadMaxFileVersions verifies the cap defaults to 20, 0 keeps every version, and negatives are rejected
+func TestConfigLoadMaxFileVersions(t *testing.T) {
+	t.Setenv("FILE_VERSIONS_MAX", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 20, cfg.MaxFileVersions)
+
+	t.Setenv("FILE_VERSIONS_MAX", "0")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxFileVersions)
+
+	t.Setenv("FILE_VERSIONS_MAX", "-1")
+	_, err = config.Load()
+	assert.Error(t, err)
+}
+
+// TestConfigLoadMaxBodyBytes verifies the body limit defaults to 10 MiB, 0 disables it, and negatives are rejected
+func TestConfigLoadMaxBodyBytes(t *testing.T) {
+	t.Setenv("MAX_BODY_BYTES", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, int64(10<<20), cfg.MaxBodyBytes)
+
+	t.Setenv("MAX_BODY_BYTES", "0")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxBodyBytes)
+
+	t.Setenv("MAX_BODY_BYTES", "-1")
+	_, err = config.Load()
+	assert.Error(t, err)
+}
+
+// TestConfigLoadDBQueryTimeout verifies the query timeout defaults to 10s and must be positive
+func TestConfigLoadDBQueryTimeout(t *testing.T) {
+	t.Setenv("DB_QUERY_TIMEOUT", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, cfg.DBQueryTimeout)
+
+	t.Setenv("DB_QUERY_TIMEOUT", "2s")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, cfg.DBQueryTimeout)
+
+	for _, bad := range []string{"0s", "-1s", "soon"} {
+		t.Setenv("DB_QUERY_TIMEOUT", bad)
+		_, err = config.Load()
+		assert.Error(t, err, bad)
+	}
+}
+
+// TestConfigLoadInvalidDimension verifies a non-numeric or negative dimension is rejected at startup
+func TestConfigLoadInvalidDimension(t *testing.T) {
+	for _, bad := range []string{"abc", "-1"} {
+		t.Setenv("EXPECTED_EMBEDDING_DIM", bad)
+
+		_, err := config.Load()
+		assert.Error(t, err, bad)
+	}
+}
+
+// TestEmbeddingConfigHandler verifies the discovery endpoint reflects the configured values
+func TestEmbeddingConfigHandler(t *testing.T) {
+	t.Setenv("EXPECTED_EMBEDDING_DIM", "384")
+	t.Setenv("EMBEDDING_PROVIDER", "openai")
+	t.Setenv("EMBEDDING_MODEL", "")
+	t.Setenv("EMBEDDING_MODELS", "text-embedding-3-small, all-MiniLM-L6-v2")
+	t.Setenv("DEFAULT_METRIC", "l2")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	router := setupHandlersTestRouter()
+	router.GET("/config/embeddings", handlers.EmbeddingConfigHandler(cfg.Embedding))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/config/embeddings", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.EmbeddingConfigResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 384, response.ExpectedDimension)
+	assert.Equal(t, []string{"text-embedding-3-small", "all-MiniLM-L6-v2"}, response.Models)
+	assert.Equal(t, []string{"openai"}, response.Providers)
+	assert.Equal(t, "l2", response.DefaultMetric)
+}
+
+// TestMetricsHandler verifies every supported metric is listed with its operator and direction
+func TestMetricsHandler(t *testing.T) {
+	router := setupHandlersTestRouter()
+	router.GET("/config/metrics", handlers.MetricsHandler())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/config/metrics", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.MetricInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	operators := map[string]string{}
+	for _, m := range response {
+		operators[m.Name] = m.Operator
+		assert.True(t, vector.ValidMetric(m.Name), m.Name)
+		assert.Equal(t, "lower", m.Better, "%s distances rank closest first", m.Name)
+		assert.NotEmpty(t, m.Description, m.Name)
+	}
+	assert.Equal(t, map[string]string{"l2": "<->", "cosine": "<=>", "inner": "<#>"}, operators)
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// TestNewPoolConfigRegistersVectorTypes verifies the pgvector hook is on the config before the pool is built
+func TestNewPoolConfigRegistersVectorTypes(t *testing.T) {
+	cfg, err := db.NewPoolConfig("postgres://user@localhost:5432/db", db.PoolConfig{MaxConns: 7})
+	require.NoError(t, err)
+	assert.NotNil(t, cfg.AfterConnect, "connections opened by the pool must register the vector type")
+	assert.Equal(t, int32(7), cfg.MaxConns)
+
+	_, err = db.NewPoolConfig("://not a url", db.PoolConfig{})
+	assert.Error(t, err)
+}
+
+// TestVectorRoundTrip verifies a vector survives encode and decode on a pooled connection.
+// It needs a real Postgres with pgvector available and runs only when TEST_DATABASE_URL is set.
+func TestVectorRoundTrip(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, url)
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS vector")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close(ctx))
+
+	cfg, err := db.NewPoolConfig(url, db.PoolConfig{MaxConns: 1})
+	require.NoError(t, err)
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	want := pgvector.NewVector([]float32{0.5, -1, 2.25})
+	var got pgvector.Vector
+	require.NoError(t, pool.QueryRow(ctx, "SELECT $1::vector", want).Scan(&got))
+	assert.Equal(t, want.Slice(), got.Slice())
+}
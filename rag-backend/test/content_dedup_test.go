@@ -0,0 +1,166 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/embedding"
+)
+
+func uploadContent(fake *fakeDB, embedder *stubEmbedder, query string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	var e embedding.Embedder
+	if embedder != nil {
+		e = embedder
+	}
+	router.POST("/files/upload", handlers.UploadHandler(fake.queries(), config.EmbeddingConfig{}, e))
+
+	body, _ := json.Marshal(models.FileUploadRequest{Filename: "copy.txt", Content: "same text"})
+	req, _ := http.NewRequest("POST", "/files/upload"+query, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUploadReturnsExistingDuplicate(t *testing.T) {
+	existingID := uuid.New()
+	fake := newWriteStore()
+	var lookedUp any
+	fake.on("GetFileByContentHash", func(args ...any) ([][]any, error) {
+		lookedUp = args[0]
+		return [][]any{fileRow(db.File{
+			ID:        pgtype.UUID{Bytes: existingID, Valid: true},
+			Filename:  "original.txt",
+			Content:   "same text",
+			Embedding: pgvector.NewVector([]float32{1, 2, 3}),
+		})}, nil
+	})
+	embedder := &stubEmbedder{vec: []float32{1, 2, 3}}
+
+	w := uploadContent(fake, embedder, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var file map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &file))
+	assert.Equal(t, "original.txt", file["Filename"])
+	assert.Empty(t, w.Header().Get("Location"), "nothing was created")
+	assert.Equal(t, pgtype.Text{String: sha256Hex("same text"), Valid: true}, lookedUp)
+	assert.Zero(t, fake.called("CreateFile"))
+	assert.Empty(t, embedder.texts, "a duplicate must not be embedded")
+}
+
+func TestUploadStoresNewContent(t *testing.T) {
+	fake := newWriteStore()
+	var stored any
+	next := fake.handlers["CreateFile"]
+	fake.on("CreateFile", func(args ...any) ([][]any, error) {
+		stored = args[3]
+		return next(args...)
+	})
+
+	w := uploadContent(fake, &stubEmbedder{vec: []float32{1, 2, 3}}, "")
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, fake.called("GetFileByContentHash"), "checked before embedding and again under the lock")
+	assert.Equal(t, 1, fake.called("LockContentHash"))
+	assert.Equal(t, pgtype.Text{String: sha256Hex("same text"), Valid: true}, stored)
+
+	var created map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotEmpty(t, created["ID"])
+	assert.Equal(t, "/files/"+created["ID"].(string), w.Header().Get("Location"))
+}
+
+// TestUploadConcurrentDuplicate covers an identical upload committing after the
+// first check: the locked re-check finds it and nothing is inserted
+func TestUploadConcurrentDuplicate(t *testing.T) {
+	existingID := uuid.New()
+	fake := newWriteStore()
+	var lockedHash any
+	fake.on("LockContentHash", func(args ...any) ([][]any, error) {
+		lockedHash = args[0]
+		return nil, nil
+	})
+	fake.on("GetFileByContentHash", func(args ...any) ([][]any, error) {
+		if fake.called("LockContentHash") == 0 {
+			return nil, nil
+		}
+		return [][]any{fileRow(db.File{ID: pgtype.UUID{Bytes: existingID, Valid: true}, Filename: "racer.txt", Content: "same text"})}, nil
+	})
+
+	w := uploadContent(fake, &stubEmbedder{vec: []float32{1, 2, 3}}, "")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var file map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &file))
+	assert.Equal(t, existingID.String(), file["ID"])
+	assert.Equal(t, sha256Hex("same text"), lockedHash)
+	assert.Zero(t, fake.called("CreateFile"))
+	assert.Contains(t, fake.lastSQL("LockContentHash"), "pg_advisory_xact_lock")
+	commits, _ := fake.txCounts()
+	assert.Equal(t, 1, commits)
+}
+
+func TestUploadForceSkipsDuplicateCheck(t *testing.T) {
+	fake := newWriteStore()
+
+	w := uploadContent(fake, &stubEmbedder{vec: []float32{1, 2, 3}}, "?force=true")
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Zero(t, fake.called("GetFileByContentHash"))
+	assert.Equal(t, 1, fake.called("CreateFile"))
+	assert.Zero(t, fake.called("LockContentHash"))
+}
+
+func TestUploadDuplicateCheckFails(t *testing.T) {
+	fake := newWriteStore()
+	fake.on("GetFileByContentHash", func(args ...any) ([][]any, error) {
+		return nil, errors.New("connection reset")
+	})
+
+	w := uploadContent(fake, nil, "")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Zero(t, fake.called("CreateFile"))
+}
+
+func TestDuplicatesHandler(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	fake := newFakeDB()
+	fake.on("GetDuplicateGroups", func(args ...any) ([][]any, error) {
+		return [][]any{{
+			"abc123", int64(2),
+			[]pgtype.UUID{{Bytes: first, Valid: true}, {Bytes: second, Valid: true}},
+		}}, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.GET("/files/duplicates", handlers.DuplicatesHandler(fake.queries()))
+	req, _ := http.NewRequest("GET", "/files/duplicates", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var groups []models.DuplicateGroup
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &groups))
+	assert.Equal(t, []models.DuplicateGroup{{
+		ContentHash: "abc123",
+		Count:       2,
+		IDs:         []string{first.String(), second.String()},
+	}}, groups)
+}
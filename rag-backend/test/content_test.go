@@ -0,0 +1,101 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/api/handlers"
+)
+
+const rangeTestContent = "0123456789abcdefghij"
+
+func getContent(fake *fakeDB, id, rangeHeader string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.GET("/files/:id/content", handlers.GetContentHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/files/"+id+"/content", nil)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func newContentStore() *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetFileContent", func(args ...any) ([][]any, error) {
+		return [][]any{{"doc.txt", rangeTestContent, pgtype.Timestamptz{Time: time.Now(), Valid: true}}}, nil
+	})
+	return fake
+}
+
+// TestGetContentHandlerRanges covers full, partial, and unsatisfiable range requests
+func TestGetContentHandlerRanges(t *testing.T) {
+	id := uuid.New().String()
+
+	t.Run("FullContent", func(t *testing.T) {
+		w := getContent(newContentStore(), id, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, rangeTestContent, w.Body.String())
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("ValidRange", func(t *testing.T) {
+		w := getContent(newContentStore(), id, "bytes=5-9")
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "56789", w.Body.String())
+		assert.Equal(t, "bytes 5-9/20", w.Header().Get("Content-Range"))
+	})
+
+	t.Run("SuffixRange", func(t *testing.T) {
+		w := getContent(newContentStore(), id, "bytes=-4")
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "ghij", w.Body.String())
+	})
+
+	t.Run("OutOfBoundsRange", func(t *testing.T) {
+		w := getContent(newContentStore(), id, "bytes=100-200")
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+		assert.Equal(t, "bytes */20", w.Header().Get("Content-Range"))
+	})
+}
+
+// TestGetContentHandlerErrors covers invalid and missing files
+func TestGetContentHandlerErrors(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("GetFileContent", func(args ...any) ([][]any, error) {
+		return nil, nil
+	})
+
+	assert.Equal(t, http.StatusNotFound, getContent(fake, uuid.New().String(), "").Code)
+	assert.Equal(t, http.StatusBadRequest, getContent(fake, "nope", "").Code)
+}
+
+// TestGetContentHandlerModifiedSinceUpdate checks that date validators follow
+// updated_at, so a client holding a pre-update copy gets the new content.
+func TestGetContentHandlerModifiedSinceUpdate(t *testing.T) {
+	updated := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	fake := newFakeDB()
+	fake.on("GetFileContent", func(args ...any) ([][]any, error) {
+		return [][]any{{"doc.txt", rangeTestContent, pgtype.Timestamptz{Time: updated, Valid: true}}}, nil
+	})
+	router := setupHandlersTestRouter()
+	router.GET("/files/:id/content", handlers.GetContentHandler(fake.queries()))
+
+	req, _ := http.NewRequest("GET", "/files/"+uuid.NewString()+"/content", nil)
+	req.Header.Set("If-Modified-Since", updated.Add(-time.Hour).Format(http.TimeFormat))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, rangeTestContent, w.Body.String())
+	assert.Equal(t, updated.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/api/routes"
+	"github.com/fain17/rag-backend/config"
+)
+
+// TestDebugParseHandler covers the debug echo endpoint for well-formed and malformed bodies
+func TestDebugParseHandler(t *testing.T) {
+	// A well-formed body is echoed back with the embedding length instead of the vector
+	t.Run("WellFormedBody", func(t *testing.T) {
+		router := setupHandlersTestRouter()
+		router.POST("/files/debug-parse", handlers.DebugParseHandler(config.EmbeddingConfig{ExpectedDim: 4}))
+
+		body := `{"filename":"notes.txt","content":"hello","embedding":[0.1,0.2,0.3]}`
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/files/debug-parse", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "0.1")
+
+		var response models.DebugParseResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "notes.txt", response.Filename)
+		assert.Equal(t, 5, response.ContentLength)
+		assert.Equal(t, 3, response.EmbeddingLength)
+		assert.Equal(t, []string{"embedding has 3 dimensions, expected 4"}, response.Warnings)
+	})
+
+	// A malformed body is rejected with the binding error so clients can see what went wrong
+	t.Run("MalformedBody", func(t *testing.T) {
+		router := setupHandlersTestRouter()
+		router.POST("/files/debug-parse", handlers.DebugParseHandler(config.EmbeddingConfig{}))
+
+		body := `{"filename":"notes.txt","embedding":"not-an-array"}`
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/files/debug-parse", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid request", response["error"])
+		assert.NotEmpty(t, response["detail"])
+	})
+}
+
+// TestDebugParseRouteGating verifies the endpoint only exists when the debug flag is set
+func TestDebugParseRouteGating(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"filename":"notes.txt"}`
+
+	for _, tc := range []struct {
+		name     string
+		debug    bool
+		expected int
+	}{
+		{"Disabled", false, http.StatusNotFound},
+		{"Enabled", true, http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := routes.NewRouter(context.Background(), nil, config.Config{Debug: tc.debug}, nil)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/files/debug-parse", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/vector"
+)
+
+const explainQuery = "EXPLAIN (FORMAT JSON)"
+
+// withExplain answers EXPLAIN with a plan that scans the vector index when indexed is set.
+func withExplain(fake *fakeDB, indexed bool) *fakeDB {
+	fake.on(explainQuery, func(args ...any) ([][]any, error) {
+		scan := `{"Node Type": "Seq Scan", "Relation Name": "files"}`
+		if indexed {
+			scan = `{"Node Type": "Index Scan", "Index Name": "idx_files_embedding", "Relation Name": "files"}`
+		}
+		return [][]any{{[]byte(`[{"Plan": {"Node Type": "Limit", "Plans": [` + scan + `]}}]`)}}, nil
+	})
+	return fake
+}
+
+func postDebugSearch(router http.Handler, path string, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(raw))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func assertTimingNonNegative(t *testing.T, timing models.SearchTiming) {
+	t.Helper()
+	assert.GreaterOrEqual(t, timing.EmbeddingMs, 0.0)
+	assert.GreaterOrEqual(t, timing.RetrievalMs, 0.0)
+	assert.GreaterOrEqual(t, timing.RerankMs, 0.0)
+}
+
+// TestAdvancedSearchDebugTiming checks debug_timing wraps the results with
+// non-negative stage timings and the planner's index use, only in debug mode
+func TestAdvancedSearchDebugTiming(t *testing.T) {
+	cfg := config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}
+	body := models.AdvancedSearchRequest{Embedding: []float32{1, 0}, TopK: 2}
+
+	for _, indexed := range []bool{true, false} {
+		fake := withExplain(newSearchStore(searchCorpus), indexed)
+		router := setupHandlersTestRouter()
+		router.POST("/files/search/advanced", handlers.AdvancedSearchHandler(fake.queries(), cfg, nil, true))
+
+		w := postDebugSearch(router, "/files/search/advanced?debug_timing=true", body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp models.SearchDebugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Results, 2)
+		assertTimingNonNegative(t, resp.Timing)
+		assert.Zero(t, resp.Timing.EmbeddingMs, "the request carried its embedding")
+		assert.Equal(t, indexed, resp.Timing.IndexUsed)
+		assert.Contains(t, fake.lastSQL(explainQuery), "-- name: SearchFilesCosine")
+	}
+
+	t.Run("DebugDisabled", func(t *testing.T) {
+		fake := withExplain(newSearchStore(searchCorpus), true)
+		router := setupHandlersTestRouter()
+		router.POST("/files/search/advanced", handlers.AdvancedSearchHandler(fake.queries(), cfg, nil, false))
+
+		w := postDebugSearch(router, "/files/search/advanced?debug_timing=true", body)
+		require.Equal(t, http.StatusOK, w.Code)
+		var results []models.SearchResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results), "the plain array is returned")
+		assert.Zero(t, fake.called(explainQuery))
+	})
+
+	t.Run("EmbedsText", func(t *testing.T) {
+		fake := withExplain(newSearchStore(searchCorpus), true)
+		embedder := &stubEmbedder{vec: []float32{1, 0}}
+		router := setupHandlersTestRouter()
+		router.POST("/files/search/advanced", handlers.AdvancedSearchHandler(fake.queries(), cfg, embedder, true))
+
+		w := postDebugSearch(router, "/files/search/advanced?debug_timing=true", models.AdvancedSearchRequest{Text: "quarterly revenue"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp models.SearchDebugResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assertTimingNonNegative(t, resp.Timing)
+		assert.Equal(t, []string{"quarterly revenue"}, embedder.texts)
+		assert.NotEmpty(t, resp.Results)
+	})
+}
+
+// TestHybridSearchDebugTiming checks hybrid search reports the same timings,
+// embedding the keyword query when no embedding is sent
+func TestHybridSearchDebugTiming(t *testing.T) {
+	file := rankedFile{uuid.New(), "report.txt"}
+	fake := withExplain(newHybridStore([]rankedFile{file}, []rankedFile{file}), true)
+	embedder := &stubEmbedder{vec: []float32{1, 0}}
+	router := setupHandlersTestRouter()
+	router.POST("/files/hybrid-search", handlers.HybridSearchHandler(fake.queries(), config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}, embedder, true))
+
+	w := postDebugSearch(router, "/files/hybrid-search?debug_timing=true", models.HybridSearchRequest{Query: "report"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp models.HybridSearchDebugResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Results, 1)
+	assertTimingNonNegative(t, resp.Timing)
+	assert.True(t, resp.Timing.IndexUsed)
+	assert.Equal(t, []string{"report"}, embedder.texts)
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// postCompressed sends body with Content-Encoding: gzip to an echo handler behind DecompressBody
+func postCompressed(maxBytes int64, body []byte) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/echo", handlers.DecompressBody(maxBytes), func(c *gin.Context) {
+		raw, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "text/plain", raw)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/echo", bytes.NewReader(body))
+	req.Header.Set("Content-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestDecompressBody verifies a gzip body reaches the handler inflated
+func TestDecompressBody(t *testing.T) {
+	payload, _ := json.Marshal(map[string]string{"filename": "doc.txt", "content": "compressed content"})
+
+	w := postCompressed(1<<20, gzipBytes(t, payload))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, payload, w.Body.Bytes())
+}
+
+// TestDecompressBodyRejects covers zip bombs and corrupt streams
+func TestDecompressBodyRejects(t *testing.T) {
+	t.Run("ZipBomb", func(t *testing.T) {
+		// 8 MiB of zeros compresses to a few KiB
+		bomb := gzipBytes(t, make([]byte, 8<<20))
+		require.Less(t, len(bomb), 64<<10)
+
+		w := postCompressed(1<<20, bomb)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("CorruptHeader", func(t *testing.T) {
+		w := postCompressed(1<<20, []byte("not gzip at all"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("TruncatedStream", func(t *testing.T) {
+		full := gzipBytes(t, bytes.Repeat([]byte("abc"), 1000))
+		w := postCompressed(1<<20, full[:len(full)-8])
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestDecompressBodyPassthrough verifies uncompressed bodies are untouched
+func TestDecompressBodyPassthrough(t *testing.T) {
+	router := setupHandlersTestRouter()
+	router.POST("/echo", handlers.DecompressBody(4), func(c *gin.Context) {
+		raw, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "text/plain", raw)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/echo", bytes.NewBufferString("plain body over the cap"))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "plain body over the cap", w.Body.String())
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// TestAdvancedSearchDedup verifies identical-content files collapse to the closest one
+func TestAdvancedSearchDedup(t *testing.T) {
+	cfg := config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	corpus := []searchableFile{
+		{"original.txt", []float32{1, 0}, created, false},
+		{"accidental-copy.txt", []float32{0.9, 0.1}, created, false},
+		{"other.txt", []float32{0, 1}, created, false},
+	}
+	shared := sha256Hex("same content")
+	hashes := map[string]string{
+		"original.txt":        shared,
+		"accidental-copy.txt": shared,
+		"other.txt":           sha256Hex("other content"),
+	}
+	request := models.AdvancedSearchRequest{Embedding: []float32{1, 0}, TopK: 2}
+
+	t.Run("Off", func(t *testing.T) {
+		code, results := postAdvancedSearch(t, newHashedSearchStore(corpus, hashes), cfg, request)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"original.txt", "accidental-copy.txt"}, filenames(results))
+	})
+
+	t.Run("On", func(t *testing.T) {
+		code, results := postAdvancedSearchQuery(t, newHashedSearchStore(corpus, hashes), cfg, "?dedup=true", request)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"original.txt", "other.txt"}, filenames(results),
+			"the copy is dropped and top_k is still filled")
+		assert.Equal(t, shared, results[0].ContentHash)
+	})
+}
+
+// TestFilenameSearchDedup verifies dedup on filename search, including rows stored before content hashes
+func TestFilenameSearchDedup(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("GetFilesByFilename", func(args ...any) ([][]any, error) {
+		return [][]any{
+			fileRow(db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "report.txt", Content: "same", ContentHash: pgtype.Text{String: sha256Hex("same"), Valid: true}}),
+			fileRow(db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "report-copy.txt", Content: "same"}),
+			fileRow(db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "report-v2.txt", Content: "different"}),
+		}, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.GET("/files/search", handlers.GetFilesByFilenameHandler(fake.queries()))
+
+	for query, want := range map[string][]string{
+		"?query=report":            {"report.txt", "report-copy.txt", "report-v2.txt"},
+		"?query=report&dedup=true": {"report.txt", "report-v2.txt"},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/files/search"+query, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var files []struct {
+			Filename string `json:"Filename"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &files))
+		var got []string
+		for _, f := range files {
+			got = append(got, f.Filename)
+		}
+		assert.Equal(t, want, got, query)
+	}
+}
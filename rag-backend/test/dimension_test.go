@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// TestCheckEmbeddingDim covers the startup comparison between configured and declared dimensions
+func TestCheckEmbeddingDim(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configured int
+		declared   int
+		wantErr    bool
+	}{
+		{"Unconfigured skips the check", 0, 384, false},
+		{"Unconfigured ignores dimensionless column", 0, -1, false},
+		{"Matching dimension", 384, 384, false},
+		{"Mismatched dimension", 1536, 384, true},
+		{"Dimensionless column is declared at startup", 384, -1, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := db.CheckEmbeddingDim(tc.configured, tc.declared)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestVerifyEmbeddingDim verifies the startup check reads the declared dimension from the database
+func TestVerifyEmbeddingDim(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("SELECT atttypmod FROM pg_attribute", func(args ...any) ([][]any, error) {
+		return [][]any{{int32(384)}}, nil
+	})
+
+	assert.NoError(t, fake.queries().VerifyEmbeddingDim(context.Background(), 384))
+	assert.ErrorContains(t, fake.queries().VerifyEmbeddingDim(context.Background(), 768), "vector(384)")
+	assert.NoError(t, fake.queries().VerifyEmbeddingDim(context.Background(), 0))
+	assert.Equal(t, 2, fake.called("SELECT atttypmod FROM pg_attribute"))
+}
+
+// TestEnsureEmbeddingDim verifies a dimensionless column is declared from the
+// configuration and a declared one is only checked
+func TestEnsureEmbeddingDim(t *testing.T) {
+	const alter768 = "ALTER TABLE files ALTER COLUMN embedding TYPE vector(768)"
+	newStore := func(declared int32) *fakeDB {
+		fake := newFakeDB()
+		fake.on("SELECT atttypmod FROM pg_attribute", func(args ...any) ([][]any, error) {
+			return [][]any{{declared}}, nil
+		})
+		fake.on(alter768, func(args ...any) ([][]any, error) { return nil, nil })
+		return fake
+	}
+
+	t.Run("DeclaresDimensionlessColumn", func(t *testing.T) {
+		fake := newStore(-1)
+		require.NoError(t, fake.queries().EnsureEmbeddingDim(context.Background(), 768))
+		assert.Equal(t, 1, fake.called(alter768))
+	})
+
+	t.Run("DimensionlessWithoutConfig", func(t *testing.T) {
+		fake := newStore(-1)
+		assert.ErrorContains(t, fake.queries().EnsureEmbeddingDim(context.Background(), 0), "EXPECTED_EMBEDDING_DIM")
+		assert.Zero(t, fake.called(alter768))
+	})
+
+	t.Run("DeclaredMismatchFailsFast", func(t *testing.T) {
+		fake := newStore(384)
+		assert.ErrorContains(t, fake.queries().EnsureEmbeddingDim(context.Background(), 768), "vector(384)")
+		assert.Zero(t, fake.called(alter768))
+	})
+
+	t.Run("DeclaredMatchIsLeftAlone", func(t *testing.T) {
+		fake := newStore(768)
+		require.NoError(t, fake.queries().EnsureEmbeddingDim(context.Background(), 768))
+		assert.Zero(t, fake.called(alter768))
+	})
+}
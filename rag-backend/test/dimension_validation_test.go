@@ -0,0 +1,110 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// newWriteStore answers CreateFile and UpdateFile by echoing the written row.
+// It holds no existing content, so uploads are never treated as duplicates,
+// and every update snapshots as version 1.
+func newWriteStore() *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetFileByContentHash", func(args ...any) ([][]any, error) { return nil, nil })
+	fake.on("LockContentHash", func(args ...any) ([][]any, error) { return nil, nil })
+	fake.on("CreateFileVersion", func(args ...any) ([][]any, error) { return [][]any{{int32(1)}}, nil })
+	fake.on("CreateFile", func(args ...any) ([][]any, error) {
+		return [][]any{fileRow(db.File{
+			ID:        pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			Filename:  args[0].(string),
+			Content:   args[1].(string),
+			Embedding: args[2].(pgvector.Vector),
+		})}, nil
+	})
+	fake.on("UpdateFile", func(args ...any) ([][]any, error) {
+		return [][]any{fileRow(db.File{
+			ID:        args[0].(pgtype.UUID),
+			Filename:  args[1].(string),
+			Content:   args[2].(string),
+			Embedding: args[3].(pgvector.Vector),
+		})}, nil
+	})
+	return fake
+}
+
+func sendFile(fake *fakeDB, cfg config.EmbeddingConfig, method string, embedding []float32) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/upload", handlers.UploadHandler(fake.queries(), cfg, nil))
+	router.PUT("/files/:id", handlers.UpdateHandler(fake.queries(), cfg, 0))
+
+	path := "/files/upload"
+	if method == "PUT" {
+		path = "/files/" + uuid.New().String()
+	}
+	body, _ := json.Marshal(models.FileUploadRequest{Filename: "doc.txt", Content: "text", Embedding: embedding})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestEmbeddingDimensionValidation verifies upload and update reject embeddings of the wrong length
+func TestEmbeddingDimensionValidation(t *testing.T) {
+	for method, accepted := range map[string]int{"POST": http.StatusCreated, "PUT": http.StatusOK} {
+		t.Run(method, func(t *testing.T) {
+			enforced := config.EmbeddingConfig{ExpectedDim: 3}
+
+			fake := newWriteStore()
+			w := sendFile(fake, enforced, method, []float32{1, 2})
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "embedding dimension mismatch", resp["error"])
+			assert.Zero(t, fake.called("CreateFile")+fake.called("UpdateFile"))
+
+			assert.Equal(t, accepted, sendFile(newWriteStore(), enforced, method, []float32{1, 2, 3}).Code)
+
+			// Unset EXPECTED_EMBEDDING_DIM keeps accepting any length.
+			assert.Equal(t, accepted, sendFile(newWriteStore(), config.EmbeddingConfig{}, method, []float32{1, 2}).Code)
+		})
+	}
+}
+
+// TestSyncDimensionValidation verifies sync fails only the items with the wrong dimension
+func TestSyncDimensionValidation(t *testing.T) {
+	fake, _, _ := newFileStore()
+	router := setupHandlersTestRouter()
+	router.POST("/files/sync", handlers.SyncHandler(fake.queries(), config.EmbeddingConfig{ExpectedDim: 2}, 0))
+
+	body, _ := json.Marshal(models.FileSyncRequest{Files: []models.FileUploadRequest{
+		{Filename: "ok.txt", Content: "a", Embedding: []float32{1, 0}},
+		{Filename: "bad.txt", Content: "b", Embedding: []float32{1, 0, 0}},
+	}})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/sync", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var results []models.FileSyncResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "created", results[0].Action)
+	assert.Equal(t, "failed", results[1].Action)
+	assert.Equal(t, "embedding dimension mismatch", results[1].Error)
+}
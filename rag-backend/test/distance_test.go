@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// newEmbeddingStore wires GetEmbeddingsByIDs to answer from a fixed id->vector map
+func newEmbeddingStore(vectors map[uuid.UUID][]float32) *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetEmbeddingsByIDs", func(args ...any) ([][]any, error) {
+		var rows [][]any
+		for _, id := range args[0].([]pgtype.UUID) {
+			if vec, ok := vectors[id.Bytes]; ok {
+				rows = append(rows, []any{id, pgvector.NewVector(vec)})
+			}
+		}
+		return rows, nil
+	})
+	return fake
+}
+
+func postDistanceMatrix(fake *fakeDB, req models.DistanceMatrixRequest) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/distance-matrix", handlers.DistanceMatrixHandler(fake.queries(), config.EmbeddingConfig{DefaultMetric: vector.MetricL2}))
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/files/distance-matrix", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	return w
+}
+
+// TestDistanceMatrixHandler computes a small matrix and checks symmetry and known distances
+func TestDistanceMatrixHandler(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	fake := newEmbeddingStore(map[uuid.UUID][]float32{
+		a: {0, 0},
+		b: {3, 4},
+		c: {0, 1},
+	})
+
+	w := postDistanceMatrix(fake, models.DistanceMatrixRequest{IDs: []string{a.String(), b.String(), c.String()}})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.DistanceMatrixResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "l2", response.Metric)
+	require.Len(t, response.Matrix, 3)
+
+	assert.InDelta(t, 0, response.Matrix[0][0], 1e-9)
+	assert.InDelta(t, 5, response.Matrix[0][1], 1e-9)
+	assert.InDelta(t, 1, response.Matrix[0][2], 1e-9)
+	assert.InDelta(t, math.Sqrt(18), response.Matrix[1][2], 1e-9)
+	for i := range response.Matrix {
+		for j := range response.Matrix {
+			assert.Equal(t, response.Matrix[i][j], response.Matrix[j][i])
+		}
+	}
+	assert.Equal(t, 1, fake.called("GetEmbeddingsByIDs"))
+}
+
+// TestDistanceMatrixHandlerErrors covers the rejection paths
+func TestDistanceMatrixHandlerErrors(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	fake := newEmbeddingStore(map[uuid.UUID][]float32{
+		a: {1, 0},
+		b: {1, 0, 0},
+	})
+
+	t.Run("MismatchedDimensions", func(t *testing.T) {
+		w := postDistanceMatrix(fake, models.DistanceMatrixRequest{IDs: []string{a.String(), b.String()}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("TooManyIDs", func(t *testing.T) {
+		ids := make([]string, 101)
+		for i := range ids {
+			ids[i] = uuid.New().String()
+		}
+		w := postDistanceMatrix(fake, models.DistanceMatrixRequest{IDs: ids})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UnsupportedMetric", func(t *testing.T) {
+		w := postDistanceMatrix(fake, models.DistanceMatrixRequest{IDs: []string{a.String()}, Metric: "manhattan"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		unknown := uuid.New().String()
+		w := postDistanceMatrix(fake, models.DistanceMatrixRequest{IDs: []string{a.String(), unknown}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, []interface{}{unknown}, response["missing"])
+	})
+}
+
+// TestVectorDistanceMetrics checks each metric against hand-computed pgvector values
+func TestVectorDistanceMetrics(t *testing.T) {
+	x := []float32{1, 2}
+	y := []float32{3, 4}
+
+	l2, err := vector.Distance(vector.MetricL2, x, y)
+	require.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(8), l2, 1e-9)
+
+	cosine, err := vector.Distance(vector.MetricCosine, x, y)
+	require.NoError(t, err)
+	assert.InDelta(t, 1-11/(math.Sqrt(5)*5), cosine, 1e-9)
+
+	inner, err := vector.Distance(vector.MetricInner, x, y)
+	require.NoError(t, err)
+	assert.InDelta(t, -11, inner, 1e-9)
+
+	_, err = vector.Distance(vector.MetricCosine, []float32{0, 0}, y)
+	assert.ErrorIs(t, err, vector.ErrZeroVector)
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/embedding"
+)
+
+// stubEmbedder returns a fixed vector and records what it was asked to embed
+type stubEmbedder struct {
+	vec   []float32
+	err   error
+	texts []string
+}
+
+func (s *stubEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	s.texts = append(s.texts, text)
+	return s.vec, s.err
+}
+
+func uploadWith(fake *fakeDB, embedder embedding.Embedder, req models.FileUploadRequest) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/upload", handlers.UploadHandler(fake.queries(), config.EmbeddingConfig{ExpectedDim: 3}, embedder))
+
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/files/upload", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	return w
+}
+
+// TestUploadHandlerAutoEmbed verifies content without an embedding is embedded server-side
+func TestUploadHandlerAutoEmbed(t *testing.T) {
+	stub := &stubEmbedder{vec: []float32{0.1, 0.2, 0.3}}
+	fake := newWriteStore()
+
+	w := uploadWith(fake, stub, models.FileUploadRequest{Filename: "doc.txt", Content: "embed me"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, []string{"embed me"}, stub.texts)
+
+	var file struct {
+		Embedding []float32 `json:"Embedding"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &file))
+	assert.Equal(t, stub.vec, file.Embedding)
+
+	t.Run("ClientEmbeddingWins", func(t *testing.T) {
+		stub.texts = nil
+		w := uploadWith(fake, stub, models.FileUploadRequest{Filename: "doc.txt", Content: "text", Embedding: []float32{1, 2, 3}})
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, stub.texts)
+	})
+
+	t.Run("ProviderFailure", func(t *testing.T) {
+		failing := &stubEmbedder{err: errors.New("provider down")}
+		w := uploadWith(fake, failing, models.FileUploadRequest{Filename: "doc.txt", Content: "text"})
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+
+	t.Run("WrongDimension", func(t *testing.T) {
+		short := &stubEmbedder{vec: []float32{0.1}}
+		w := uploadWith(fake, short, models.FileUploadRequest{Filename: "doc.txt", Content: "text"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestOpenAIEmbedder verifies the request sent to the embeddings API and the parsed vector
+func TestOpenAIEmbedder(t *testing.T) {
+	var got map[string]any
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"data": [{"embedding": [0.5, -0.5]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := embedding.NewOpenAI("sk-test", "", 2)
+	embedder.BaseURL = server.URL
+
+	vec, err := embedder.Embed(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.5, -0.5}, vec)
+	assert.Equal(t, "Bearer sk-test", auth)
+	assert.Equal(t, "hello", got["input"])
+	assert.Equal(t, embedding.DefaultOpenAIModel, got["model"])
+	assert.Equal(t, float64(2), got["dimensions"])
+
+	t.Run("APIError", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"message": "invalid api key"}}`))
+		}))
+		defer failing.Close()
+
+		embedder.BaseURL = failing.URL
+		_, err := embedder.Embed(context.Background(), "hello")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid api key")
+	})
+}
+
+// TestEmbedderFromConfig verifies auto-embed stays off without an API key
+func TestEmbedderFromConfig(t *testing.T) {
+	assert.Nil(t, embedding.FromConfig(config.EmbeddingConfig{}))
+
+	openai, ok := embedding.FromConfig(config.EmbeddingConfig{OpenAIAPIKey: "sk", Model: "custom"}).(*embedding.OpenAI)
+	require.True(t, ok)
+	assert.Equal(t, "custom", openai.Model)
+
+	ollama, ok := embedding.FromConfig(config.EmbeddingConfig{Provider: "ollama", OpenAIAPIKey: "sk"}).(*embedding.OllamaEmbedder)
+	require.True(t, ok, "an explicit ollama provider wins over an OpenAI key")
+	assert.Equal(t, embedding.DefaultOllamaURL, ollama.URL)
+	assert.NotZero(t, ollama.Client.Timeout)
+}
+
+// TestOllamaEmbedder verifies the request body sent to Ollama and the parsed vector
+func TestOllamaEmbedder(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"embedding": [0.25, 0.75, 1]}`))
+	}))
+	defer server.Close()
+
+	vec, err := embedding.NewOllama(server.URL, "").Embed(context.Background(), "local text")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.25, 0.75, 1}, vec)
+	assert.Equal(t, map[string]string{"model": embedding.DefaultOllamaModel, "prompt": "local text"}, got)
+}
+
+// TestOllamaUnavailable verifies a connection failure surfaces as 502 from upload
+func TestOllamaUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	fake := newWriteStore()
+	w := uploadWith(fake, embedding.NewOllama(url, "all-minilm"), models.FileUploadRequest{Filename: "doc.txt", Content: "text"})
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, 0, fake.called("CreateFile"))
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/vector"
+)
+
+func getEmbeddingStats(fake *fakeDB, id string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.GET("/files/:id/embedding/stats", handlers.EmbeddingStatsHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/files/"+id+"/embedding/stats", nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestSummarize checks each statistic for a known vector
+func TestSummarize(t *testing.T) {
+	stats := vector.Summarize([]float32{3, 0, -4, 0})
+
+	assert.Equal(t, 4, stats.Dimension)
+	assert.InDelta(t, 5.0, stats.Norm, 1e-9)
+	assert.Equal(t, -4.0, stats.Min)
+	assert.Equal(t, 3.0, stats.Max)
+	assert.InDelta(t, -0.25, stats.Mean, 1e-9)
+	assert.Equal(t, 2, stats.Zeros)
+
+	assert.Equal(t, vector.Stats{}, vector.Summarize(nil))
+}
+
+// TestEmbeddingStatsHandler verifies the endpoint reports the norm of a stored vector without returning it
+func TestEmbeddingStatsHandler(t *testing.T) {
+	id := uuid.New()
+	fake := newFakeDB()
+	fake.on("GetFileEmbedding", func(args ...any) ([][]any, error) {
+		return [][]any{{pgvector.NewVector([]float32{0.6, 0.8, 0})}}, nil
+	})
+
+	w := getEmbeddingStats(fake, id.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "embedding")
+
+	var stats models.EmbeddingStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, id.String(), stats.ID)
+	assert.Equal(t, 3, stats.Dimension)
+	assert.InDelta(t, 1.0, stats.Norm, 1e-6)
+	assert.Equal(t, 1, stats.Zeros)
+}
+
+// TestEmbeddingStatsHandlerErrors covers invalid and missing files
+func TestEmbeddingStatsHandlerErrors(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("GetFileEmbedding", func(args ...any) ([][]any, error) { return nil, nil })
+
+	assert.Equal(t, http.StatusNotFound, getEmbeddingStats(fake, uuid.New().String()).Code)
+	assert.Equal(t, http.StatusBadRequest, getEmbeddingStats(fake, "nope").Code)
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+)
+
+// TestEmbeddingTruncatedIsStored checks the flag a client sends on upload and
+// update reaches the write query, so trimmed embeddings stay identifiable.
+func TestEmbeddingTruncatedIsStored(t *testing.T) {
+	for _, tc := range []struct {
+		method, query string
+		flagArg       int
+		code          int
+	}{
+		{"POST", "CreateFile", 5, http.StatusCreated},
+		{"PUT", "UpdateFile", 6, http.StatusOK},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			fake := newWriteStore()
+			var flag any
+			next := fake.handlers[tc.query]
+			fake.on(tc.query, func(args ...any) ([][]any, error) {
+				flag = args[tc.flagArg]
+				return next(args...)
+			})
+
+			router := setupHandlersTestRouter()
+			router.POST("/files/upload", handlers.UploadHandler(fake.queries(), config.EmbeddingConfig{}, nil))
+			router.PUT("/files/:id", handlers.UpdateHandler(fake.queries(), config.EmbeddingConfig{}, 0))
+
+			path := "/files/upload"
+			if tc.method == "PUT" {
+				path = "/files/" + uuid.NewString()
+			}
+			body, _ := json.Marshal(models.FileUploadRequest{
+				Filename: "long.txt", Content: "trimmed before embedding", Embedding: []float32{0.1}, EmbeddingTruncated: true,
+			})
+			req, _ := http.NewRequest(tc.method, path, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tc.code, w.Code, w.Body.String())
+			assert.Equal(t, true, flag)
+		})
+	}
+}
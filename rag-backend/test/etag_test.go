@@ -0,0 +1,118 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/db"
+)
+
+func conditionalGet(router http.Handler, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetHandlerETag(t *testing.T) {
+	id := uuid.New()
+	updated := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	stored := db.File{
+		ID:          pgtype.UUID{Bytes: id, Valid: true},
+		Filename:    "doc.txt",
+		Content:     "text",
+		ContentHash: pgtype.Text{String: "abc", Valid: true},
+		UpdatedAt:   pgtype.Timestamptz{Time: updated, Valid: true},
+	}
+	fake := newFakeDB()
+	fake.on("GetFile", func(args ...any) ([][]any, error) {
+		return [][]any{fileRow(stored)}, nil
+	})
+	router := setupHandlersTestRouter()
+	router.GET("/files/:id", handlers.GetHandler(fake.queries()))
+	path := "/files/" + id.String()
+
+	first := conditionalGet(router, path, "")
+	require.Equal(t, http.StatusOK, first.Code)
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag)
+
+	t.Run("Unchanged", func(t *testing.T) {
+		for _, header := range []string{tag, `"other", ` + tag, "*"} {
+			w := conditionalGet(router, path, header)
+			assert.Equal(t, http.StatusNotModified, w.Code, header)
+			assert.Empty(t, w.Body.String())
+			assert.Equal(t, tag, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("StaleTag", func(t *testing.T) {
+		w := conditionalGet(router, path, `W/"stale"`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Body.String())
+	})
+
+	t.Run("ChangesWithFile", func(t *testing.T) {
+		changes := map[string]func(*db.File){
+			"Updated":     func(f *db.File) { f.UpdatedAt.Time = updated.Add(time.Second) },
+			"SoftDeleted": func(f *db.File) { f.DeletedAt = pgtype.Timestamptz{Time: updated, Valid: true} },
+			"Reviewed":    func(f *db.File) { f.ReviewedAt = pgtype.Timestamptz{Time: updated, Valid: true} },
+			"Rehashed":    func(f *db.File) { f.ContentHash.String = "def" },
+		}
+		original := stored
+		for name, change := range changes {
+			stored = original
+			change(&stored)
+			w := conditionalGet(router, path, tag)
+			assert.Equal(t, http.StatusOK, w.Code, name)
+			assert.NotEqual(t, tag, w.Header().Get("ETag"), name)
+		}
+		stored = original
+	})
+
+	t.Run("NotFoundHasNoETag", func(t *testing.T) {
+		missing := newFakeDB()
+		missing.on("GetFile", func(args ...any) ([][]any, error) { return nil, nil })
+		r := setupHandlersTestRouter()
+		r.GET("/files/:id", handlers.GetHandler(missing.queries()))
+
+		w := conditionalGet(r, path, "*")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+}
+
+func TestGetFileMetadataHandlerETag(t *testing.T) {
+	filename := "doc.txt"
+	fake := newFakeDB()
+	fake.on("GetFileMetadata", func(args ...any) ([][]any, error) {
+		return [][]any{{pgtype.UUID{Bytes: uuid.UUID{1}, Valid: true}, filename, float64(4), pgtype.Timestamptz{Valid: true}}}, nil
+	})
+	router := setupHandlersTestRouter()
+	router.GET("/files/metadata", handlers.GetFileMetadataHandler(fake.queries()))
+
+	first := conditionalGet(router, "/files/metadata", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag)
+
+	w := conditionalGet(router, "/files/metadata", tag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	filename = "renamed.txt"
+	w = conditionalGet(router, "/files/metadata", tag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, tag, w.Header().Get("ETag"))
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// newExistsStore answers FindExistingFiles by matching filename or hash against the given files
+func newExistsStore(files []db.File) *fakeDB {
+	fake := newFakeDB()
+	fake.on("FindExistingFiles", func(args ...any) ([][]any, error) {
+		filenames, hashes := args[0].([]string), args[1].([]string)
+		var rows [][]any
+		for _, f := range files {
+			if slices.Contains(filenames, f.Filename) || (f.ContentHash.Valid && slices.Contains(hashes, f.ContentHash.String)) {
+				rows = append(rows, []any{f.ID, f.Filename, f.ContentHash})
+			}
+		}
+		return rows, nil
+	})
+	return fake
+}
+
+func postExists(t *testing.T, fake *fakeDB, body any) (int, models.ExistsBatchResponse) {
+	router := setupHandlersTestRouter()
+	router.POST("/files/exists/batch", handlers.ExistsBatchHandler(fake.queries()))
+
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/exists/batch", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var resp models.ExistsBatchResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+// TestExistsBatchMixed mixes existing and new hashes and filenames in one request
+func TestExistsBatchMixed(t *testing.T) {
+	alpha := db.File{
+		ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Filename:    "alpha.txt",
+		ContentHash: pgtype.Text{String: sha256Hex("alpha"), Valid: true},
+	}
+	legacy := db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "legacy.txt"}
+	fake := newExistsStore([]db.File{alpha, legacy})
+
+	code, resp := postExists(t, fake, models.ExistsBatchRequest{
+		Hashes:    []string{strings.ToUpper(sha256Hex("alpha")), sha256Hex("new content")},
+		Filenames: []string{"legacy.txt", "new.txt", "alpha.txt"},
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	assert.Equal(t, map[string][]string{sha256Hex("alpha"): {uuid.UUID(alpha.ID.Bytes).String()}}, resp.Hashes.Existing)
+	assert.Equal(t, []string{sha256Hex("new content")}, resp.Hashes.Missing)
+
+	assert.Equal(t, map[string][]string{
+		"legacy.txt": {uuid.UUID(legacy.ID.Bytes).String()},
+		"alpha.txt":  {uuid.UUID(alpha.ID.Bytes).String()},
+	}, resp.Filenames.Existing)
+	assert.Equal(t, []string{"new.txt"}, resp.Filenames.Missing)
+	assert.Equal(t, 1, fake.called("FindExistingFiles"))
+}
+
+// TestExistsBatchValidation rejects empty, oversized, and malformed input before querying
+func TestExistsBatchValidation(t *testing.T) {
+	tooMany := make([]string, 1001)
+	for i := range tooMany {
+		tooMany[i] = "f"
+	}
+
+	for _, tc := range []struct {
+		name string
+		body any
+	}{
+		{"Empty", models.ExistsBatchRequest{}},
+		{"TooMany", models.ExistsBatchRequest{Filenames: tooMany}},
+		{"BadHash", models.ExistsBatchRequest{Hashes: []string{"not-a-hash"}}},
+		{"ShortHash", models.ExistsBatchRequest{Hashes: []string{"abcd"}}},
+		{"MalformedJSON", "nope"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newExistsStore(nil)
+			code, _ := postExists(t, fake, tc.body)
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.Zero(t, fake.called("FindExistingFiles"))
+		})
+	}
+}
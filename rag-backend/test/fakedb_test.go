@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// fakeQueryFunc answers one sqlc query. It returns the result rows in scan order;
+// an empty result for a :one query surfaces as pgx.ErrNoRows.
+type fakeQueryFunc func(args ...any) ([][]any, error)
+
+// fakeDB is a scripted db.DBTX so handlers can be exercised end to end without Postgres.
+// Queries are dispatched by their sqlc name (the "-- name: X" header of the generated SQL).
+type fakeDB struct {
+	mu       sync.Mutex
+	handlers map[string]fakeQueryFunc
+	calls    []string
+	sql      map[string]string
+
+	commits   int
+	rollbacks int
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{handlers: map[string]fakeQueryFunc{}, sql: map[string]string{}}
+}
+
+// queries returns a db.Queries backed by the fake.
+func (f *fakeDB) queries() *db.Queries {
+	return db.New(f)
+}
+
+// on registers the answer for a named query.
+func (f *fakeDB) on(name string, fn fakeQueryFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[name] = fn
+}
+
+// called returns how many times a named query ran.
+func (f *fakeDB) called(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.calls {
+		if c == name {
+			n++
+		}
+	}
+	return n
+}
+
+// lastSQL returns the statement text most recently run for a named query.
+func (f *fakeDB) lastSQL(name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sql[name]
+}
+
+func (f *fakeDB) dispatch(sql string, args []any) ([][]any, error) {
+	name := queryName(sql)
+
+	f.mu.Lock()
+	f.calls = append(f.calls, name)
+	f.sql[name] = sql
+	fn, ok := f.handlers[name]
+	f.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("fakeDB: unexpected query %q", name)
+	}
+	return fn(args...)
+}
+
+func (f *fakeDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	rows, err := f.dispatch(sql, args)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return pgconn.NewCommandTag(fmt.Sprintf("UPDATE %d", len(rows))), nil
+}
+
+func (f *fakeDB) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	rows, err := f.dispatch(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{rows: rows, idx: -1}, nil
+}
+
+func (f *fakeDB) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	rows, err := f.dispatch(sql, args)
+	if err != nil {
+		return fakeRow{err: err}
+	}
+	if len(rows) == 0 {
+		return fakeRow{err: pgx.ErrNoRows}
+	}
+	return fakeRow{values: rows[0]}
+}
+
+// Begin starts a fake transaction whose statements dispatch to the same handlers.
+func (f *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeTx{fakeDB: f}, nil
+}
+
+// txCounts returns how many fake transactions were committed and rolled back.
+func (f *fakeDB) txCounts() (commits, rollbacks int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.commits, f.rollbacks
+}
+
+// fakeTx records the outcome of a transaction on its fakeDB. Statements are not
+// staged, so tests assert on commit/rollback counts rather than on undone writes.
+type fakeTx struct {
+	*fakeDB
+	done bool
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	return t.finish(func() { t.commits++ })
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	return t.finish(func() { t.rollbacks++ })
+}
+
+func (t *fakeTx) finish(record func()) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return pgx.ErrTxClosed
+	}
+	t.done = true
+	record()
+	return nil
+}
+
+func (t *fakeTx) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, fmt.Errorf("fakeTx: CopyFrom not supported")
+}
+func (t *fakeTx) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults { return nil }
+func (t *fakeTx) LargeObjects() pgx.LargeObjects                         { return pgx.LargeObjects{} }
+func (t *fakeTx) Prepare(context.Context, string, string) (*pgconn.StatementDescription, error) {
+	return nil, fmt.Errorf("fakeTx: Prepare not supported")
+}
+func (t *fakeTx) Conn() *pgx.Conn { return nil }
+
+// queryName extracts the sqlc query name from a generated statement.
+// Hand-written statements are identified by their first line instead.
+func queryName(sql string) string {
+	header, _, _ := strings.Cut(strings.TrimSpace(sql), "\n")
+	name, ok := strings.CutPrefix(header, "-- name:")
+	if !ok {
+		return header
+	}
+	return strings.Fields(name)[0]
+}
+
+// scanInto copies scripted values into scan destinations by reflection.
+func scanInto(values []any, dest []any) error {
+	if len(values) != len(dest) {
+		return fmt.Errorf("fakeDB: scanning %d values into %d destinations", len(values), len(dest))
+	}
+	for i, d := range dest {
+		target := reflect.ValueOf(d).Elem()
+		if values[i] == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		v := reflect.ValueOf(values[i])
+		if !v.Type().ConvertibleTo(target.Type()) {
+			return fmt.Errorf("fakeDB: cannot scan %T into %s", values[i], target.Type())
+		}
+		target.Set(v.Convert(target.Type()))
+	}
+	return nil
+}
+
+type fakeRow struct {
+	values []any
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return scanInto(r.values, dest)
+}
+
+type fakeRows struct {
+	rows [][]any
+	idx  int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.NewCommandTag("SELECT") }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	return scanInto(r.rows[r.idx], dest)
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+	return r.rows[r.idx], nil
+}
+
+// fileRow flattens a db.File into the column order sqlc scans for SELECT *.
+func fileRow(f db.File) []any {
+	return []any{f.ID, f.Filename, f.Content, f.Embedding, f.CreatedAt, f.ContentHash, f.UpdatedAt, f.ReviewedAt, f.MimeType, f.DeletedAt, f.Deleted, f.Tags, f.EmbeddingTruncated, f.Summary, f.SummaryEmbedding}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+)
+
+// TestFilenameSearchCaseAndEscaping verifies the default is case-insensitive, case_sensitive opts out, and wildcards are escaped
+func TestFilenameSearchCaseAndEscaping(t *testing.T) {
+	var gotCaseSensitive bool
+	var gotQuery string
+	fake := newFakeDB()
+	fake.on("GetFilesByFilename", func(args ...any) ([][]any, error) {
+		gotCaseSensitive, gotQuery = args[0].(bool), args[1].(string)
+		return nil, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.GET("/files/search", handlers.GetFilesByFilenameHandler(fake.queries()))
+	search := func(params url.Values) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/files/search?"+params.Encode(), nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	search(url.Values{"query": {"Report"}})
+	assert.False(t, gotCaseSensitive)
+	assert.Equal(t, "Report", gotQuery)
+	sql := fake.lastSQL("GetFilesByFilename")
+	assert.Contains(t, sql, "filename ILIKE")
+	assert.Contains(t, sql, "filename LIKE")
+
+	search(url.Values{"query": {"Report"}, "case_sensitive": {"true"}})
+	assert.True(t, gotCaseSensitive)
+
+	search(url.Values{"query": {`50%_off\v2`}})
+	assert.Equal(t, `50\%\_off\\v2`, gotQuery, "LIKE wildcards and the escape character must match literally")
+}
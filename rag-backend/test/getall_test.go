@@ -0,0 +1,81 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/db"
+)
+
+func newGetAllStore(file db.File) *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetAllFiles", func(args ...any) ([][]any, error) {
+		return [][]any{fileRow(file)}, nil
+	})
+	fake.on("GetAllFileSummaries", func(args ...any) ([][]any, error) {
+		return [][]any{{file.ID, file.Filename, int32(len(file.Content)), file.CreatedAt, file.Deleted, file.MimeType}}, nil
+	})
+	return fake
+}
+
+func getAll(fake *fakeDB, query string) []map[string]any {
+	router := setupHandlersTestRouter()
+	router.GET("/files/getall", handlers.GetAllHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/files/getall"+query, nil)
+	router.ServeHTTP(w, req)
+
+	var files []map[string]any
+	if w.Code == http.StatusOK {
+		_ = json.Unmarshal(w.Body.Bytes(), &files)
+	}
+	return files
+}
+
+// TestGetAllHandlerShapes verifies getall omits content by default and returns full records on request
+func TestGetAllHandlerShapes(t *testing.T) {
+	id := uuid.New()
+	file := db.File{
+		ID:        pgtype.UUID{Bytes: id, Valid: true},
+		Filename:  "notes.txt",
+		Content:   "hello world",
+		Embedding: pgvector.NewVector([]float32{0.1, 0.2}),
+		CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		Deleted:   pgtype.Bool{Bool: true, Valid: true},
+	}
+
+	t.Run("DefaultExcludesContent", func(t *testing.T) {
+		fake := newGetAllStore(file)
+		files := getAll(fake, "")
+
+		require.Len(t, files, 1)
+		assert.Equal(t, id.String(), files[0]["id"])
+		assert.Equal(t, "notes.txt", files[0]["filename"])
+		assert.EqualValues(t, len(file.Content), files[0]["size"])
+		assert.Equal(t, true, files[0]["deleted"])
+		assert.NotContains(t, files[0], "content")
+		assert.NotContains(t, files[0], "embedding")
+		assert.Zero(t, fake.called("GetAllFiles"))
+	})
+
+	t.Run("IncludeContent", func(t *testing.T) {
+		fake := newGetAllStore(file)
+		files := getAll(fake, "?include_content=true")
+
+		require.Len(t, files, 1)
+		assert.Contains(t, files[0], "Content")
+		assert.Contains(t, files[0], "Embedding")
+		assert.Zero(t, fake.called("GetAllFileSummaries"))
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/fain17/rag-backend/api/handlers"
 	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -88,7 +89,7 @@ func TestHandlersInputValidation(t *testing.T) {
 	// The handler must validate JSON format before processing upload data
 	t.Run("UploadHandler_InvalidJSON", func(t *testing.T) {
 		router := setupHandlersTestRouter()
-		router.POST("/files", handlers.UploadHandler(nil))
+		router.POST("/files", handlers.UploadHandler(nil, config.EmbeddingConfig{}, nil))
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("POST", "/files", bytes.NewBuffer([]byte("invalid json")))
@@ -107,7 +108,7 @@ func TestHandlersInputValidation(t *testing.T) {
 	// UpdateHandler must validate UUID format before attempting update operations
 	t.Run("UpdateHandler_InvalidUUID", func(t *testing.T) {
 		router := setupHandlersTestRouter()
-		router.PUT("/files/:id", handlers.UpdateHandler(nil))
+		router.PUT("/files/:id", handlers.UpdateHandler(nil, config.EmbeddingConfig{}, 0))
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("PUT", "/files/invalid-uuid", nil)
@@ -126,7 +127,7 @@ func TestHandlersInputValidation(t *testing.T) {
 	t.Run("UpdateHandler_InvalidJSON", func(t *testing.T) {
 		router := setupHandlersTestRouter()
 		testUUID := uuid.New()
-		router.PUT("/files/:id", handlers.UpdateHandler(nil))
+		router.PUT("/files/:id", handlers.UpdateHandler(nil, config.EmbeddingConfig{}, 0))
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("PUT", "/files/"+testUUID.String(), bytes.NewBuffer([]byte("invalid json")))
@@ -489,9 +490,9 @@ func TestHandlerSpecificLogic(t *testing.T) {
 		router.GET("/files", handlers.GetAllHandler(nil))
 		router.GET("/files/search", handlers.GetFilesByFilenameHandler(nil))
 		router.GET("/files/date-range", handlers.GetFilesByDateRangeHandler(nil))
-		router.POST("/files", handlers.UploadHandler(nil))
+		router.POST("/files", handlers.UploadHandler(nil, config.EmbeddingConfig{}, nil))
 		router.DELETE("/files/:id", handlers.DeleteHandler(nil))
-		router.PUT("/files/:id", handlers.UpdateHandler(nil))
+		router.PUT("/files/:id", handlers.UpdateHandler(nil, config.EmbeddingConfig{}, 0))
 		router.PATCH("/files/:id/soft-delete", handlers.SoftDeleteHandler(nil))
 		router.PATCH("/files/:id/restore", handlers.UndoSoftDeleteHandler(nil))
 		router.GET("/files/recycle-bin", handlers.GetDeletedFilesHandler(nil))
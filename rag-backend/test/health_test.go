@@ -0,0 +1,71 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/db"
+)
+
+func probe(fake *fakeDB, path string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.GET("/healthz", handlers.HealthHandler())
+	router.GET("/readyz", handlers.ReadinessHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestHealthHandler verifies liveness never depends on the database
+func TestHealthHandler(t *testing.T) {
+	fake := newFakeDB()
+	w := probe(fake, "/healthz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, fake.called("SELECT 1"))
+}
+
+// TestReadinessHandler verifies readiness follows database reachability
+func TestReadinessHandler(t *testing.T) {
+	t.Run("Reachable", func(t *testing.T) {
+		fake := newFakeDB()
+		fake.on("SELECT 1", func(args ...any) ([][]any, error) {
+			return nil, nil
+		})
+		w := probe(fake, "/readyz")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, fake.called("SELECT 1"))
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		fake := newFakeDB()
+		fake.on("SELECT 1", func(args ...any) ([][]any, error) {
+			return nil, errors.New("connection refused")
+		})
+		w := probe(fake, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+// closableDB is a fakeDB that records pool shutdown
+type closableDB struct {
+	*fakeDB
+	closed int
+}
+
+func (c *closableDB) Close() { c.closed++ }
+
+// TestQueriesClose verifies Close releases a pool and ignores handles without one
+func TestQueriesClose(t *testing.T) {
+	pool := &closableDB{fakeDB: newFakeDB()}
+	db.New(pool).Close()
+	assert.Equal(t, 1, pool.closed)
+
+	assert.NotPanics(t, func() { newFakeDB().queries().Close() })
+}
@@ -0,0 +1,180 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+)
+
+type rankedFile struct {
+	id       uuid.UUID
+	filename string
+}
+
+// newHybridStore returns the keyword and vector rankings in the given order
+func newHybridStore(keyword, vectorRanked []rankedFile) *fakeDB {
+	created := pgtype.Timestamptz{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	fake := newFakeDB()
+	fake.on("SearchFilesKeyword", func(args ...any) ([][]any, error) {
+		var rows [][]any
+		for _, f := range keyword[:min(len(keyword), int(args[1].(int32)))] {
+			rows = append(rows, []any{pgtype.UUID{Bytes: f.id, Valid: true}, f.filename, created, true})
+		}
+		return rows, nil
+	})
+	fake.on("SearchFilesCosine", func(args ...any) ([][]any, error) {
+		var rows [][]any
+		for i, f := range vectorRanked[:min(len(vectorRanked), int(args[6].(int32)))] {
+			rows = append(rows, []any{pgtype.UUID{Bytes: f.id, Valid: true}, f.filename, created, pgtype.Text{}, 0.1 * float64(i+1)})
+		}
+		return rows, nil
+	})
+	return fake
+}
+
+func postHybridSearch(fake *fakeDB, body any) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/hybrid-search", handlers.HybridSearchHandler(fake.queries(), config.EmbeddingConfig{DefaultMetric: "cosine"}, nil, false))
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/hybrid-search", bytes.NewBuffer(raw))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestHybridSearchFusion verifies reciprocal rank fusion ordering and that vector_weight shifts it
+func TestHybridSearchFusion(t *testing.T) {
+	both := rankedFile{uuid.New(), "both.txt"}
+	keywordOnly := rankedFile{uuid.New(), "keyword.txt"}
+	vectorOnly := rankedFile{uuid.New(), "vector.txt"}
+	keyword := []rankedFile{keywordOnly, both}
+	vectorRanked := []rankedFile{both, vectorOnly}
+
+	weight := func(w float64) *float64 { return &w }
+	cases := []struct {
+		name   string
+		weight *float64
+		want   []string
+	}{
+		{"default balances both", nil, []string{"both.txt", "keyword.txt", "vector.txt"}},
+		{"vector only", weight(1), []string{"both.txt", "vector.txt", "keyword.txt"}},
+		{"keyword only", weight(0), []string{"keyword.txt", "both.txt", "vector.txt"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := postHybridSearch(newHybridStore(keyword, vectorRanked), models.HybridSearchRequest{
+				Query: "report", Embedding: []float32{1, 0}, VectorWeight: tc.weight,
+			})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var results []models.HybridSearchResult
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Filename)
+				assert.InDelta(t, r.KeywordScore+r.VectorScore, r.Score, 1e-12)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	w := postHybridSearch(newHybridStore(keyword, vectorRanked), models.HybridSearchRequest{Query: "report", Embedding: []float32{1, 0}})
+	var results []models.HybridSearchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 3)
+	top := results[0]
+	assert.Equal(t, 2, top.KeywordRank)
+	assert.Equal(t, 1, top.VectorRank)
+	assert.InDelta(t, 0.5/62, top.KeywordScore, 1e-12)
+	assert.InDelta(t, 0.5/61, top.VectorScore, 1e-12)
+	require.NotNil(t, top.Distance)
+	assert.InDelta(t, 0.1, *top.Distance, 1e-12)
+	assert.Zero(t, results[1].VectorRank, "keyword-only hits have no vector rank")
+	assert.Nil(t, results[1].Distance)
+}
+
+// TestHybridSearchNormalizeScores adds relevance to vector matches only, in vector rank order
+func TestHybridSearchNormalizeScores(t *testing.T) {
+	first := rankedFile{uuid.New(), "first.txt"}
+	second := rankedFile{uuid.New(), "second.txt"}
+	keywordOnly := rankedFile{uuid.New(), "keyword.txt"}
+	fake := newHybridStore([]rankedFile{keywordOnly}, []rankedFile{first, second})
+
+	weight := 1.0
+	router := setupHandlersTestRouter()
+	router.POST("/files/hybrid-search", handlers.HybridSearchHandler(fake.queries(), config.EmbeddingConfig{DefaultMetric: "cosine"}, nil, false))
+	raw, _ := json.Marshal(models.HybridSearchRequest{Query: "report", Embedding: []float32{1, 0}, VectorWeight: &weight})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/hybrid-search?normalize_scores=true", bytes.NewBuffer(raw))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var results []models.HybridSearchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 3)
+	require.NotNil(t, results[0].Relevance)
+	require.NotNil(t, results[1].Relevance)
+	assert.InDelta(t, 0.95, *results[0].Relevance, 1e-9)
+	assert.Greater(t, *results[0].Relevance, *results[1].Relevance)
+	assert.Nil(t, results[2].Relevance, "keyword-only hits have no distance to normalize")
+}
+
+// TestHybridSearchTopKAndEscaping verifies top_k trims fused results and LIKE wildcards match literally
+func TestHybridSearchTopKAndEscaping(t *testing.T) {
+	var files []rankedFile
+	for i := range 6 {
+		files = append(files, rankedFile{uuid.New(), string(rune('a'+i)) + ".txt"})
+	}
+	fake := newHybridStore(files, files)
+	w := postHybridSearch(fake, models.HybridSearchRequest{Query: "50%_off", Embedding: []float32{1}, TopK: 2})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var results []models.HybridSearchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	assert.Len(t, results, 2)
+	assert.Equal(t, "a.txt", results[0].Filename)
+	assert.Contains(t, fake.lastSQL("SearchFilesKeyword"), "deleted IS NOT TRUE")
+
+	var pattern string
+	fake.on("SearchFilesKeyword", func(args ...any) ([][]any, error) {
+		pattern = args[0].(string)
+		return nil, nil
+	})
+	postHybridSearch(fake, models.HybridSearchRequest{Query: "50%_off", Embedding: []float32{1}})
+	assert.Equal(t, `50\%\_off`, pattern)
+}
+
+// TestHybridSearchValidation verifies malformed requests are rejected before searching
+func TestHybridSearchValidation(t *testing.T) {
+	weight := func(w float64) *float64 { return &w }
+	cases := map[string]models.HybridSearchRequest{
+		"missing query":     {Embedding: []float32{1}},
+		"blank query":       {Query: "   ", Embedding: []float32{1}},
+		"missing embedding": {Query: "x"},
+		"bad metric":        {Query: "x", Embedding: []float32{1}, Metric: "manhattan"},
+		"top_k too large":   {Query: "x", Embedding: []float32{1}, TopK: 51},
+		"weight above one":  {Query: "x", Embedding: []float32{1}, VectorWeight: weight(1.5)},
+		"negative weight":   {Query: "x", Embedding: []float32{1}, VectorWeight: weight(-0.1)},
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := newHybridStore(nil, nil)
+			assert.Equal(t, http.StatusBadRequest, postHybridSearch(fake, body).Code)
+			assert.Zero(t, fake.called("SearchFilesKeyword")+fake.called("SearchFilesCosine"))
+		})
+	}
+}
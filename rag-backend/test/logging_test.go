@@ -0,0 +1,85 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+)
+
+func setupLoggingRouter(buf *bytes.Buffer) *gin.Engine {
+	router := setupHandlersTestRouter()
+	router.Use(handlers.RequestIDMiddleware(), handlers.RequestLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+	router.GET("/files/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"request_id": c.GetString(handlers.RequestIDKey)})
+	})
+	router.GET("/boom", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	return router
+}
+
+func sendWithRequestID(router *gin.Engine, path, requestID string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	if requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestRequestIDMiddleware verifies IDs are generated when absent, honored when supplied, and replaced when unsafe
+func TestRequestIDMiddleware(t *testing.T) {
+	router := setupLoggingRouter(&bytes.Buffer{})
+
+	generated := sendWithRequestID(router, "/files/1", "").Header().Get("X-Request-ID")
+	_, err := uuid.Parse(generated)
+	assert.NoError(t, err, "a UUID is generated when the caller sends none")
+
+	w := sendWithRequestID(router, "/files/1", "trace-abc-123")
+	assert.Equal(t, "trace-abc-123", w.Header().Get("X-Request-ID"))
+	assert.JSONEq(t, `{"request_id":"trace-abc-123"}`, w.Body.String(), "handlers see the ID in the context")
+
+	for _, unsafe := range []string{"has space", strings.Repeat("a", 129), "line\tbreak"} {
+		got := sendWithRequestID(router, "/files/1", unsafe).Header().Get("X-Request-ID")
+		assert.NotEqual(t, unsafe, got)
+		_, err := uuid.Parse(got)
+		assert.NoError(t, err, "unsafe ID %q is replaced", unsafe)
+	}
+}
+
+// TestRequestLogger verifies one JSON line per request with the correlated fields
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	router := setupLoggingRouter(&buf)
+
+	sendWithRequestID(router, "/files/42?pretty=true", "req-1")
+	sendWithRequestID(router, "/boom", "req-2")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "INFO", first["level"])
+	assert.Equal(t, "GET", first["method"])
+	assert.Equal(t, "/files/42", first["path"])
+	assert.Equal(t, float64(200), first["status"])
+	assert.Equal(t, "req-1", first["request_id"])
+	assert.Contains(t, first, "latency")
+
+	var second map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "ERROR", second["level"], "server errors log at error level")
+	assert.Equal(t, float64(500), second["status"])
+	assert.Equal(t, "req-2", second["request_id"])
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// minimalPDF is a tiny ASCII-only PDF, enough for content sniffing
+const minimalPDF = "%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n"
+
+// newMimeStore keeps created files in memory and answers the summary listing with its mime_type filter
+func newMimeStore() *fakeDB {
+	var files []db.File
+	fake := newFakeDB()
+	fake.on("GetFileByContentHash", func(args ...any) ([][]any, error) { return nil, nil })
+	fake.on("LockContentHash", func(args ...any) ([][]any, error) { return nil, nil })
+	fake.on("CreateFile", func(args ...any) ([][]any, error) {
+		f := db.File{
+			ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			Filename:    args[0].(string),
+			Content:     args[1].(string),
+			Embedding:   args[2].(pgvector.Vector),
+			ContentHash: args[3].(pgtype.Text),
+			MimeType:    args[4].(string),
+		}
+		files = append(files, f)
+		return [][]any{fileRow(f)}, nil
+	})
+	fake.on("GetAllFileSummaries", func(args ...any) ([][]any, error) {
+		filter := args[0].(pgtype.Text)
+		var rows [][]any
+		for _, f := range files {
+			if filter.Valid && f.MimeType != filter.String {
+				continue
+			}
+			rows = append(rows, []any{f.ID, f.Filename, int32(len(f.Content)), f.CreatedAt, f.Deleted, f.MimeType})
+		}
+		return rows, nil
+	})
+	return fake
+}
+
+// TestMimeTypeDetectionAndFilter uploads a PDF and a JSON text file, then filters the listing by type
+func TestMimeTypeDetectionAndFilter(t *testing.T) {
+	fake := newMimeStore()
+
+	w := postMultipart(fake, 1<<20, "paper.pdf", minimalPDF, "[0.1, 0.2]")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	router := setupHandlersTestRouter()
+	router.POST("/files/upload", handlers.UploadHandler(fake.queries(), config.EmbeddingConfig{}, nil))
+	router.GET("/files/getall", handlers.GetAllHandler(fake.queries()))
+
+	body, _ := json.Marshal(models.FileUploadRequest{Filename: "notes.txt", Content: "plain notes", Embedding: []float32{0.3, 0.4}})
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/upload", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	list := func(query string) []models.FileSummary {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/files/getall"+query, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var files []models.FileSummary
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &files))
+		return files
+	}
+
+	all := list("")
+	require.Len(t, all, 2)
+	assert.Equal(t, "application/pdf", all[0].MimeType)
+	assert.Equal(t, "text/plain", all[1].MimeType, "JSON uploads without a detectable type default to text/plain")
+
+	pdfs := list("?mime_type=application/pdf")
+	require.Len(t, pdfs, 1)
+	assert.Equal(t, "paper.pdf", pdfs[0].Filename)
+}
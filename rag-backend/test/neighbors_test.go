@@ -0,0 +1,102 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/db"
+)
+
+func getWithNeighbors(fake *fakeDB, path string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.GET("/files/:id/with-neighbors", handlers.FileWithNeighborsHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestFileWithNeighborsHandler verifies the anchor file and its neighbors come back together
+func TestFileWithNeighborsHandler(t *testing.T) {
+	anchorID := uuid.New()
+	anchor := db.File{
+		ID:        pgtype.UUID{Bytes: anchorID, Valid: true},
+		Filename:  "anchor.txt",
+		Content:   "anchor content",
+		Embedding: pgvector.NewVector([]float32{1, 0}),
+	}
+	neighborID := uuid.New()
+
+	var gotTopK int32
+	fake := newFakeDB()
+	fake.on("GetFile", func(args ...any) ([][]any, error) {
+		return [][]any{fileRow(anchor)}, nil
+	})
+	fake.on("GetNearestNeighbors", func(args ...any) ([][]any, error) {
+		assert.Equal(t, anchor.ID, args[1], "anchor must be excluded from its own neighbors")
+		gotTopK = args[2].(int32)
+		return [][]any{
+			{pgtype.UUID{Bytes: neighborID, Valid: true}, "close.txt", 0.05},
+			{pgtype.UUID{Bytes: uuid.New(), Valid: true}, "far.txt", 0.6},
+		}, nil
+	})
+
+	w := getWithNeighbors(fake, "/files/"+anchorID.String()+"/with-neighbors?top_k=2")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int32(2), gotTopK)
+
+	var response struct {
+		File struct {
+			Filename string `json:"Filename"`
+		} `json:"file"`
+		Neighbors []struct {
+			ID       string  `json:"id"`
+			Filename string  `json:"filename"`
+			Distance float64 `json:"distance"`
+		} `json:"neighbors"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "anchor.txt", response.File.Filename)
+	require.Len(t, response.Neighbors, 2)
+	assert.Equal(t, neighborID.String(), response.Neighbors[0].ID)
+	assert.Equal(t, "close.txt", response.Neighbors[0].Filename)
+	assert.Equal(t, 0.05, response.Neighbors[0].Distance)
+	assert.Contains(t, fake.lastSQL("GetNearestNeighbors"), "ORDER BY embedding <=> $1::vector, created_at, id",
+		"equidistant neighbors need a deterministic order")
+}
+
+// TestFileWithNeighborsHandlerErrors covers missing anchors and invalid parameters
+func TestFileWithNeighborsHandlerErrors(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("GetFile", func(args ...any) ([][]any, error) {
+		return nil, nil
+	})
+
+	t.Run("MissingAnchor", func(t *testing.T) {
+		w := getWithNeighbors(fake, "/files/"+uuid.New().String()+"/with-neighbors")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, 0, fake.called("GetNearestNeighbors"))
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		w := getWithNeighbors(fake, "/files/not-a-uuid/with-neighbors")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("InvalidTopK", func(t *testing.T) {
+		for _, topK := range []string{"0", "51", "abc"} {
+			w := getWithNeighbors(fake, "/files/"+uuid.New().String()+"/with-neighbors?top_k="+topK)
+			assert.Equal(t, http.StatusBadRequest, w.Code, topK)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+var errConnectionLost = errors.New("conn closed")
+
+func sendByID(fake *fakeDB, method, id string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.GET("/files/:id", handlers.GetHandler(fake.queries()))
+	router.PUT("/files/:id", handlers.UpdateHandler(fake.queries(), config.EmbeddingConfig{}, 0))
+	router.DELETE("/files/:id", handlers.DeleteHandler(fake.queries()))
+
+	var body bytes.Buffer
+	if method == "PUT" {
+		json.NewEncoder(&body).Encode(models.FileUploadRequest{Filename: "doc.txt", Content: "text", Embedding: []float32{1}})
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/files/"+id, &body)
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestMissingVersusBrokenFile verifies a missing row is 404 while any other database error is 500
+func TestMissingVersusBrokenFile(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		query  string
+		method string
+	}{
+		{"Get", "GetFile", "GET"},
+		// Update snapshots the current row first, so that lookup decides 404 versus 500.
+		{"Update", "CreateFileVersion", "PUT"},
+		{"Delete", "DeleteFile", "DELETE"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, outcome := range []struct {
+				err  error
+				want int
+			}{
+				{nil, http.StatusNotFound},
+				{errConnectionLost, http.StatusInternalServerError},
+			} {
+				fake := newFakeDB()
+				fake.on(tc.query, func(args ...any) ([][]any, error) {
+					return nil, outcome.err
+				})
+
+				w := sendByID(fake, tc.method, uuid.New().String())
+				assert.Equal(t, outcome.want, w.Code, "error %v", outcome.err)
+				assert.Equal(t, 1, fake.called(tc.query))
+			}
+		})
+	}
+}
+
+// TestDeleteHandlerRemovesFile verifies an existing file still deletes with 204
+func TestDeleteHandlerRemovesFile(t *testing.T) {
+	id := uuid.New()
+	fake := newFakeDB()
+	fake.on("DeleteFile", func(args ...any) ([][]any, error) {
+		if args[0].(pgtype.UUID) != (pgtype.UUID{Bytes: id, Valid: true}) {
+			return nil, nil
+		}
+		return [][]any{fileRow(db.File{})}, nil
+	})
+
+	w := sendByID(fake, "DELETE", id.String())
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
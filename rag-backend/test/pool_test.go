@@ -0,0 +1,61 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// TestConfigLoadPool verifies the pool settings are read from the environment and validated
+func TestConfigLoadPool(t *testing.T) {
+	t.Setenv("DB_MAX_CONNS", "")
+	t.Setenv("DB_MIN_CONNS", "")
+	t.Setenv("DB_MAX_CONN_LIFETIME", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, db.PoolConfig{}, cfg.Pool, "unset variables keep pgxpool defaults")
+
+	t.Setenv("DB_MAX_CONNS", "20")
+	t.Setenv("DB_MIN_CONNS", "2")
+	t.Setenv("DB_MAX_CONN_LIFETIME", "30m")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, db.PoolConfig{MaxConns: 20, MinConns: 2, MaxConnLifetime: 30 * time.Minute}, cfg.Pool)
+
+	invalid := map[string][2]string{
+		"negative max":    {"DB_MAX_CONNS", "-1"},
+		"min above max":   {"DB_MIN_CONNS", "21"},
+		"bad lifetime":    {"DB_MAX_CONN_LIFETIME", "forever"},
+		"negative expiry": {"DB_MAX_CONN_LIFETIME", "-1m"},
+		"not a number":    {"DB_MAX_CONNS", "many"},
+	}
+	for name, kv := range invalid {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(kv[0], kv[1])
+			_, err := config.Load()
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestPoolConfigApply verifies only the configured settings override the parsed pool config
+func TestPoolConfigApply(t *testing.T) {
+	cfg, err := pgxpool.ParseConfig("postgres://user@localhost:5432/db")
+	require.NoError(t, err)
+	defaultMax, defaultLifetime := cfg.MaxConns, cfg.MaxConnLifetime
+
+	db.PoolConfig{MinConns: 3}.Apply(cfg)
+	assert.Equal(t, int32(3), cfg.MinConns)
+	assert.Equal(t, defaultMax, cfg.MaxConns)
+	assert.Equal(t, defaultLifetime, cfg.MaxConnLifetime)
+
+	db.PoolConfig{MaxConns: 50, MaxConnLifetime: time.Minute}.Apply(cfg)
+	assert.Equal(t, int32(50), cfg.MaxConns)
+	assert.Equal(t, time.Minute, cfg.MaxConnLifetime)
+}
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/config"
+)
+
+// TestPrettyJSON verifies responses are compact by default and indented on request
+func TestPrettyJSON(t *testing.T) {
+	router := setupHandlersTestRouter()
+	router.GET("/config/embeddings", handlers.EmbeddingConfigHandler(config.EmbeddingConfig{DefaultMetric: "cosine"}))
+
+	get := func(path string, header string) string {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		if header != "" {
+			req.Header.Set("X-Pretty-JSON", header)
+		}
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	compact := get("/config/embeddings", "")
+	assert.NotContains(t, compact, "\n")
+
+	pretty := get("/config/embeddings?pretty=true", "")
+	assert.True(t, strings.HasPrefix(pretty, "{\n    \""), "expected indented output, got %q", pretty)
+	assert.Contains(t, pretty, `"default_metric": "cosine"`)
+
+	assert.Equal(t, pretty, get("/config/embeddings", "true"), "the header is equivalent to the query parameter")
+}
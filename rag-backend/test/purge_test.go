@@ -0,0 +1,56 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+// TestPurgeExpired verifies the cutoff is ttl before now and the purged count is returned
+func TestPurgeExpired(t *testing.T) {
+	var cutoff time.Time
+	fake := newFakeDB()
+	fake.on("PurgeDeletedBefore", func(args ...any) ([][]any, error) {
+		cutoff = args[0].(pgtype.Timestamptz).Time
+		return [][]any{{}, {}}, nil
+	})
+
+	n, err := fake.queries().PurgeExpired(context.Background(), 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), cutoff, time.Minute)
+}
+
+// TestPurgeWorkerStopsOnCancel verifies the worker purges on start and returns once its context is cancelled
+func TestPurgeWorkerStopsOnCancel(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("PurgeDeletedBefore", func(args ...any) ([][]any, error) { return nil, nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		db.StartPurgeWorker(ctx, fake.queries(), time.Hour, time.Hour)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return fake.called("PurgeDeletedBefore") == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge worker did not stop after cancel")
+	}
+}
+
+// TestPurgeWorkerDisabled verifies a zero TTL returns without touching the database
+func TestPurgeWorkerDisabled(t *testing.T) {
+	fake := newFakeDB()
+	db.StartPurgeWorker(context.Background(), fake.queries(), 0, time.Hour)
+	assert.Zero(t, fake.called("PurgeDeletedBefore"))
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// toHalf rounds f to the nearest float16 value, as a cast to halfvec does.
+// Test vectors stay within float16's normal range.
+func toHalf(f float32) float32 {
+	const drop = 13 // float32 keeps 23 mantissa bits, float16 keeps 10
+	bits := math.Float32bits(f)
+	bits += 1<<(drop-1) - 1 + (bits>>drop)&1 // round to nearest, ties to even
+	return math.Float32frombits(bits &^ (1<<drop - 1))
+}
+
+func halfVector(v []float32) []float32 {
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = toHalf(x)
+	}
+	return out
+}
+
+var quantizedOverfetchPattern = regexp.MustCompile(`LIMIT \$7 \* (\d+)`)
+
+// withQuantizedSearch answers SearchFilesQuantized like Postgres with a halfvec
+// index would: candidates ranked by half-precision distance, then re-ranked by
+// full-precision distance. The unfiltered corpus is enough for these tests.
+func withQuantizedSearch(fake *fakeDB, metric string, files []searchableFile) *fakeDB {
+	fake.on("SearchFilesQuantized", func(args ...any) ([][]any, error) {
+		query := args[0].(pgvector.Vector).Slice()
+		limit := int(args[6].(int32))
+		overfetch, _ := strconv.Atoi(quantizedOverfetchPattern.FindStringSubmatch(fake.lastSQL("SearchFilesQuantized"))[1])
+
+		type hit struct {
+			file           searchableFile
+			half, distance float64
+		}
+		hits := make([]hit, len(files))
+		for i, f := range files {
+			half, err := vector.Distance(metric, halfVector(query), halfVector(f.embedding))
+			if err != nil {
+				return nil, err
+			}
+			full, _ := vector.Distance(metric, query, f.embedding)
+			hits[i] = hit{f, half, full}
+		}
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].half < hits[j].half })
+		hits = hits[:min(len(hits), limit*overfetch)]
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].distance < hits[j].distance })
+		hits = hits[:min(len(hits), limit)]
+
+		rows := make([][]any, len(hits))
+		for i, h := range hits {
+			rows[i] = []any{pgtype.UUID{Bytes: uuid.New(), Valid: true}, h.file.filename, pgtype.Timestamptz{Time: h.file.createdAt, Valid: true}, pgtype.Text{}, h.distance}
+		}
+		return rows, nil
+	})
+	return fake
+}
+
+// TestQuantizedSearchRecall checks searches served by a halfvec index return the
+// same top results, in the same order, as full precision on a small corpus
+func TestQuantizedSearchRecall(t *testing.T) {
+	const dim, topK = 16, 5
+	rng := rand.New(rand.NewSource(42))
+	randomVector := func() []float32 {
+		v := make([]float32, dim)
+		for i := range v {
+			v[i] = rng.Float32()*2 - 1
+		}
+		return v
+	}
+
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var corpus []searchableFile
+	for i := range 60 {
+		corpus = append(corpus, searchableFile{"doc-" + strconv.Itoa(i) + ".txt", randomVector(), created.Add(time.Duration(i) * time.Minute), false})
+	}
+	// Two files closer together than float16 can tell apart, so only the
+	// full-precision re-rank orders them correctly.
+	near := randomVector()
+	nearer := append([]float32(nil), near...)
+	nearer[0] += 1e-4
+	corpus = append(corpus,
+		searchableFile{"near.txt", near, created, false},
+		searchableFile{"nearer.txt", nearer, created, false},
+	)
+
+	for _, metric := range []string{vector.MetricCosine, vector.MetricL2, vector.MetricInner} {
+		t.Run(metric, func(t *testing.T) {
+			exact := config.EmbeddingConfig{ExpectedDim: dim, DefaultMetric: metric}
+			quantized := exact
+			quantized.Quantization = db.QuantizationHalfvec
+
+			queries := [][]float32{nearer}
+			for range 10 {
+				queries = append(queries, randomVector())
+			}
+			for _, query := range queries {
+				body := models.AdvancedSearchRequest{Embedding: query, TopK: topK}
+
+				code, want := postAdvancedSearch(t, newSearchStore(corpus), exact, body)
+				require.Equal(t, http.StatusOK, code)
+
+				fake := withQuantizedSearch(newSearchStore(corpus), metric, corpus)
+				code, got := postAdvancedSearch(t, fake, quantized, body)
+				require.Equal(t, http.StatusOK, code)
+
+				assert.Equal(t, filenames(want), filenames(got))
+				assert.Equal(t, 1, fake.called("SearchFilesQuantized"))
+			}
+		})
+	}
+}
+
+// TestQuantizedSearchSQL checks the quantized query orders by the indexed
+// halfvec expression and only serves the metric the index was built for
+func TestQuantizedSearchSQL(t *testing.T) {
+	cfg := config.EmbeddingConfig{ExpectedDim: 2, DefaultMetric: vector.MetricCosine, Quantization: db.QuantizationHalfvec}
+
+	fake := withQuantizedSearch(newSearchStore(searchCorpus), vector.MetricCosine, searchCorpus)
+	code, _ := postAdvancedSearch(t, fake, cfg, models.AdvancedSearchRequest{Embedding: []float32{1, 0}})
+	require.Equal(t, http.StatusOK, code)
+	sql := fake.lastSQL("SearchFilesQuantized")
+	assert.Contains(t, sql, "ORDER BY (embedding)::halfvec(2) <=> ($1::vector)::halfvec(2)")
+	assert.Contains(t, sql, "ORDER BY f.embedding <=> $1::vector, f.created_at, f.id", "results are ranked at full precision")
+
+	code, _ = postAdvancedSearch(t, fake, cfg, models.AdvancedSearchRequest{Embedding: []float32{1, 0}, Metric: vector.MetricL2})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, fake.called("SearchFilesQuantized"))
+	assert.Equal(t, 1, fake.called("SearchFilesL2"), "other metrics have no index to quantize")
+}
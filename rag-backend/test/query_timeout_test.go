@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/routes"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// slowDB delays every statement by delay, like a scan over a large table,
+// giving up early with the context's error once its deadline passes.
+type slowDB struct {
+	*fakeDB
+	delay time.Duration
+}
+
+func (s slowDB) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.delay):
+		return nil
+	}
+}
+
+func (s slowDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if err := s.wait(ctx); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return s.fakeDB.Exec(ctx, sql, args...)
+}
+
+func (s slowDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	return s.fakeDB.Query(ctx, sql, args...)
+}
+
+func (s slowDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if err := s.wait(ctx); err != nil {
+		return fakeRow{err: err}
+	}
+	return s.fakeDB.QueryRow(ctx, sql, args...)
+}
+
+func searchWithDelay(delay, timeout time.Duration) (*httptest.ResponseRecorder, time.Duration) {
+	store := newSearchStore([]searchableFile{{filename: "a.txt", embedding: []float32{1, 0}}})
+	queries := db.New(slowDB{fakeDB: store, delay: delay})
+	router := routes.NewRouter(context.Background(), queries, config.Config{DBQueryTimeout: timeout}, nil)
+
+	req, _ := http.NewRequest("POST", "/files/search/advanced", strings.NewReader(`{"embedding":[1,0],"metric":"cosine"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	start := time.Now()
+	router.ServeHTTP(w, req)
+	return w, time.Since(start)
+}
+
+// TestSimilaritySearchRespectsQueryTimeout verifies a search slower than
+// DB_QUERY_TIMEOUT is cancelled at the deadline and answered with 504.
+func TestSimilaritySearchRespectsQueryTimeout(t *testing.T) {
+	w, elapsed := searchWithDelay(5*time.Second, 50*time.Millisecond)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.JSONEq(t, `{"error":"database query timed out"}`, w.Body.String())
+	assert.Less(t, elapsed, time.Second, "the query must be cancelled at the deadline, not left to finish")
+}
+
+func TestSimilaritySearchWithinQueryTimeout(t *testing.T) {
+	w, _ := searchWithDelay(time.Millisecond, time.Second)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// blockingEmbedder waits for the request to end, like a provider that outlives the deadline.
+type blockingEmbedder struct{}
+
+func (blockingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestEmbedderFailureAfterDeadlineStays502 verifies only database errors are
+// turned into 504s: an embedder failure reported after the deadline keeps its 502.
+func TestEmbedderFailureAfterDeadlineStays502(t *testing.T) {
+	router := setupHandlersTestRouter()
+	router.ContextWithFallback = true
+	router.POST("/files/upload", handlers.QueryTimeout(20*time.Millisecond),
+		handlers.UploadHandler(newWriteStore().queries(), config.EmbeddingConfig{}, blockingEmbedder{}))
+
+	req, _ := http.NewRequest("POST", "/files/upload", strings.NewReader(`{"filename":"a.txt","content":"embed me"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.JSONEq(t, `{"error":"failed to embed content"}`, w.Body.String())
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+)
+
+// TestPurgeFileHandler verifies purge removes only recycle-bin files and 404s otherwise
+func TestPurgeFileHandler(t *testing.T) {
+	binned := uuid.New()
+	fake := newFakeDB()
+	fake.on("PurgeFile", func(args ...any) ([][]any, error) {
+		if args[0].(pgtype.UUID).Bytes == binned {
+			return [][]any{{}}, nil
+		}
+		return nil, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.DELETE("/files/:id/purge", handlers.PurgeFileHandler(fake.queries()))
+	send := func(id string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/files/"+id+"/purge", nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, send(binned.String()))
+	assert.Equal(t, http.StatusNotFound, send(uuid.New().String()), "live or missing files are not purged")
+	assert.Equal(t, http.StatusBadRequest, send("not-a-uuid"))
+	assert.Contains(t, fake.lastSQL("PurgeFile"), "AND deleted")
+}
+
+// TestEmptyRecycleBinHandler verifies emptying the bin reports the purged count
+func TestEmptyRecycleBinHandler(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("PurgeRecycleBin", func(args ...any) ([][]any, error) {
+		return [][]any{
+			{pgtype.UUID{Bytes: uuid.New(), Valid: true}},
+			{pgtype.UUID{Bytes: uuid.New(), Valid: true}},
+			{pgtype.UUID{Bytes: uuid.New(), Valid: true}},
+		}, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.DELETE("/files/recycle-bin", handlers.EmptyRecycleBinHandler(fake.queries()))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/files/recycle-bin", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.PurgeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(3), resp.Purged)
+	assert.Contains(t, fake.lastSQL("PurgeRecycleBin"), "WHERE deleted")
+}
+
+// TestEmptyRecycleBinDryRun verifies a dry run previews the purge and leaves the files in place, while a real run deletes them
+func TestEmptyRecycleBinDryRun(t *testing.T) {
+	bin := []string{"a.txt", "b.txt"}
+	fake := newFakeDB()
+	fake.on("CountRecycleBin", func(args ...any) ([][]any, error) {
+		return [][]any{{int64(len(bin))}}, nil
+	})
+	fake.on("GetRecycleBinSample", func(args ...any) ([][]any, error) {
+		var rows [][]any
+		for _, name := range bin[:min(len(bin), int(args[0].(int32)))] {
+			rows = append(rows, []any{pgtype.UUID{Bytes: uuid.New(), Valid: true}, name, float64(4), pgtype.Timestamptz{}})
+		}
+		return rows, nil
+	})
+	fake.on("PurgeRecycleBin", func(args ...any) ([][]any, error) {
+		rows := make([][]any, len(bin))
+		for i := range rows {
+			rows[i] = []any{pgtype.UUID{Bytes: uuid.New(), Valid: true}}
+		}
+		bin = nil
+		return rows, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.DELETE("/files/recycle-bin", handlers.EmptyRecycleBinHandler(fake.queries()))
+	send := func(path string) models.PurgeResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", path, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp models.PurgeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	preview := send("/files/recycle-bin?dry_run=true")
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, int64(2), preview.Purged)
+	require.Len(t, preview.Sample, 2)
+	assert.Equal(t, "a.txt", preview.Sample[0].Filename)
+	assert.Zero(t, fake.called("PurgeRecycleBin"))
+	assert.Len(t, bin, 2, "dry run must not delete")
+
+	purged := send("/files/recycle-bin")
+	assert.False(t, purged.DryRun)
+	assert.Equal(t, int64(2), purged.Purged)
+	assert.Empty(t, purged.Sample)
+	assert.Empty(t, bin)
+}
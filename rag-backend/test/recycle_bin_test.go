@@ -0,0 +1,82 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/db"
+)
+
+// newRecycleStore keeps one file whose deleted_at the soft-delete queries set and clear
+func newRecycleStore(file *db.File) *fakeDB {
+	fake := newFakeDB()
+	fake.on("SoftDeleteFile", func(args ...any) ([][]any, error) {
+		if args[0].(pgtype.UUID) == file.ID && !file.DeletedAt.Valid {
+			file.DeletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			file.Deleted = pgtype.Bool{Bool: true, Valid: true}
+		}
+		return nil, nil
+	})
+	fake.on("UndoSoftDelete", func(args ...any) ([][]any, error) {
+		if args[0].(pgtype.UUID) == file.ID {
+			file.DeletedAt = pgtype.Timestamptz{}
+			file.Deleted = pgtype.Bool{Bool: false, Valid: true}
+		}
+		return nil, nil
+	})
+	fake.on("GetDeletedFiles", func(args ...any) ([][]any, error) {
+		if !file.DeletedAt.Valid {
+			return nil, nil
+		}
+		return [][]any{fileRow(*file)}, nil
+	})
+	return fake
+}
+
+// TestSoftDeleteRecordsDeletedAt verifies soft delete stamps deleted_at, the recycle bin exposes it, and restore clears it
+func TestSoftDeleteRecordsDeletedAt(t *testing.T) {
+	id := uuid.New()
+	file := &db.File{ID: pgtype.UUID{Bytes: id, Valid: true}, Filename: "old.txt"}
+	fake := newRecycleStore(file)
+
+	router := setupHandlersTestRouter()
+	router.PATCH("/files/:id/soft-delete", handlers.SoftDeleteHandler(fake.queries()))
+	router.PATCH("/files/:id/restore", handlers.UndoSoftDeleteHandler(fake.queries()))
+	router.GET("/files/recycle-bin", handlers.GetDeletedFilesHandler(fake.queries()))
+	send := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	recycleBin := func() []map[string]any {
+		w := send("GET", "/files/recycle-bin")
+		require.Equal(t, http.StatusOK, w.Code)
+		var files []map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &files))
+		return files
+	}
+
+	require.Equal(t, http.StatusOK, send("PATCH", "/files/"+id.String()+"/soft-delete").Code)
+	assert.Contains(t, fake.lastSQL("SoftDeleteFile"), "deleted_at IS NULL", "re-deleting must keep the original deletion time")
+
+	files := recycleBin()
+	require.Len(t, files, 1)
+	deletedAt, err := time.Parse(time.RFC3339Nano, files[0]["DeletedAt"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), deletedAt, time.Minute)
+	assert.Equal(t, true, files[0]["Deleted"], "the boolean stays available for existing clients")
+
+	require.Equal(t, http.StatusOK, send("PATCH", "/files/"+id.String()+"/restore").Code)
+	assert.Empty(t, recycleBin())
+	assert.False(t, file.DeletedAt.Valid)
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/api/routes"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// newRepairStore answers the repair queries against in-memory files, paging by id like Postgres would
+func newRepairStore(files []*db.File) *fakeDB {
+	files = append([]*db.File{}, files...)
+	sort.Slice(files, func(i, j int) bool { return bytes.Compare(files[i].ID.Bytes[:], files[j].ID.Bytes[:]) < 0 })
+
+	fake := newFakeDB()
+	fake.on("GetFilesMissingContentHash", func(args ...any) ([][]any, error) {
+		after, limit := args[0].(pgtype.UUID), int(args[1].(int32))
+		var rows [][]any
+		for _, f := range files {
+			if !f.ContentHash.Valid && bytes.Compare(f.ID.Bytes[:], after.Bytes[:]) > 0 && len(rows) < limit {
+				rows = append(rows, []any{f.ID, f.Content})
+			}
+		}
+		return rows, nil
+	})
+	fake.on("SetContentHash", func(args ...any) ([][]any, error) {
+		id, hash := args[0].(pgtype.UUID), args[1].(pgtype.Text)
+		for _, f := range files {
+			if f.ID == id && !f.ContentHash.Valid {
+				f.ContentHash = hash
+				return [][]any{{}}, nil
+			}
+		}
+		return nil, nil
+	})
+	fake.on("CountFilesMissingContentHash", func(args ...any) ([][]any, error) {
+		var n int64
+		for _, f := range files {
+			if !f.ContentHash.Valid {
+				n++
+			}
+		}
+		return [][]any{{n}}, nil
+	})
+	return fake
+}
+
+func postRepair(t *testing.T, fake *fakeDB, query string) (int, models.RepairResponse) {
+	gin.SetMode(gin.TestMode)
+	router := routes.NewRouter(context.Background(), fake.queries(), config.Config{AdminToken: "secret"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/repair/content-hashes"+query, nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	router.ServeHTTP(w, req)
+
+	var resp models.RepairResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+// TestRepairContentHashes inserts hash-less rows and asserts they are populated in batches, idempotently
+func TestRepairContentHashes(t *testing.T) {
+	var files []*db.File
+	for _, content := range []string{"a", "b", "c", "d", "e"} {
+		files = append(files, &db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Content: content})
+	}
+	hashed := &db.File{
+		ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Content:     "kept",
+		ContentHash: pgtype.Text{String: "precomputed", Valid: true},
+	}
+	fake := newRepairStore(append(files, hashed))
+
+	code, resp := postRepair(t, fake, "?batch_size=2")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.RepairResponse{Repaired: 5, Batches: 3, Remaining: 0}, resp)
+
+	for _, f := range files {
+		assert.Equal(t, sha256Hex(f.Content), f.ContentHash.String)
+	}
+	assert.Equal(t, "precomputed", hashed.ContentHash.String)
+
+	commits, rollbacks := fake.txCounts()
+	assert.Equal(t, 3, commits)
+	assert.Zero(t, rollbacks)
+
+	// A second run finds nothing to do.
+	code, resp = postRepair(t, fake, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.RepairResponse{}, resp)
+}
+
+// TestRepairContentHashesBatchSize rejects out-of-range batch sizes
+func TestRepairContentHashesBatchSize(t *testing.T) {
+	for _, q := range []string{"?batch_size=0", "?batch_size=5001", "?batch_size=x"} {
+		code, _ := postRepair(t, newRepairStore(nil), q)
+		assert.Equal(t, http.StatusBadRequest, code, q)
+	}
+}
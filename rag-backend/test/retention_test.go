@@ -0,0 +1,106 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+)
+
+type agedFile struct {
+	filename  string
+	createdAt time.Time
+	deleted   bool
+}
+
+// newOldestStore answers GetOldestFiles the way Postgres would: filter, sort ascending, and limit
+func newOldestStore(files []agedFile) *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetOldestFiles", func(args ...any) ([][]any, error) {
+		includeDeleted, limit := args[0].(bool), int(args[1].(int32))
+
+		var matched []agedFile
+		for _, f := range files {
+			if includeDeleted || !f.deleted {
+				matched = append(matched, f)
+			}
+		}
+		sort.Slice(matched, func(i, j int) bool { return matched[i].createdAt.Before(matched[j].createdAt) })
+		if len(matched) > limit {
+			matched = matched[:limit]
+		}
+
+		rows := make([][]any, len(matched))
+		for i, f := range matched {
+			rows[i] = []any{
+				pgtype.UUID{Bytes: uuid.New(), Valid: true},
+				f.filename,
+				int32(len(f.filename)),
+				pgtype.Timestamptz{Time: f.createdAt, Valid: true},
+			}
+		}
+		return rows, nil
+	})
+	return fake
+}
+
+func getOldest(fake *fakeDB, query string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.GET("/files/oldest", handlers.GetOldestFilesHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/files/oldest"+query, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestGetOldestFilesHandler asserts ascending created_at ordering, ages, and soft-delete exclusion
+func TestGetOldestFilesHandler(t *testing.T) {
+	now := time.Now()
+	fake := newOldestStore([]agedFile{
+		{"middle.txt", now.Add(-48 * time.Hour), false},
+		{"oldest-deleted.txt", now.Add(-96 * time.Hour), true},
+		{"newest.txt", now.Add(-1 * time.Hour), false},
+		{"oldest.txt", now.Add(-72 * time.Hour), false},
+	})
+
+	w := getOldest(fake, "?limit=2")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, fake.lastSQL("GetOldestFiles"), "ORDER BY created_at ASC")
+
+	var files []models.FileAge
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &files))
+	require.Len(t, files, 2)
+	assert.Equal(t, "oldest.txt", files[0].Filename)
+	assert.Equal(t, "middle.txt", files[1].Filename)
+	assert.True(t, files[0].CreatedAt.Before(files[1].CreatedAt))
+	assert.Greater(t, files[0].AgeSeconds, files[1].AgeSeconds)
+	assert.InDelta(t, 72*3600, files[0].AgeSeconds, 5)
+
+	w = getOldest(fake, "?include_deleted=true")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &files))
+	require.Len(t, files, 4)
+	assert.Equal(t, "oldest-deleted.txt", files[0].Filename)
+	assert.True(t, sort.SliceIsSorted(files, func(i, j int) bool { return files[i].CreatedAt.Before(files[j].CreatedAt) }))
+}
+
+// TestGetOldestFilesHandlerLimit rejects limits outside the cap
+func TestGetOldestFilesHandlerLimit(t *testing.T) {
+	fake := newOldestStore(nil)
+	for _, limit := range []string{"0", "501", "-1", "ten"} {
+		w := getOldest(fake, "?limit="+limit)
+		assert.Equal(t, http.StatusBadRequest, w.Code, limit)
+	}
+	assert.Equal(t, 0, fake.called("GetOldestFiles"))
+}
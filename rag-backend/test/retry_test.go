@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+var errNotReady = errors.New("connection refused")
+
+// failingOp fails the first n calls and counts every call
+func failingOp(n int, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= n {
+			return errNotReady
+		}
+		return nil
+	}
+}
+
+// TestConnectRetrySucceedsAfterFailures verifies a database that comes up late is still reached
+func TestConnectRetrySucceedsAfterFailures(t *testing.T) {
+	calls := 0
+	retry := db.ConnectRetry{Retries: 5, InitialBackoff: time.Millisecond}
+	require.NoError(t, retry.Do(context.Background(), failingOp(3, &calls)))
+	assert.Equal(t, 4, calls)
+}
+
+// TestConnectRetryGivesUp verifies the last error is returned once retries are exhausted
+func TestConnectRetryGivesUp(t *testing.T) {
+	calls := 0
+	retry := db.ConnectRetry{Retries: 2, InitialBackoff: time.Millisecond}
+	err := retry.Do(context.Background(), failingOp(100, &calls))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errNotReady)
+	assert.Equal(t, 3, calls, "one attempt plus two retries")
+
+	calls = 0
+	require.Error(t, db.ConnectRetry{InitialBackoff: time.Millisecond}.Do(context.Background(), failingOp(100, &calls)))
+	assert.Equal(t, 1, calls, "zero retries tries once")
+}
+
+// TestConnectRetryTimeout verifies the overall timeout cuts the backoff short
+func TestConnectRetryTimeout(t *testing.T) {
+	calls := 0
+	retry := db.ConnectRetry{Retries: 100, Timeout: 50 * time.Millisecond, InitialBackoff: 20 * time.Millisecond}
+
+	start := time.Now()
+	err := retry.Do(context.Background(), failingOp(1000, &calls))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errNotReady)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Less(t, calls, 100)
+}
+
+// TestConfigLoadDBConnect verifies the retry settings default, parse, and reject negatives
+func TestConfigLoadDBConnect(t *testing.T) {
+	t.Setenv("DB_CONNECT_RETRIES", "")
+	t.Setenv("DB_CONNECT_TIMEOUT", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.DBConnect.Retries)
+	assert.Equal(t, 30*time.Second, cfg.DBConnect.Timeout)
+
+	t.Setenv("DB_CONNECT_RETRIES", "10")
+	t.Setenv("DB_CONNECT_TIMEOUT", "2m")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, db.ConnectRetry{Retries: 10, Timeout: 2 * time.Minute}, cfg.DBConnect)
+
+	t.Setenv("DB_CONNECT_RETRIES", "-1")
+	_, err = config.Load()
+	assert.Error(t, err)
+
+	t.Setenv("DB_CONNECT_RETRIES", "1")
+	t.Setenv("DB_CONNECT_TIMEOUT", "-5s")
+	_, err = config.Load()
+	assert.Error(t, err)
+}
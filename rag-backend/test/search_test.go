@@ -0,0 +1,330 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/vector"
+)
+
+type searchableFile struct {
+	filename  string
+	embedding []float32
+	createdAt time.Time
+	deleted   bool
+}
+
+// newSearchStore answers the SearchFiles* queries like Postgres would: filter, order by distance, and limit
+func newSearchStore(files []searchableFile) *fakeDB {
+	return newHashedSearchStore(files, nil)
+}
+
+// newHashedSearchStore is newSearchStore with content hashes by filename; files without one have a NULL hash
+func newHashedSearchStore(files []searchableFile, hashes map[string]string) *fakeDB {
+	fake := newFakeDB()
+	for name, metric := range map[string]string{
+		"SearchFilesCosine": vector.MetricCosine,
+		"SearchFilesL2":     vector.MetricL2,
+		"SearchFilesInner":  vector.MetricInner,
+	} {
+		fake.on(name, func(args ...any) ([][]any, error) {
+			query := args[0].(pgvector.Vector).Slice()
+			includeDeleted := args[1].(bool)
+			contains := args[2].(pgtype.Text)
+			after, before := args[3].(pgtype.Timestamptz), args[4].(pgtype.Timestamptz)
+			mimeType := args[5].(pgtype.Text)
+			limit := int(args[6].(int32))
+
+			type hit struct {
+				file     searchableFile
+				distance float64
+			}
+			var hits []hit
+			for _, f := range files {
+				switch {
+				case f.deleted && !includeDeleted:
+				case contains.Valid && !strings.Contains(strings.ToLower(f.filename), strings.ToLower(contains.String)):
+				case mimeType.Valid && mimeType.String != "text/plain":
+				case after.Valid && f.createdAt.Before(after.Time):
+				case before.Valid && f.createdAt.After(before.Time):
+				default:
+					d, err := vector.Distance(metric, query, f.embedding)
+					if err != nil {
+						return nil, err
+					}
+					hits = append(hits, hit{f, d})
+				}
+			}
+			sort.Slice(hits, func(i, j int) bool {
+				if hits[i].distance != hits[j].distance {
+					return hits[i].distance < hits[j].distance
+				}
+				return hits[i].file.createdAt.Before(hits[j].file.createdAt)
+			})
+			if len(hits) > limit {
+				hits = hits[:limit]
+			}
+
+			rows := make([][]any, len(hits))
+			for i, h := range hits {
+				rows[i] = []any{
+					pgtype.UUID{Bytes: uuid.New(), Valid: true},
+					h.file.filename,
+					pgtype.Timestamptz{Time: h.file.createdAt, Valid: true},
+					pgtype.Text{String: hashes[h.file.filename], Valid: hashes[h.file.filename] != ""},
+					h.distance,
+				}
+			}
+			return rows, nil
+		})
+	}
+	return fake
+}
+
+func postAdvancedSearch(t *testing.T, fake *fakeDB, cfg config.EmbeddingConfig, body any) (int, []models.SearchResult) {
+	return postAdvancedSearchQuery(t, fake, cfg, "", body)
+}
+
+func postAdvancedSearchQuery(t *testing.T, fake *fakeDB, cfg config.EmbeddingConfig, query string, body any) (int, []models.SearchResult) {
+	router := setupHandlersTestRouter()
+	router.POST("/files/search/advanced", handlers.AdvancedSearchHandler(fake.queries(), cfg, nil, false))
+
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/search/advanced"+query, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var results []models.SearchResult
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	}
+	return w.Code, results
+}
+
+func filenames(results []models.SearchResult) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Filename
+	}
+	return names
+}
+
+var searchCorpus = []searchableFile{
+	{"report-q1.txt", []float32{1, 0}, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), false},
+	{"report-q2.txt", []float32{0.9, 0.1}, time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), false},
+	{"report-q3.txt", []float32{0.95, 0.05}, time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), true},
+	{"notes.txt", []float32{1, 0}, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), false},
+	{"report-q4.txt", []float32{0, 1}, time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC), false},
+}
+
+// TestAdvancedSearchCombinesFilters covers embedding similarity, filename filter, and date range in one request
+func TestAdvancedSearchCombinesFilters(t *testing.T) {
+	cfg := config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}
+	after := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("EmbeddingFilterAndDate", func(t *testing.T) {
+		fake := newSearchStore(searchCorpus)
+		code, results := postAdvancedSearch(t, fake, cfg, models.AdvancedSearchRequest{
+			Embedding:        []float32{1, 0},
+			FilenameContains: "REPORT",
+			CreatedAfter:     &after,
+			CreatedBefore:    &before,
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"report-q2.txt", "report-q4.txt"}, filenames(results))
+		assert.Less(t, results[0].Distance, results[1].Distance)
+		assert.Equal(t, 1, fake.called("SearchFilesCosine"))
+	})
+
+	t.Run("IncludeDeletedAndTopK", func(t *testing.T) {
+		fake := newSearchStore(searchCorpus)
+		code, results := postAdvancedSearch(t, fake, cfg, models.AdvancedSearchRequest{
+			Embedding:        []float32{1, 0},
+			FilenameContains: "report",
+			CreatedAfter:     &after,
+			IncludeDeleted:   true,
+			TopK:             2,
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"report-q3.txt", "report-q2.txt"}, filenames(results))
+	})
+
+	t.Run("MetricSelectsQuery", func(t *testing.T) {
+		fake := newSearchStore(searchCorpus)
+		code, results := postAdvancedSearch(t, fake, cfg, models.AdvancedSearchRequest{
+			Embedding: []float32{0, 2},
+			Metric:    vector.MetricL2,
+			TopK:      1,
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"report-q4.txt"}, filenames(results))
+		assert.InDelta(t, 1.0, results[0].Distance, 1e-6)
+		assert.Equal(t, 1, fake.called("SearchFilesL2"))
+		assert.Zero(t, fake.called("SearchFilesCosine"))
+	})
+}
+
+// TestAdvancedSearchNormalizeScores checks relevance falls in [0,1] for every metric,
+// keeps the distance ranking, and is only returned when requested
+func TestAdvancedSearchNormalizeScores(t *testing.T) {
+	corpus := []searchableFile{
+		{"same.txt", []float32{3, 0}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"close.txt", []float32{2, 1}, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"orthogonal.txt", []float32{0, 1}, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), false},
+		{"opposite.txt", []float32{-5, 0}, time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, metric := range []string{vector.MetricL2, vector.MetricCosine, vector.MetricInner} {
+		t.Run(metric, func(t *testing.T) {
+			cfg := config.EmbeddingConfig{DefaultMetric: metric}
+			code, results := postAdvancedSearchQuery(t, newSearchStore(corpus), cfg, "?normalize_scores=true", models.AdvancedSearchRequest{
+				Embedding: []float32{1, 0},
+			})
+			require.Equal(t, http.StatusOK, code)
+			require.Len(t, results, len(corpus))
+
+			for i, r := range results {
+				require.NotNil(t, r.Relevance, r.Filename)
+				assert.GreaterOrEqual(t, *r.Relevance, 0.0)
+				assert.LessOrEqual(t, *r.Relevance, 1.0)
+				if i > 0 {
+					assert.LessOrEqual(t, results[i-1].Distance, r.Distance)
+					assert.GreaterOrEqual(t, *results[i-1].Relevance, *r.Relevance)
+				}
+			}
+		})
+	}
+
+	t.Run("CosineFormula", func(t *testing.T) {
+		cfg := config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}
+		_, results := postAdvancedSearchQuery(t, newSearchStore(corpus), cfg, "?normalize_scores=true", models.AdvancedSearchRequest{
+			Embedding: []float32{1, 0},
+		})
+		byName := map[string]float64{}
+		for _, r := range results {
+			byName[r.Filename] = *r.Relevance
+		}
+		assert.InDelta(t, 1.0, byName["same.txt"], 1e-6)
+		assert.InDelta(t, 0.5, byName["orthogonal.txt"], 1e-6)
+		assert.InDelta(t, 0.0, byName["opposite.txt"], 1e-6)
+	})
+
+	t.Run("OffByDefault", func(t *testing.T) {
+		code, results := postAdvancedSearch(t, newSearchStore(corpus), config.EmbeddingConfig{DefaultMetric: vector.MetricL2}, models.AdvancedSearchRequest{
+			Embedding: []float32{1, 0},
+		})
+		require.Equal(t, http.StatusOK, code)
+		for _, r := range results {
+			assert.Nil(t, r.Relevance)
+		}
+	})
+}
+
+// TestAdvancedSearchValidation rejects invalid and unsupported combinations before querying
+func TestAdvancedSearchValidation(t *testing.T) {
+	cfg := config.EmbeddingConfig{ExpectedDim: 2, DefaultMetric: vector.MetricCosine}
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.AddDate(1, 0, 0)
+
+	for _, tc := range []struct {
+		name string
+		body any
+	}{
+		{"MissingEmbedding", models.AdvancedSearchRequest{}},
+		{"WrongDimension", models.AdvancedSearchRequest{Embedding: []float32{1, 2, 3}}},
+		{"UnknownMetric", models.AdvancedSearchRequest{Embedding: []float32{1, 0}, Metric: "manhattan"}},
+		{"TopKTooLarge", models.AdvancedSearchRequest{Embedding: []float32{1, 0}, TopK: 51}},
+		{"InvertedDateRange", models.AdvancedSearchRequest{Embedding: []float32{1, 0}, CreatedAfter: &late, CreatedBefore: &early}},
+		{"TextQuery", models.AdvancedSearchRequest{Text: "quarterly revenue"}},
+		{"MetadataFilter", models.AdvancedSearchRequest{Embedding: []float32{1, 0}, Metadata: map[string]interface{}{"tag": "finance"}}},
+		{"Rerank", models.AdvancedSearchRequest{Embedding: []float32{1, 0}, Rerank: true}},
+		{"MalformedJSON", "not an object"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newSearchStore(searchCorpus)
+			code, _ := postAdvancedSearch(t, fake, cfg, tc.body)
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.Zero(t, fake.called("SearchFilesCosine"))
+		})
+	}
+}
+
+// TestAdvancedSearchEscapesFilenameFilter checks % and _ reach ILIKE as literals
+func TestAdvancedSearchEscapesFilenameFilter(t *testing.T) {
+	fake := newSearchStore(searchCorpus)
+	search := fake.handlers["SearchFilesCosine"]
+	var contains pgtype.Text
+	fake.on("SearchFilesCosine", func(args ...any) ([][]any, error) {
+		contains = args[2].(pgtype.Text)
+		return search(args...)
+	})
+
+	code, _ := postAdvancedSearch(t, fake, config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}, models.AdvancedSearchRequest{
+		Embedding:        []float32{1, 0},
+		FilenameContains: `50%_off\`,
+	})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, pgtype.Text{String: `50\%\_off\\`, Valid: true}, contains)
+}
+
+// TestSimilarityTieBreaker verifies equidistant results come back in a stable created_at, id order
+func TestSimilarityTieBreaker(t *testing.T) {
+	cfg := config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Both files point the same way, so their cosine distance to the query is identical.
+	corpus := []searchableFile{
+		{"newer.txt", []float32{2, 0}, older.Add(time.Hour), false},
+		{"older.txt", []float32{1, 0}, older, false},
+	}
+
+	fake := newSearchStore(corpus)
+	for range 5 {
+		code, results := postAdvancedSearch(t, fake, cfg, models.AdvancedSearchRequest{Embedding: []float32{1, 0}})
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, results, 2)
+		assert.Equal(t, results[0].Distance, results[1].Distance)
+		assert.Equal(t, []string{"older.txt", "newer.txt"}, filenames(results))
+	}
+
+	assert.Contains(t, fake.lastSQL("SearchFilesCosine"), "ORDER BY embedding <=> $1::vector, created_at, id")
+}
+
+// TestSearchDimensionMismatchError checks both search endpoints reject a wrong-length
+// embedding with the same error the upload paths use
+func TestSearchDimensionMismatchError(t *testing.T) {
+	cfg := config.EmbeddingConfig{ExpectedDim: 2, DefaultMetric: vector.MetricCosine}
+	router := setupHandlersTestRouter()
+	router.POST("/files/search/advanced", handlers.AdvancedSearchHandler(nil, cfg, nil, false))
+	router.POST("/files/hybrid-search", handlers.HybridSearchHandler(nil, cfg, nil, false))
+
+	for path, body := range map[string]any{
+		"/files/search/advanced": models.AdvancedSearchRequest{Embedding: []float32{1, 2, 3}},
+		"/files/hybrid-search":   models.HybridSearchRequest{Query: "x", Embedding: []float32{1, 2, 3}},
+	} {
+		payload, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", path, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.JSONEq(t, `{"error":"embedding dimension mismatch"}`, w.Body.String(), path)
+	}
+}
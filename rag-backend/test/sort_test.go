@@ -0,0 +1,92 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fain17/rag-backend/api/handlers"
+)
+
+// newSortStore records the sort arguments each list query receives
+func newSortStore(got *[]any) *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetAllFileSummaries", func(args ...any) ([][]any, error) {
+		*got = args[1:]
+		return nil, nil
+	})
+	fake.on("GetAllFiles", func(args ...any) ([][]any, error) {
+		*got = args[1:]
+		return nil, nil
+	})
+	fake.on("GetFileMetadata", func(args ...any) ([][]any, error) {
+		*got = args
+		return nil, nil
+	})
+	return fake
+}
+
+// withQuery appends query to path, which may already carry parameters
+func withQuery(path, query string) string {
+	if query == "" {
+		return path
+	}
+	if strings.Contains(path, "?") {
+		return path + "&" + query
+	}
+	return path + "?" + query
+}
+
+// TestListSorting verifies sort and order reach the list queries and default to newest first
+func TestListSorting(t *testing.T) {
+	paths := []string{"/files/getall", "/files/getall?include_content=true", "/files/metadata"}
+	cases := map[string][]any{
+		"":                           {"created_at", true},
+		"sort=filename&order=asc":    {"filename", false},
+		"sort=size":                  {"size", true},
+		"order=asc":                  {"created_at", false},
+		"sort=created_at&order=desc": {"created_at", true},
+	}
+
+	for _, path := range paths {
+		for query, want := range cases {
+			var got []any
+			fake := newSortStore(&got)
+			router := setupHandlersTestRouter()
+			router.GET("/files/getall", handlers.GetAllHandler(fake.queries()))
+			router.GET("/files/metadata", handlers.GetFileMetadataHandler(fake.queries()))
+
+			target := withQuery(path, query)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", target, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code, target)
+			assert.Equal(t, want, got, target)
+		}
+	}
+}
+
+// TestListSortingRejectsUnknownValues verifies non-whitelisted sort columns and directions get 400 without querying
+func TestListSortingRejectsUnknownValues(t *testing.T) {
+	for _, path := range []string{"/files/getall", "/files/metadata"} {
+		for _, query := range []string{"sort=content", "sort=" + url.QueryEscape("id;DROP TABLE files"), "order=sideways", "sort=FILENAME"} {
+			var got []any
+			fake := newSortStore(&got)
+			router := setupHandlersTestRouter()
+			router.GET("/files/getall", handlers.GetAllHandler(fake.queries()))
+			router.GET("/files/metadata", handlers.GetFileMetadataHandler(fake.queries()))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", path+"?"+query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, path+"?"+query)
+			assert.Nil(t, got, "no query runs for %s?%s", path, query)
+		}
+	}
+}
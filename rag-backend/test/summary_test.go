@@ -0,0 +1,120 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/vector"
+)
+
+// newSummaryStore serves content searches from contents and summary searches
+// from summaries, each holding the embeddings of that column.
+func newSummaryStore(contents, summaries []searchableFile) *fakeDB {
+	fake := newSearchStore(contents)
+	bySummary := newSearchStore(summaries)
+	fake.on("SearchSummariesCosine", bySummary.handlers["SearchFilesCosine"])
+	fake.on("SearchSummariesL2", bySummary.handlers["SearchFilesL2"])
+	fake.on("SearchSummariesInner", bySummary.handlers["SearchFilesInner"])
+	return fake
+}
+
+// TestAdvancedSearchSummaryMode checks mode=summary ranks by summary embeddings,
+// which can disagree with the content ranking, and skips files without a summary
+func TestAdvancedSearchSummaryMode(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// The long report's content drifts off-topic, but its summary is on point.
+	contents := []searchableFile{
+		{"long-report.txt", []float32{0, 1}, created, false},
+		{"memo.txt", []float32{1, 0.1}, created.Add(time.Hour), false},
+		{"unsummarized.txt", []float32{1, 0}, created.Add(2 * time.Hour), false},
+	}
+	summaries := []searchableFile{
+		{"long-report.txt", []float32{1, 0}, created, false},
+		{"memo.txt", []float32{0.5, 1}, created.Add(time.Hour), false},
+	}
+	cfg := config.EmbeddingConfig{DefaultMetric: vector.MetricCosine}
+	body := models.AdvancedSearchRequest{Embedding: []float32{1, 0}}
+
+	fake := newSummaryStore(contents, summaries)
+	code, results := postAdvancedSearch(t, fake, cfg, body)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"unsummarized.txt", "memo.txt", "long-report.txt"}, filenames(results))
+	assert.Zero(t, fake.called("SearchSummariesCosine"), "content is searched by default")
+
+	code, results = postAdvancedSearchQuery(t, fake, cfg, "?mode=summary", body)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"long-report.txt", "memo.txt"}, filenames(results))
+	sql := fake.lastSQL("SearchSummariesCosine")
+	assert.Contains(t, sql, "WHERE summary_embedding IS NOT NULL")
+	assert.Contains(t, sql, "ORDER BY summary_embedding <=> $1::vector, created_at, id")
+
+	for _, metric := range []string{vector.MetricL2, vector.MetricInner} {
+		code, _ = postAdvancedSearchQuery(t, fake, cfg, "?mode=summary", models.AdvancedSearchRequest{Embedding: []float32{1, 0}, Metric: metric})
+		require.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, 1, fake.called("SearchSummariesL2"))
+	assert.Equal(t, 1, fake.called("SearchSummariesInner"))
+
+	code, _ = postAdvancedSearchQuery(t, fake, cfg, "?mode=title", body)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// TestUploadStoresSummary checks a summary is stored with its embedding,
+// embedded server-side when omitted, and rejected when incomplete
+func TestUploadStoresSummary(t *testing.T) {
+	var summary pgtype.Text
+	var summaryEmbedding *pgvector.Vector
+	newStore := func() *fakeDB {
+		fake := newWriteStore()
+		create := fake.handlers["CreateFile"]
+		fake.on("CreateFile", func(args ...any) ([][]any, error) {
+			summary, summaryEmbedding = args[7].(pgtype.Text), args[8].(*pgvector.Vector)
+			return create(args...)
+		})
+		return fake
+	}
+	file := models.FileUploadRequest{Filename: "report.txt", Content: "a long report", Embedding: []float32{1, 2, 3}}
+
+	withSummary := file
+	withSummary.Summary = "revenue grew"
+	withSummary.SummaryEmbedding = []float32{4, 5, 6}
+	w := uploadWith(newStore(), nil, withSummary)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, pgtype.Text{String: "revenue grew", Valid: true}, summary)
+	require.NotNil(t, summaryEmbedding)
+	assert.Equal(t, []float32{4, 5, 6}, summaryEmbedding.Slice())
+
+	w = uploadWith(newStore(), nil, file)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, pgtype.Text{}, summary)
+	assert.Nil(t, summaryEmbedding, "no summary is stored as NULL")
+
+	embedder := &stubEmbedder{vec: []float32{7, 8, 9}}
+	withSummary.SummaryEmbedding = nil
+	w = uploadWith(newStore(), embedder, withSummary)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, []string{"revenue grew"}, embedder.texts, "only the summary needed embedding")
+	require.NotNil(t, summaryEmbedding)
+	assert.Equal(t, []float32{7, 8, 9}, summaryEmbedding.Slice())
+
+	for name, req := range map[string]models.FileUploadRequest{
+		"SummaryWithoutEmbedding": withSummary,
+		"EmbeddingWithoutSummary": {Filename: "x.txt", Content: "x", Embedding: []float32{1, 2, 3}, SummaryEmbedding: []float32{1, 2, 3}},
+		"WrongDimension":          {Filename: "x.txt", Content: "x", Embedding: []float32{1, 2, 3}, Summary: "x", SummaryEmbedding: []float32{1, 2}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fake := newStore()
+			w := uploadWith(fake, nil, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+			assert.Zero(t, fake.called("CreateFile"))
+		})
+	}
+}
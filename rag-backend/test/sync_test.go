@@ -0,0 +1,152 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// newFileStore wires a fake DB whose file lookups, inserts, and updates operate on an in-memory map keyed by filename.
+// Version snapshots are counted per file ID.
+func newFileStore(seed ...db.File) (*fakeDB, map[string]db.File, map[pgtype.UUID]int32) {
+	store := map[string]db.File{}
+	for _, f := range seed {
+		store[f.Filename] = f
+	}
+
+	fake := newFakeDB()
+	fake.on("GetLatestFileByFilename", func(args ...any) ([][]any, error) {
+		if f, ok := store[args[0].(string)]; ok {
+			return [][]any{fileRow(f)}, nil
+		}
+		return nil, nil
+	})
+	fake.on("CreateFile", func(args ...any) ([][]any, error) {
+		f := db.File{
+			ID:          pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			Filename:    args[0].(string),
+			Content:     args[1].(string),
+			Embedding:   args[2].(pgvector.Vector),
+			ContentHash: args[3].(pgtype.Text),
+		}
+		store[f.Filename] = f
+		return [][]any{fileRow(f)}, nil
+	})
+	versions := map[pgtype.UUID]int32{}
+	fake.on("CreateFileVersion", func(args ...any) ([][]any, error) {
+		id := args[0].(pgtype.UUID)
+		versions[id]++
+		return [][]any{{versions[id]}}, nil
+	})
+	fake.on("UpdateFile", func(args ...any) ([][]any, error) {
+		f := db.File{
+			ID:          args[0].(pgtype.UUID),
+			Filename:    args[1].(string),
+			Content:     args[2].(string),
+			Embedding:   args[3].(pgvector.Vector),
+			ContentHash: args[4].(pgtype.Text),
+		}
+		store[f.Filename] = f
+		return [][]any{fileRow(f)}, nil
+	})
+	return fake, store, versions
+}
+
+func postSync(t *testing.T, q *db.Queries, files []models.FileUploadRequest) (int, []models.FileSyncResult) {
+	router := setupHandlersTestRouter()
+	router.POST("/files/sync", handlers.SyncHandler(q, config.EmbeddingConfig{}, 0))
+
+	body, _ := json.Marshal(models.FileSyncRequest{Files: files})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/sync", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var results []models.FileSyncResult
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	}
+	return w.Code, results
+}
+
+// TestSyncHandlerOutcomes exercises the created, updated, and unchanged paths in one batch
+func TestSyncHandlerOutcomes(t *testing.T) {
+	// "same.txt" predates content hashes, so its hash is computed from stored content
+	fake, store, versions := newFileStore(
+		db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "same.txt", Content: "unchanged content"},
+		db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Filename: "changed.txt", Content: "old content"},
+	)
+	changedID := uuid.UUID(store["changed.txt"].ID.Bytes).String()
+
+	batch := []models.FileUploadRequest{
+		{Filename: "new.txt", Content: "brand new", Embedding: []float32{0.1, 0.2}},
+		{Filename: "same.txt", Content: "unchanged content", Embedding: []float32{0.3, 0.4}},
+		{Filename: "changed.txt", Content: "new content", Embedding: []float32{0.5, 0.6}},
+	}
+
+	code, results := postSync(t, fake.queries(), batch)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "created", results[0].Action)
+	assert.NotEmpty(t, results[0].ID)
+	assert.Equal(t, "unchanged", results[1].Action)
+	assert.Equal(t, "updated", results[2].Action)
+	assert.Equal(t, changedID, results[2].ID)
+	assert.Equal(t, "new content", store["changed.txt"].Content)
+	assert.Equal(t, 1, fake.called("CreateFile"))
+	assert.Equal(t, 1, fake.called("UpdateFile"))
+	assert.Equal(t, 1, fake.called("CreateFileVersion"), "an update must snapshot the old content")
+	assert.Equal(t, int32(1), versions[store["changed.txt"].ID])
+
+	// Re-running the same batch converges: nothing is written and everything is unchanged
+	code, results = postSync(t, fake.queries(), batch)
+	require.Equal(t, http.StatusOK, code)
+	for _, r := range results {
+		assert.Equal(t, "unchanged", r.Action, r.Filename)
+	}
+	assert.Equal(t, 1, fake.called("CreateFile"))
+	assert.Equal(t, 1, fake.called("UpdateFile"))
+	assert.Equal(t, 1, fake.called("CreateFileVersion"))
+}
+
+// TestSyncHandlerValidation covers batch-level rejection and per-item failures
+func TestSyncHandlerValidation(t *testing.T) {
+	t.Run("EmptyBatch", func(t *testing.T) {
+		code, _ := postSync(t, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("OversizedBatch", func(t *testing.T) {
+		files := make([]models.FileUploadRequest, 101)
+		code, _ := postSync(t, nil, files)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("InvalidItems", func(t *testing.T) {
+		fake, _, _ := newFileStore()
+		code, results := postSync(t, fake.queries(), []models.FileUploadRequest{
+			{Content: "no name", Embedding: []float32{0.1}},
+			{Filename: "no-vector.txt", Content: "text"},
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "failed", results[0].Action)
+		assert.Equal(t, "filename is required", results[0].Error)
+		assert.Equal(t, "failed", results[1].Action)
+		assert.Equal(t, "embedding is required", results[1].Error)
+		assert.Equal(t, 0, fake.called("GetLatestFileByFilename"))
+	})
+}
@@ -0,0 +1,145 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+)
+
+func sendTaggedFile(t *testing.T, method string, tags []string) ([]any, *httptest.ResponseRecorder) {
+	t.Helper()
+	fake := newWriteStore()
+	var tagArg []any
+	for _, name := range []string{"CreateFile", "UpdateFile"} {
+		next := fake.handlers[name]
+		fake.on(name, func(args ...any) ([][]any, error) {
+			// tags precede summary and summary_embedding in both queries
+			tagArg = append(tagArg, args[len(args)-3])
+			return next(args...)
+		})
+	}
+
+	router := setupHandlersTestRouter()
+	router.POST("/files/upload", handlers.UploadHandler(fake.queries(), config.EmbeddingConfig{}, nil))
+	router.PUT("/files/:id", handlers.UpdateHandler(fake.queries(), config.EmbeddingConfig{}, 0))
+
+	path := "/files/upload"
+	if method == "PUT" {
+		path = "/files/" + uuid.New().String()
+	}
+	body, _ := json.Marshal(models.FileUploadRequest{Filename: "doc.txt", Content: "text", Embedding: []float32{0.1}, Tags: tags})
+	req, _ := http.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return tagArg, w
+}
+
+func TestUploadNormalizesTags(t *testing.T) {
+	tagArg, w := sendTaggedFile(t, "POST", []string{" invoices ", "2026", "", "invoices"})
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, tagArg, 1)
+	assert.Equal(t, []string{"invoices", "2026"}, tagArg[0])
+}
+
+func TestUpdateWithoutTagsKeepsThem(t *testing.T) {
+	tagArg, w := sendTaggedFile(t, "PUT", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, tagArg, 1)
+	assert.Nil(t, tagArg[0], "omitted tags must reach the query as NULL")
+}
+
+func TestTagValidation(t *testing.T) {
+	tooMany := make([]string, 33)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+
+	for name, tags := range map[string][]string{
+		"TooLong": {strings.Repeat("x", 65)},
+		"TooMany": tooMany,
+	} {
+		t.Run(name, func(t *testing.T) {
+			tagArg, w := sendTaggedFile(t, "POST", tags)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Empty(t, tagArg)
+		})
+	}
+}
+
+func TestGetFilesByTagHandler(t *testing.T) {
+	id := uuid.New()
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	fake := newFakeDB()
+	var gotTag any
+	fake.on("GetFilesByTag", func(args ...any) ([][]any, error) {
+		gotTag = args[0]
+		return [][]any{{
+			pgtype.UUID{Bytes: id, Valid: true}, "invoice.txt", int32(42),
+			pgtype.Timestamptz{Time: created, Valid: true}, pgtype.Bool{Bool: false, Valid: true},
+			"text/plain", []string{"invoices", "2026"},
+		}}, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.GET("/files/by-tag", handlers.GetFilesByTagHandler(fake.queries()))
+
+	t.Run("Match", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/files/by-tag?tag=%20invoices%20", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "invoices", gotTag)
+
+		var files []models.FileSummary
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &files))
+		require.Len(t, files, 1)
+		assert.Equal(t, id.String(), files[0].ID)
+		assert.Equal(t, 42, files[0].Size)
+		assert.Equal(t, []string{"invoices", "2026"}, files[0].Tags)
+	})
+
+	t.Run("MissingTag", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/files/by-tag?tag=%20", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 1, fake.called("GetFilesByTag"))
+	})
+}
+
+func TestListTagsHandler(t *testing.T) {
+	fake := newFakeDB()
+	fake.on("ListTags", func(args ...any) ([][]any, error) {
+		return [][]any{{"invoices", int64(3)}, {"2026", int64(1)}}, nil
+	})
+
+	router := setupHandlersTestRouter()
+	router.GET("/files/tags", handlers.ListTagsHandler(fake.queries()))
+
+	req, _ := http.NewRequest("GET", "/files/tags", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var tags []models.TagCount
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+	assert.Equal(t, []models.TagCount{{Tag: "invoices", Count: 3}, {Tag: "2026", Count: 1}}, tags)
+}
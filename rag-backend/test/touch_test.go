@@ -0,0 +1,84 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// newTouchStore answers TouchFile against a single in-memory file, setting timestamps like Postgres would
+func newTouchStore(file *db.File) *fakeDB {
+	fake := newFakeDB()
+	fake.on("TouchFile", func(args ...any) ([][]any, error) {
+		reviewed, id := args[0].(bool), args[1].(pgtype.UUID)
+		if id != file.ID {
+			return nil, nil
+		}
+		now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		file.UpdatedAt = now
+		if reviewed {
+			file.ReviewedAt = now
+		}
+		return [][]any{{file.UpdatedAt, file.ReviewedAt}}, nil
+	})
+	return fake
+}
+
+func postTouch(fake *fakeDB, id, query string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/:id/touch", handlers.TouchHandler(fake.queries()))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/"+id+"/touch"+query, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestTouchHandler verifies updated_at advances while content stays unchanged
+func TestTouchHandler(t *testing.T) {
+	id := uuid.New()
+	before := time.Now().Add(-time.Hour)
+	file := &db.File{
+		ID:        pgtype.UUID{Bytes: id, Valid: true},
+		Filename:  "doc.txt",
+		Content:   "unchanged",
+		UpdatedAt: pgtype.Timestamptz{Time: before, Valid: true},
+	}
+	fake := newTouchStore(file)
+
+	w := postTouch(fake, id.String(), "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.TouchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, id.String(), resp.ID)
+	assert.True(t, resp.UpdatedAt.After(before))
+	assert.Nil(t, resp.ReviewedAt)
+	assert.Equal(t, "unchanged", file.Content)
+	assert.False(t, file.ReviewedAt.Valid)
+
+	w = postTouch(fake, id.String(), "?reviewed=true")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.ReviewedAt)
+	assert.True(t, file.ReviewedAt.Valid)
+}
+
+// TestTouchHandlerErrors covers invalid and missing files
+func TestTouchHandlerErrors(t *testing.T) {
+	fake := newTouchStore(&db.File{ID: pgtype.UUID{Bytes: uuid.New(), Valid: true}})
+
+	assert.Equal(t, http.StatusNotFound, postTouch(fake, uuid.New().String(), "").Code)
+	assert.Equal(t, http.StatusBadRequest, postTouch(fake, "nope", "").Code)
+}
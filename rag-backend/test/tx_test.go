@@ -0,0 +1,91 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/db"
+)
+
+// noTxDB hides fakeDB's Begin so ExecTx sees a handle without transaction support.
+type noTxDB struct{ db.DBTX }
+
+// TestExecTx verifies commit on success, rollback on failure, and the error for handles without transactions
+func TestExecTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Commit", func(t *testing.T) {
+		fake := newFakeDB()
+		fake.on("CountTotalFiles", func(args ...any) ([][]any, error) { return [][]any{{int64(3)}}, nil })
+
+		var count int64
+		err := fake.queries().ExecTx(ctx, func(q *db.Queries) error {
+			var err error
+			count, err = q.CountTotalFiles(ctx)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		commits, rollbacks := fake.txCounts()
+		assert.Equal(t, 1, commits)
+		assert.Zero(t, rollbacks)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		fake := newFakeDB()
+		boom := errors.New("boom")
+
+		err := fake.queries().ExecTx(ctx, func(q *db.Queries) error { return boom })
+		assert.ErrorIs(t, err, boom)
+
+		commits, rollbacks := fake.txCounts()
+		assert.Zero(t, commits)
+		assert.Equal(t, 1, rollbacks)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		q := db.New(noTxDB{newFakeDB()})
+		called := false
+
+		err := q.ExecTx(ctx, func(q *db.Queries) error { called = true; return nil })
+		assert.ErrorIs(t, err, db.ErrTxUnsupported)
+		assert.False(t, called)
+	})
+}
+
+// TestSyncHandlerRollsBackOnWriteFailure injects a failure after the lookup and asserts the item's transaction is rolled back
+func TestSyncHandlerRollsBackOnWriteFailure(t *testing.T) {
+	fake, _, _ := newFileStore(db.File{
+		ID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Filename: "doc.txt",
+		Content:  "old",
+	})
+	fake.on("UpdateFile", func(args ...any) ([][]any, error) {
+		return nil, errors.New("connection reset")
+	})
+
+	code, results := postSync(t, fake.queries(), []models.FileUploadRequest{
+		{Filename: "doc.txt", Content: "new", Embedding: []float32{1}},
+		{Filename: "fresh.txt", Content: "x", Embedding: []float32{1}},
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "failed", results[0].Action)
+	assert.Equal(t, "failed to update file", results[0].Error)
+	assert.Empty(t, results[0].ID)
+	assert.Equal(t, "created", results[1].Action)
+
+	commits, rollbacks := fake.txCounts()
+	assert.Equal(t, 1, commits)
+	assert.Equal(t, 1, rollbacks)
+}
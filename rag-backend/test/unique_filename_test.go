@@ -0,0 +1,75 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/db"
+)
+
+func TestUploadUniqueFilename(t *testing.T) {
+	existingID := uuid.New()
+	withExisting := func(fake *fakeDB) *[]any {
+		var looked []any
+		fake.on("GetLatestFileByFilename", func(args ...any) ([][]any, error) {
+			looked = args
+			return [][]any{fileRow(db.File{ID: pgtype.UUID{Bytes: existingID, Valid: true}, Filename: "copy.txt"})}, nil
+		})
+		return &looked
+	}
+
+	t.Run("OffByDefault", func(t *testing.T) {
+		fake := newWriteStore()
+		withExisting(fake)
+
+		w := uploadContent(fake, &stubEmbedder{vec: []float32{1}}, "")
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Zero(t, fake.called("GetLatestFileByFilename"))
+	})
+
+	t.Run("Taken", func(t *testing.T) {
+		fake := newWriteStore()
+		looked := withExisting(fake)
+
+		w := uploadContent(fake, &stubEmbedder{vec: []float32{1}}, "?unique_filename=true")
+
+		require.Equal(t, http.StatusConflict, w.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "filename already exists", body["error"])
+		assert.Equal(t, existingID.String(), body["id"])
+		assert.Equal(t, []any{"copy.txt"}, *looked)
+		assert.Zero(t, fake.called("GetFileByContentHash"), "the conflict wins over content dedup")
+		assert.Zero(t, fake.called("CreateFile"))
+	})
+
+	t.Run("Free", func(t *testing.T) {
+		fake := newWriteStore()
+		fake.on("GetLatestFileByFilename", func(args ...any) ([][]any, error) { return nil, nil })
+
+		w := uploadContent(fake, &stubEmbedder{vec: []float32{1}}, "?unique_filename=true")
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 1, fake.called("CreateFile"))
+	})
+
+	t.Run("LookupFails", func(t *testing.T) {
+		fake := newWriteStore()
+		fake.on("GetLatestFileByFilename", func(args ...any) ([][]any, error) {
+			return nil, errors.New("connection reset")
+		})
+
+		w := uploadContent(fake, nil, "?unique_filename=true")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Zero(t, fake.called("CreateFile"))
+	})
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+)
+
+func patchEmbedding(fake *fakeDB, id, body string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.PATCH("/files/:id/embedding", handlers.UpdateEmbeddingHandler(fake.queries(), 0))
+
+	req, _ := http.NewRequest("PATCH", "/files/"+id+"/embedding", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// newStoredVector holds one file whose stored embedding has three dimensions.
+func newStoredVector() *fakeDB {
+	fake := newFakeDB()
+	fake.on("GetFileEmbedding", func(args ...any) ([][]any, error) {
+		return [][]any{{pgvector.NewVector([]float32{1, 2, 3})}}, nil
+	})
+	fake.on("CreateFileVersion", func(args ...any) ([][]any, error) { return [][]any{{int32(1)}}, nil })
+	return fake
+}
+
+func TestUpdateEmbeddingHandler(t *testing.T) {
+	id := uuid.New()
+	updated := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	fake := newStoredVector()
+	var written []any
+	fake.on("UpdateFileEmbedding", func(args ...any) ([][]any, error) {
+		written = args
+		return [][]any{{pgtype.Timestamptz{Time: updated, Valid: true}}}, nil
+	})
+
+	w := patchEmbedding(fake, id.String(), `{"embedding":[0.5,0.25,0.125],"embedding_truncated":true}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, written, 3)
+	assert.Equal(t, []float32{0.5, 0.25, 0.125}, written[0].(pgvector.Vector).Slice())
+	assert.Equal(t, true, written[1])
+	assert.Equal(t, pgtype.UUID{Bytes: id, Valid: true}, written[2])
+	assert.Equal(t, 1, fake.called("CreateFileVersion"), "the old vector must be kept as a version")
+	commits, _ := fake.txCounts()
+	assert.Equal(t, 1, commits)
+
+	var resp models.EmbeddingUpdateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.EmbeddingUpdateResponse{ID: id.String(), Dimension: 3, UpdatedAt: updated}, resp)
+}
+
+func TestUpdateEmbeddingHandlerRejects(t *testing.T) {
+	for name, tc := range map[string]struct {
+		id   string
+		body string
+		code int
+	}{
+		"InvalidID":         {"not-a-uuid", `{"embedding":[1,2,3]}`, http.StatusBadRequest},
+		"MalformedBody":     {uuid.NewString(), `{"embedding":`, http.StatusBadRequest},
+		"MissingEmbedding":  {uuid.NewString(), `{}`, http.StatusBadRequest},
+		"DimensionMismatch": {uuid.NewString(), `{"embedding":[1,2]}`, http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fake := newStoredVector()
+			w := patchEmbedding(fake, tc.id, tc.body)
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.Zero(t, fake.called("UpdateFileEmbedding"))
+			assert.Zero(t, fake.called("CreateFileVersion"))
+		})
+	}
+
+	t.Run("MismatchReportsDimensions", func(t *testing.T) {
+		w := patchEmbedding(newStoredVector(), uuid.NewString(), `{"embedding":[1,2]}`)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, float64(3), body["expected"])
+		assert.Equal(t, float64(2), body["got"])
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		fake := newFakeDB()
+		fake.on("GetFileEmbedding", func(args ...any) ([][]any, error) { return nil, nil })
+
+		w := patchEmbedding(fake, uuid.NewString(), `{"embedding":[1,2,3]}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Zero(t, fake.called("UpdateFileEmbedding"))
+	})
+}
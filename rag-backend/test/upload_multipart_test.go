@@ -0,0 +1,114 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+)
+
+// postMultipart uploads content as the "file" field, adding an "embedding" field when it is non-empty
+func postMultipart(fake *fakeDB, maxBytes int64, filename, content, embedding string) *httptest.ResponseRecorder {
+	router := setupHandlersTestRouter()
+	router.POST("/files/upload-multipart", handlers.MultipartUploadHandler(fake.queries(), config.EmbeddingConfig{ExpectedDim: 2}, maxBytes))
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if filename != "" {
+		part, _ := form.CreateFormFile("file", filename)
+		part.Write([]byte(content))
+	}
+	if embedding != "" {
+		form.WriteField("embedding", embedding)
+	}
+	form.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/files/upload-multipart", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestMultipartUploadHandler verifies the uploaded text and base filename are stored with the embedding
+func TestMultipartUploadHandler(t *testing.T) {
+	var created db.CreateFileParams
+	id := uuid.New()
+	fake := newFakeDB()
+	fake.on("CreateFile", func(args ...any) ([][]any, error) {
+		created = db.CreateFileParams{
+			Filename:    args[0].(string),
+			Content:     args[1].(string),
+			Embedding:   args[2].(pgvector.Vector),
+			ContentHash: args[3].(pgtype.Text),
+		}
+		return [][]any{fileRow(db.File{
+			ID:          pgtype.UUID{Bytes: id, Valid: true},
+			Filename:    created.Filename,
+			Content:     created.Content,
+			Embedding:   created.Embedding,
+			ContentHash: created.ContentHash,
+		})}, nil
+	})
+
+	w := postMultipart(fake, 1<<20, "docs/notes.txt", "hello multipart", "[0.5, 0.25]")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/files/"+id.String(), w.Header().Get("Location"))
+
+	assert.Equal(t, "notes.txt", created.Filename)
+	assert.Equal(t, "hello multipart", created.Content)
+	assert.Equal(t, []float32{0.5, 0.25}, created.Embedding.Slice())
+	assert.Equal(t, sha256Hex("hello multipart"), created.ContentHash.String)
+
+	var file struct {
+		Filename string `json:"Filename"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &file))
+	assert.Equal(t, "notes.txt", file.Filename)
+}
+
+// TestMultipartUploadHandlerRejects covers oversized uploads and invalid form fields
+func TestMultipartUploadHandlerRejects(t *testing.T) {
+	fake := newFakeDB()
+
+	t.Run("TooLarge", func(t *testing.T) {
+		w := postMultipart(fake, 512, "big.txt", strings.Repeat("x", 4096), "[0.1, 0.2]")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		w := postMultipart(fake, 1<<20, "", "", "[0.1, 0.2]")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("BinaryContent", func(t *testing.T) {
+		w := postMultipart(fake, 1<<20, "blob.bin", "\xff\xfe\x00", "[0.1, 0.2]")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("MissingEmbedding", func(t *testing.T) {
+		w := postMultipart(fake, 1<<20, "a.txt", "text", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("DimensionMismatch", func(t *testing.T) {
+		w := postMultipart(fake, 1<<20, "a.txt", "text", "[0.1, 0.2, 0.3]")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "embedding dimension mismatch")
+	})
+
+	assert.Equal(t, 0, fake.called("CreateFile"))
+}
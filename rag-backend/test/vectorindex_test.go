@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/config"
+	"github.com/fain17/rag-backend/db"
+	"github.com/fain17/rag-backend/vector"
+)
+
+const (
+	existingIndexQuery = "SELECT indexdef FROM pg_indexes WHERE indexname = $1"
+	dropIndexStatement = "DROP INDEX IF EXISTS idx_files_embedding"
+	createHNSWIndex    = "CREATE INDEX IF NOT EXISTS idx_files_embedding ON files USING hnsw (embedding vector_cosine_ops) WITH (m = 16, ef_construction = 64)"
+)
+
+// TestVectorIndexCreateSQL asserts the CREATE INDEX statement generated for each index type
+func TestVectorIndexCreateSQL(t *testing.T) {
+	testCases := []struct {
+		name  string
+		index db.VectorIndex
+		want  string
+	}{
+		{
+			"IVFFlat",
+			db.VectorIndex{Type: db.IndexIVFFlat, Lists: 200},
+			"CREATE INDEX IF NOT EXISTS idx_files_embedding ON files USING ivfflat (embedding vector_cosine_ops) WITH (lists = 200)",
+		},
+		{
+			"HNSW",
+			db.VectorIndex{Type: db.IndexHNSW, M: 16, EfConstruction: 64},
+			createHNSWIndex,
+		},
+		{
+			"L2",
+			db.VectorIndex{Type: db.IndexIVFFlat, Metric: vector.MetricL2, Lists: 100},
+			"CREATE INDEX IF NOT EXISTS idx_files_embedding ON files USING ivfflat (embedding vector_l2_ops) WITH (lists = 100)",
+		},
+		{
+			"InnerProduct",
+			db.VectorIndex{Type: db.IndexHNSW, Metric: vector.MetricInner, M: 16, EfConstruction: 64},
+			"CREATE INDEX IF NOT EXISTS idx_files_embedding ON files USING hnsw (embedding vector_ip_ops) WITH (m = 16, ef_construction = 64)",
+		},
+		{
+			"Cosine",
+			db.VectorIndex{Type: db.IndexHNSW, Metric: vector.MetricCosine, M: 16, EfConstruction: 64},
+			createHNSWIndex,
+		},
+		{
+			"HalfvecQuantized",
+			db.VectorIndex{Type: db.IndexHNSW, Metric: vector.MetricL2, Quantization: db.QuantizationHalfvec, Dim: 384, M: 16, EfConstruction: 64},
+			"CREATE INDEX IF NOT EXISTS idx_files_embedding ON files USING hnsw (((embedding)::halfvec(384)) halfvec_l2_ops) WITH (m = 16, ef_construction = 64)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, err := tc.index.CreateSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, sql)
+		})
+	}
+}
+
+// TestVectorIndexValidate rejects unknown types and out-of-range parameters
+func TestVectorIndexValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		index db.VectorIndex
+	}{
+		{"UnknownType", db.VectorIndex{Type: "btree", Lists: 100}},
+		{"ZeroLists", db.VectorIndex{Type: db.IndexIVFFlat}},
+		{"MTooSmall", db.VectorIndex{Type: db.IndexHNSW, M: 1, EfConstruction: 64}},
+		{"EfConstructionBelowTwiceM", db.VectorIndex{Type: db.IndexHNSW, M: 48, EfConstruction: 64}},
+		{"UnknownMetric", db.VectorIndex{Type: db.IndexIVFFlat, Metric: "manhattan", Lists: 100}},
+		{"UnknownQuantization", db.VectorIndex{Type: db.IndexIVFFlat, Quantization: "int4", Lists: 100}},
+		{"HalfvecWithoutDimension", db.VectorIndex{Type: db.IndexIVFFlat, Quantization: db.QuantizationHalfvec, Lists: 100}},
+		{"HalfvecTooWide", db.VectorIndex{Type: db.IndexIVFFlat, Quantization: db.QuantizationHalfvec, Dim: 4001, Lists: 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, tc.index.Validate())
+			_, err := tc.index.CreateSQL()
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestConfigLoadVectorIndex verifies the index settings are read and validated from the environment
+func TestConfigLoadVectorIndex(t *testing.T) {
+	t.Setenv("VECTOR_INDEX_TYPE", "")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, db.VectorIndex{Type: db.IndexIVFFlat, Metric: vector.MetricCosine, Quantization: db.QuantizationNone, Lists: 100, M: 16, EfConstruction: 64}, cfg.VectorIndex)
+
+	t.Setenv("DEFAULT_METRIC", "inner")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, vector.MetricInner, cfg.VectorIndex.Metric, "the index follows DEFAULT_METRIC")
+
+	t.Setenv("VECTOR_INDEX_TYPE", "hnsw")
+	t.Setenv("VECTOR_INDEX_M", "24")
+	t.Setenv("VECTOR_INDEX_EF_CONSTRUCTION", "100")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, db.IndexHNSW, cfg.VectorIndex.Type)
+	assert.Equal(t, 24, cfg.VectorIndex.M)
+
+	t.Setenv("VECTOR_INDEX_QUANTIZATION", "halfvec")
+	_, err = config.Load()
+	assert.Error(t, err, "halfvec needs EXPECTED_EMBEDDING_DIM")
+	t.Setenv("EXPECTED_EMBEDDING_DIM", "384")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, db.QuantizationHalfvec, cfg.VectorIndex.Quantization)
+	assert.Equal(t, 384, cfg.VectorIndex.Dim)
+	assert.Equal(t, db.QuantizationHalfvec, cfg.Embedding.Quantization)
+	t.Setenv("VECTOR_INDEX_QUANTIZATION", "")
+
+	t.Setenv("VECTOR_INDEX_TYPE", "flat")
+	_, err = config.Load()
+	assert.Error(t, err)
+}
+
+// TestEnsureVectorIndex verifies the index is created when missing, kept when matching, and rebuilt when the type or operator class changes
+func TestEnsureVectorIndex(t *testing.T) {
+	hnsw := db.VectorIndex{Type: db.IndexHNSW, M: 16, EfConstruction: 64}
+
+	testCases := []struct {
+		name       string
+		existing   string
+		wantDrop   int
+		wantCreate int
+	}{
+		{"Missing", "", 0, 1},
+		{"SameType", "CREATE INDEX idx_files_embedding ON public.files USING hnsw (embedding vector_cosine_ops)", 0, 0},
+		{"DifferentType", "CREATE INDEX idx_files_embedding ON public.files USING ivfflat (embedding vector_cosine_ops) WITH (lists='100')", 1, 1},
+		{"DifferentOpClass", "CREATE INDEX idx_files_embedding ON public.files USING hnsw (embedding vector_l2_ops) WITH (m='16', ef_construction='64')", 1, 1},
+		{"Quantized", "CREATE INDEX idx_files_embedding ON public.files USING hnsw (((embedding)::halfvec(384)) halfvec_cosine_ops) WITH (m='16', ef_construction='64')", 1, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newFakeDB()
+			fake.on(existingIndexQuery, func(args ...any) ([][]any, error) {
+				if tc.existing == "" {
+					return nil, nil
+				}
+				return [][]any{{tc.existing}}, nil
+			})
+			fake.on(dropIndexStatement, func(args ...any) ([][]any, error) { return nil, nil })
+			fake.on(createHNSWIndex, func(args ...any) ([][]any, error) { return nil, nil })
+
+			require.NoError(t, fake.queries().EnsureVectorIndex(context.Background(), hnsw))
+			assert.Equal(t, tc.wantDrop, fake.called(dropIndexStatement))
+			assert.Equal(t, tc.wantCreate, fake.called(createHNSWIndex))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	pgvector "github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fain17/rag-backend/api/handlers"
+	"github.com/fain17/rag-backend/api/models"
+	"github.com/fain17/rag-backend/config"
+)
+
+func newVersionRouter(fake *fakeDB, keep int) http.Handler {
+	router := setupHandlersTestRouter()
+	router.PUT("/files/:id", handlers.UpdateHandler(fake.queries(), config.EmbeddingConfig{}, keep))
+	router.GET("/files/:id/versions", handlers.ListFileVersionsHandler(fake.queries()))
+	router.POST("/files/:id/versions/:version/restore", handlers.RestoreFileVersionHandler(fake.queries(), keep))
+	return router
+}
+
+func serve(router http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateSavesVersion(t *testing.T) {
+	id := uuid.New()
+	update := models.FileUploadRequest{Filename: "doc.txt", Content: "new", Embedding: []float32{1}}
+
+	t.Run("SnapshotsThenPrunes", func(t *testing.T) {
+		fake := newWriteStore()
+		var pruned []any
+		fake.on("PruneFileVersions", func(args ...any) ([][]any, error) {
+			pruned = args
+			return nil, nil
+		})
+
+		w := serve(newVersionRouter(fake, 3), "PUT", "/files/"+id.String(), update)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, fake.called("CreateFileVersion"))
+		assert.Equal(t, 1, fake.called("UpdateFile"))
+		assert.Equal(t, []any{pgtype.UUID{Bytes: id, Valid: true}, int32(3)}, pruned)
+		commits, rollbacks := fake.txCounts()
+		assert.Equal(t, 1, commits)
+		assert.Zero(t, rollbacks)
+	})
+
+	t.Run("UnlimitedSkipsPrune", func(t *testing.T) {
+		fake := newWriteStore()
+
+		w := serve(newVersionRouter(fake, 0), "PUT", "/files/"+id.String(), update)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Zero(t, fake.called("PruneFileVersions"))
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		fake := newWriteStore()
+		fake.on("CreateFileVersion", func(args ...any) ([][]any, error) { return nil, nil })
+
+		w := serve(newVersionRouter(fake, 3), "PUT", "/files/"+id.String(), update)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Zero(t, fake.called("UpdateFile"))
+		_, rollbacks := fake.txCounts()
+		assert.Equal(t, 1, rollbacks)
+	})
+}
+
+func TestListFileVersionsHandler(t *testing.T) {
+	saved := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	fake := newFakeDB()
+	fake.on("ListFileVersions", func(args ...any) ([][]any, error) {
+		return [][]any{
+			{int32(2), "doc-v2.txt", int32(7), pgtype.Text{String: "hash2", Valid: true}, "text/plain", []string{"draft"}, pgtype.Timestamptz{Time: saved, Valid: true}},
+			{int32(1), "doc.txt", int32(3), pgtype.Text{}, "text/plain", []string{}, pgtype.Timestamptz{Time: saved.Add(-time.Hour), Valid: true}},
+		}, nil
+	})
+	router := newVersionRouter(fake, 0)
+
+	w := serve(router, "GET", "/files/"+uuid.NewString()+"/versions", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var versions []models.FileVersionSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &versions))
+	require.Len(t, versions, 2)
+	assert.Equal(t, models.FileVersionSummary{
+		Version: 2, Filename: "doc-v2.txt", Size: 7, ContentHash: "hash2",
+		MimeType: "text/plain", Tags: []string{"draft"}, CreatedAt: saved,
+	}, versions[0])
+	assert.Equal(t, 1, versions[1].Version)
+
+	assert.Equal(t, http.StatusBadRequest, serve(router, "GET", "/files/nope/versions", nil).Code)
+}
+
+func TestRestoreFileVersionHandler(t *testing.T) {
+	id := uuid.New()
+	path := "/files/" + id.String() + "/versions/2/restore"
+
+	t.Run("Restores", func(t *testing.T) {
+		fake := newWriteStore()
+		var requested []any
+		fake.on("GetFileVersion", func(args ...any) ([][]any, error) {
+			requested = args
+			return [][]any{{
+				pgtype.UUID{Bytes: id, Valid: true}, int32(2), "old.txt", "old content",
+				pgvector.NewVector([]float32{0.5}), pgtype.Text{String: "oldhash", Valid: true},
+				"text/markdown", []string{"archived"}, pgtype.Timestamptz{Valid: true}, true,
+			}}, nil
+		})
+		var written []any
+		next := fake.handlers["UpdateFile"]
+		fake.on("UpdateFile", func(args ...any) ([][]any, error) {
+			written = args
+			return next(args...)
+		})
+
+		w := serve(newVersionRouter(fake, 0), "POST", path, nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []any{pgtype.UUID{Bytes: id, Valid: true}, int32(2)}, requested)
+		assert.Equal(t, 1, fake.called("CreateFileVersion"), "the replaced state must be saved")
+		require.Len(t, written, 10)
+		assert.Equal(t, "old.txt", written[1])
+		assert.Equal(t, "old content", written[2])
+		assert.Equal(t, []float32{0.5}, written[3].(pgvector.Vector).Slice())
+		assert.Equal(t, pgtype.Text{String: "oldhash", Valid: true}, written[4])
+		assert.Equal(t, "text/markdown", written[5])
+		assert.Equal(t, true, written[6], "the version's truncation flag is restored with its embedding")
+		assert.Equal(t, []string{"archived"}, written[7])
+		assert.Equal(t, pgtype.Text{}, written[8], "the summary described the replaced content")
+		assert.Nil(t, written[9])
+		commits, _ := fake.txCounts()
+		assert.Equal(t, 1, commits)
+	})
+
+	t.Run("VersionNotFound", func(t *testing.T) {
+		fake := newWriteStore()
+		fake.on("GetFileVersion", func(args ...any) ([][]any, error) { return nil, nil })
+
+		w := serve(newVersionRouter(fake, 0), "POST", path, nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Zero(t, fake.called("CreateFileVersion"))
+		assert.Zero(t, fake.called("UpdateFile"))
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		for _, version := range []string{"0", "-1", "two"} {
+			fake := newWriteStore()
+			w := serve(newVersionRouter(fake, 0), "POST", "/files/"+id.String()+"/versions/"+version+"/restore", nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code, version)
+			assert.Zero(t, fake.called("GetFileVersion"))
+		}
+	})
+}
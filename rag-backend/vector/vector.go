@@ -0,0 +1,147 @@
+// Package vector implements the distance metrics pgvector supports so results
+// computed in Go agree with those computed by the database.
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Supported metric names.
+const (
+	MetricL2     = "l2"
+	MetricCosine = "cosine"
+	MetricInner  = "inner"
+)
+
+// MetricInfo describes how a metric is computed and how its distances rank.
+type MetricInfo struct {
+	Name string
+	// Operator is the pgvector operator used in ORDER BY.
+	Operator string
+	// LowerIsBetter reports whether smaller distances mean closer matches.
+	LowerIsBetter bool
+	Description   string
+}
+
+// Metrics returns the supported metrics in a stable order.
+func Metrics() []MetricInfo {
+	return []MetricInfo{
+		{MetricL2, "<->", true, "Euclidean distance; 0 for identical vectors, unbounded above."},
+		{MetricCosine, "<=>", true, "1 - cosine similarity; 0 for the same direction, 1 orthogonal, 2 opposite. Ignores magnitude."},
+		{MetricInner, "<#>", true, "Negated dot product, so more negative is closer. Equals negated cosine similarity for unit-normalized embeddings."},
+	}
+}
+
+// ErrDimensionMismatch is returned when two vectors have different lengths.
+var ErrDimensionMismatch = errors.New("vector dimensions differ")
+
+// ErrZeroVector is returned when cosine distance is requested for a zero-length vector.
+var ErrZeroVector = errors.New("cosine distance is undefined for a zero vector")
+
+// ValidMetric reports whether metric is one of the supported metric names.
+func ValidMetric(metric string) bool {
+	switch metric {
+	case MetricL2, MetricCosine, MetricInner:
+		return true
+	}
+	return false
+}
+
+// Distance computes the pgvector distance between a and b:
+// Euclidean for l2 (<->), 1 - cosine similarity for cosine (<=>),
+// and the negated dot product for inner (<#>).
+func Distance(metric string, a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, ErrDimensionMismatch
+	}
+
+	switch metric {
+	case MetricL2:
+		var sum float64
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			sum += d * d
+		}
+		return math.Sqrt(sum), nil
+
+	case MetricCosine:
+		var dot, normA, normB float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+			normA += float64(a[i]) * float64(a[i])
+			normB += float64(b[i]) * float64(b[i])
+		}
+		if normA == 0 || normB == 0 {
+			return 0, ErrZeroVector
+		}
+		return 1 - dot/(math.Sqrt(normA)*math.Sqrt(normB)), nil
+
+	case MetricInner:
+		var dot float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+		}
+		return -dot, nil
+	}
+
+	return 0, fmt.Errorf("unsupported metric %q", metric)
+}
+
+// Stats summarises a vector's components for spotting degenerate embeddings.
+type Stats struct {
+	Dimension int
+	Norm      float64
+	Min       float64
+	Max       float64
+	Mean      float64
+	Zeros     int
+}
+
+// Summarize computes Stats for v. Min, Max, and Mean are zero for an empty vector.
+func Summarize(v []float32) Stats {
+	s := Stats{Dimension: len(v)}
+	if len(v) == 0 {
+		return s
+	}
+
+	s.Min, s.Max = math.Inf(1), math.Inf(-1)
+	var sum, sumSquares float64
+	for _, x := range v {
+		f := float64(x)
+		sum += f
+		sumSquares += f * f
+		s.Min = math.Min(s.Min, f)
+		s.Max = math.Max(s.Max, f)
+		if x == 0 {
+			s.Zeros++
+		}
+	}
+	s.Norm = math.Sqrt(sumSquares)
+	s.Mean = sum / float64(len(v))
+	return s
+}
+
+// Relevance maps a pgvector distance to a 0-1 score where higher is closer,
+// so clients can display it without knowing the metric:
+//
+//   - l2: 1 / (1 + distance)
+//   - cosine: (1 + cosine similarity) / 2, i.e. 1 - distance/2
+//   - inner: the logistic of the dot product, 1 / (1 + e^distance), since
+//     the dot product is unbounded unless embeddings are unit-normalized
+//
+// Each mapping is strictly decreasing in distance, so ranking is preserved.
+func Relevance(metric string, distance float64) float64 {
+	var r float64
+	switch metric {
+	case MetricL2:
+		r = 1 / (1 + distance)
+	case MetricCosine:
+		r = 1 - distance/2
+	case MetricInner:
+		r = 1 / (1 + math.Exp(distance))
+	}
+	// Floating-point error can push cosine distance just outside [0, 2].
+	return min(max(r, 0), 1)
+}
@@ -1,9 +1,80 @@
+import codecs
 from pathlib import Path
 from typing import cast
 
 from fastapi import UploadFile
 from pdfminer.high_level import extract_text
 
+MIME_EXTENSIONS = {
+    "application/pdf": ".pdf",
+    "text/plain": ".txt",
+    "text/markdown": ".md",
+    "text/x-markdown": ".md",
+}
+
+
+def infer_extension(content_type: str | None) -> str | None:
+    """Map a MIME type (parameters ignored) to a supported extension."""
+    if not content_type:
+        return None
+    mime = content_type.split(";", 1)[0].strip().lower()
+    return MIME_EXTENSIONS.get(mime)
+
+
+# How much of an upload is read to detect its type.
+SNIFF_BYTES = 512
+
+
+def sniff_content_type(head: bytes) -> str | None:
+    """Detect a supported type from the first bytes of a file.
+
+    Returns application/pdf or text/plain, or None when neither matches.
+    """
+    if head.startswith(b"%PDF-"):
+        return "application/pdf"
+    if not head or b"\x00" in head:
+        return None
+    try:
+        # Incremental decoding tolerates a character cut off by the read.
+        codecs.getincrementaldecoder("utf-8")().decode(head, final=False)
+    except UnicodeDecodeError:
+        return None
+    return "text/plain"
+
+
+def resolve_content_type(head: bytes, declared: str | None) -> str | None:
+    """Prefer the sniffed type, falling back to declared when detection fails.
+
+    Sniffing cannot tell text formats apart, so a declared text/* type
+    refines detected text (text/markdown keeps its .md extension).
+    """
+    sniffed = sniff_content_type(head)
+    if sniffed is None:
+        return declared
+    if sniffed == "text/plain" and (declared or "").lower().startswith(
+        "text/"
+    ):
+        return declared
+    return sniffed
+
+
+async def detect_content_type(file: UploadFile) -> str | None:
+    """Resolve an upload's type from its content, then rewind it."""
+    head = await file.read(SNIFF_BYTES)
+    await file.seek(0)
+    return resolve_content_type(head, file.content_type)
+
+
+def with_inferred_extension(filename: str, content_type: str | None) -> str:
+    """Append an extension inferred from content_type if filename has none.
+
+    Filenames that already carry an extension are returned unchanged.
+    """
+    if Path(filename).suffix:
+        return filename
+    ext = infer_extension(content_type)
+    return filename + ext if ext else filename
+
 
 async def to_text(file: UploadFile) -> str:
     if file.filename is None:
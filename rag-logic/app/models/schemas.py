@@ -1,18 +1,47 @@
 from typing import List
 
-from pydantic import BaseModel
+from pydantic import BaseModel, Field
+
+DEFAULT_PREVIEW_LENGTH = 200
 
 
 class QueryRequest(BaseModel):
     prompt: str
+    preview_length: int = Field(
+        default=DEFAULT_PREVIEW_LENGTH,
+        ge=1,
+        description="Maximum characters of chunk text returned per source",
+    )
+    min_relevance: float | None = Field(
+        default=None,
+        ge=0,
+        le=1,
+        description=(
+            "Minimum relevance, (1 + cosine similarity) / 2, for a chunk "
+            "to be used as context"
+        ),
+    )
 
 
 class FileData(BaseModel):
+    id: str
     filename: str
     content: str
     similarity: float
 
 
+class SourceTrace(BaseModel):
+    """A retrieved chunk that was placed in the answer's context."""
+
+    document_id: str
+    filename: str
+    rank: int
+    score: float
+    preview: str
+
+
 class QueryResponse(BaseModel):
     matches: List[FileData]
+    sources: List[SourceTrace]
     answer: str
+    insufficient_context: bool = False
@@ -1,4 +1,4 @@
-from fastapi import APIRouter, Depends, File, UploadFile
+from fastapi import APIRouter, Depends, File, Form, Query, UploadFile
 from sqlalchemy.orm import Session
 
 import app.services.file_operations as fo
@@ -9,26 +9,45 @@ from app.services.query_service import run_query_pipeline
 router = APIRouter(prefix="/file", tags=["file"])
 
 
+SUMMARY_DESCRIPTION = "Summary to store; generated by the LLM when omitted"
+
+
 @router.post("/upload")
-async def upload_file(file: UploadFile = File(...)):
-    return await fo.upload_file_service(file)
+async def upload_file(
+    file: UploadFile = File(...),
+    summary: str | None = Form(default=None, description=SUMMARY_DESCRIPTION),
+):
+    return await fo.upload_file_service(file, summary)
 
 
 @router.put("/{file_id}")
 async def update_file(
     file_id: str,
     file: UploadFile = File(...),
+    summary: str | None = Form(default=None, description=SUMMARY_DESCRIPTION),
     db: Session = Depends(get_db_session),
 ):
-    return await fo.update_file_service(file_id, file)
+    return await fo.update_file_service(file_id, file, summary)
 
 
 @router.post("/query", response_model=QueryResponse)
 async def query_route(
-    req: QueryRequest, db: Session = Depends(get_db_session)
+    req: QueryRequest,
+    expand: bool = Query(
+        default=False,
+        description="Also search LLM paraphrases and fuse the results",
+    ),
+    db: Session = Depends(get_db_session),
 ):
-    files, answer = await run_query_pipeline(req.prompt, db)
-    return QueryResponse(matches=files, answer=answer)
+    files, sources, answer = await run_query_pipeline(
+        req.prompt, db, req.preview_length, expand, req.min_relevance
+    )
+    return QueryResponse(
+        matches=files,
+        sources=sources,
+        answer=answer,
+        insufficient_context=req.min_relevance is not None and not files,
+    )
 
 
 @router.delete("/{file_id}")
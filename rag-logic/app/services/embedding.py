@@ -1,9 +1,79 @@
+import logging
+import os
 from typing import cast
 
 import httpx
 
 EMBEDDING_API_URL = "http://localhost:8001/embed"
 
+LENGTH_UNITS = ("chars", "tokens")
+
+
+def validate_length_unit(unit: str) -> str:
+    """Return unit if it is a supported length unit, else raise ValueError."""
+    if unit not in LENGTH_UNITS:
+        raise ValueError(
+            f"unsupported length unit: {unit!r}; use one of {LENGTH_UNITS}"
+        )
+    return unit
+
+
+# Pre-embedding length limit; 0 disables it. The unit is "chars" or
+# "tokens", where tokens are estimated at CHARS_PER_TOKEN characters each.
+# Both are checked at import so a bad value stops startup.
+EMBED_MAX_LENGTH = int(os.getenv("EMBED_MAX_LENGTH", "0"))
+EMBED_LENGTH_UNIT = validate_length_unit(
+    os.getenv("EMBED_LENGTH_UNIT", "chars")
+)
+# Reject over-length content instead of trimming it.
+EMBED_STRICT_LENGTH = os.getenv("EMBED_STRICT_LENGTH", "false").lower() in {
+    "1",
+    "true",
+    "yes",
+}
+CHARS_PER_TOKEN = 4
+
+logger = logging.getLogger(__name__)
+
+
+class ContentTooLongError(ValueError):
+    """Content exceeds the embedding length limit in strict mode."""
+
+
+def prepare_embedding_text(
+    text: str,
+    max_length: int | None = None,
+    unit: str | None = None,
+    strict: bool | None = None,
+) -> tuple[str, bool]:
+    """Trim text to the embedding limit, returning it and whether it was cut.
+
+    In strict mode over-length text raises ContentTooLongError instead.
+    """
+    max_length = EMBED_MAX_LENGTH if max_length is None else max_length
+    unit = EMBED_LENGTH_UNIT if unit is None else validate_length_unit(unit)
+    strict = EMBED_STRICT_LENGTH if strict is None else strict
+
+    if max_length <= 0:
+        return text, False
+
+    max_chars = max_length
+    if unit == "tokens":
+        max_chars *= CHARS_PER_TOKEN
+    if len(text) <= max_chars:
+        return text, False
+
+    if strict:
+        raise ContentTooLongError(
+            f"content exceeds the embedding limit of {max_length} {unit}"
+        )
+    logger.warning(
+        "trimming content from %d to %d characters before embedding",
+        len(text),
+        max_chars,
+    )
+    return text[:max_chars], True
+
 
 async def embed_text(text: str) -> list[float]:
     async with httpx.AsyncClient() as client:
@@ -1,31 +1,83 @@
+import logging
 from pathlib import Path
 
 import httpx
 from fastapi import File, HTTPException, UploadFile
 
-from app.helpers.file_format_convert import to_text
-from app.services.embedding import embed_text
+from app.helpers.file_format_convert import (
+    detect_content_type,
+    to_text,
+    with_inferred_extension,
+)
+from app.services.embedding import (
+    ContentTooLongError,
+    embed_text,
+    prepare_embedding_text,
+)
+from app.services.llm_chain import summary_chain
 
 GO_BACKEND_URL = "http://127.0.0.1:8080"
 ALLOWED = {".txt", ".md", ".pdf"}
+# Leading characters of the content the LLM reads when summarizing.
+SUMMARY_INPUT_CHARS = 8000
 
+logger = logging.getLogger(__name__)
 
-async def upload_file_service(file: UploadFile = File(...)):
+
+def generate_summary(content: str) -> str | None:
+    """Ask the LLM for a short summary of content.
+
+    Any LLM failure yields no summary so the file is stored without one.
+    """
+    try:
+        raw = summary_chain.invoke({"content": content[:SUMMARY_INPUT_CHARS]})
+    except Exception:
+        logger.warning("summary generation failed; storing without one")
+        return None
+    return str(raw).strip() or None
+
+
+async def summary_fields(content: str, summary: str | None) -> dict:
+    """Return the summary and its embedding for the backend payload.
+
+    A missing summary is generated by the LLM. Nothing is returned when
+    there is no summary, which the backend stores as NULL.
+    """
+    summary = (summary or "").strip() or generate_summary(content)
+    if not summary:
+        return {}
+    embed_input, _ = prepare_embedding_text(summary)
+    return {
+        "summary": summary,
+        "summary_embedding": await embed_text(embed_input),
+    }
+
+
+async def upload_file_service(
+    file: UploadFile = File(...), summary: str | None = None
+):
     try:
         if file.filename is None:
             raise HTTPException(400, "File must have a filename")
 
+        file.filename = with_inferred_extension(
+            file.filename, await detect_content_type(file)
+        )
         ext = Path(file.filename).suffix.lower()
         if ext not in ALLOWED:
             raise HTTPException(400, f"Unsupported file type: {ext}")
 
         file_content = await to_text(file)
-        embedding = await embed_text(file_content)
+        # The full content is stored; only the embedded text is trimmed.
+        embed_input, truncated = prepare_embedding_text(file_content)
+        embedding = await embed_text(embed_input)
 
         payload = {
             "filename": file.filename,
             "content": file_content,
             "embedding": embedding,
+            "embedding_truncated": truncated,
+            **await summary_fields(file_content, summary),
         }
 
         async with httpx.AsyncClient() as client:
@@ -33,8 +85,14 @@ async def upload_file_service(file: UploadFile = File(...)):
                 f"{GO_BACKEND_URL}/files/upload", json=payload
             )
             resp.raise_for_status()
-            return resp.json()
-
+            result = resp.json()
+            result["embedding_truncated"] = truncated
+            return result
+
+    except ContentTooLongError as e:
+        raise HTTPException(status_code=400, detail=str(e))
+    except HTTPException:
+        raise
     except httpx.RequestError as e:
         raise HTTPException(
             status_code=502, detail=f"Failed to reach backend: {str(e)}"
@@ -115,11 +173,16 @@ async def get_soft_deleted_files_service():
         )
 
 
-async def update_file_service(file_id: str, file: UploadFile):
+async def update_file_service(
+    file_id: str, file: UploadFile, summary: str | None = None
+):
     try:
         if file.filename is None:
             raise HTTPException(400, "File must have a filename")
 
+        file.filename = with_inferred_extension(
+            file.filename, await detect_content_type(file)
+        )
         ext = Path(file.filename).suffix.lower()
 
         if ext not in ALLOWED:
@@ -128,12 +191,15 @@ async def update_file_service(file_id: str, file: UploadFile):
             )
 
         file_content = await to_text(file)
-        embedding = await embed_text(file_content)
+        embed_input, truncated = prepare_embedding_text(file_content)
+        embedding = await embed_text(embed_input)
 
         payload = {
             "filename": file.filename,
             "content": file_content,
             "embedding": embedding,
+            "embedding_truncated": truncated,
+            **await summary_fields(file_content, summary),
         }
 
         async with httpx.AsyncClient() as client:
@@ -141,11 +207,18 @@ async def update_file_service(file_id: str, file: UploadFile):
                 f"{GO_BACKEND_URL}/files/{file_id}", json=payload
             )
             if resp.status_code == 200:
-                return {"message": "File updated successfully."}
+                return {
+                    "message": "File updated successfully.",
+                    "embedding_truncated": truncated,
+                }
             raise HTTPException(
                 status_code=resp.status_code, detail="Failed to update file."
             )
 
+    except ContentTooLongError as e:
+        raise HTTPException(status_code=400, detail=str(e))
+    except HTTPException:
+        raise
     except httpx.RequestError as e:
         raise HTTPException(
             status_code=502, detail=f"Connection error: {str(e)}"
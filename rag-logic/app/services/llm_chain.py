@@ -33,6 +33,30 @@ prompt = PromptTemplate.from_template(qa_template)
 # qa_chain = LLMChain(llm=llm, prompt=prompt)
 chain = prompt | llm
 
+# 2b. Prompt for query expansion
+expansion_template = """Rewrite the search query below as {count} alternative \
+phrasings that keep its meaning but vary the wording. Return one phrasing per \
+line with no numbering or extra text.
+
+Query: {question}
+
+Phrasings:"""
+
+expansion_prompt = PromptTemplate.from_template(expansion_template)
+expansion_chain = expansion_prompt | llm
+
+# 2c. Prompt for document summaries
+summary_template = """Summarize the document below in at most three \
+sentences. State its subject and main points plainly, with no preamble.
+
+Document:
+{content}
+
+Summary:"""
+
+summary_prompt = PromptTemplate.from_template(summary_template)
+summary_chain = summary_prompt | llm
+
 
 # 3. Optional tool
 def dummy_tool(input: str) -> str:
@@ -1,9 +1,21 @@
+import logging
+import re
+
 from sqlalchemy import text
 from sqlalchemy.orm import Session
 
-from app.models.schemas import FileData
+from app.models.schemas import DEFAULT_PREVIEW_LENGTH, FileData, SourceTrace
 from app.services.embedding import get_embedding
-from app.services.llm_chain import chain
+from app.services.llm_chain import chain, expansion_chain
+
+logger = logging.getLogger(__name__)
+
+EXPANSION_COUNT = 3
+# Standard reciprocal rank fusion damping constant.
+RRF_K = 60
+INSUFFICIENT_CONTEXT_ANSWER = (
+    "I don't have enough relevant context to answer that question."
+)
 
 
 async def fetch_similar_files_pgvector(
@@ -37,10 +49,10 @@ async def fetch_similar_files_pgvector(
 
     query = text(
         """
-        SELECT filename, content,
+        SELECT id, filename, content,
                embedding <=> CAST(:embedding AS vector) AS similarity
         FROM files
-        ORDER BY embedding <=> CAST(:embedding AS vector)
+        ORDER BY embedding <=> CAST(:embedding AS vector), created_at, id
         LIMIT :top_k;
     """
     )
@@ -50,18 +62,121 @@ async def fetch_similar_files_pgvector(
     rows = result.fetchall()
 
     return [
-        FileData(filename=row[0], content=row[1], similarity=row[2])
+        FileData(
+            id=str(row[0]), filename=row[1], content=row[2], similarity=row[3]
+        )
         for row in rows
     ]
 
 
+def normalize_cosine_distance(distance: float) -> float:
+    """Map a pgvector cosine distance to a 0-1 relevance, higher is closer.
+
+    This is (1 + cosine similarity) / 2, the same mapping the Go API uses
+    for ?normalize_scores=true with the cosine metric, so scores agree
+    across services and stay on the 0-1 scale min_relevance accepts.
+    """
+    return min(max(1 - distance / 2, 0.0), 1.0)
+
+
+def relevance(f: FileData) -> float:
+    """Normalized relevance of a retrieved file; f.similarity is the raw
+    cosine distance."""
+    return normalize_cosine_distance(f.similarity)
+
+
+def build_sources(
+    files: list[FileData], preview_length: int = DEFAULT_PREVIEW_LENGTH
+) -> list[SourceTrace]:
+    """Describe each retrieved chunk so answers can be audited.
+
+    Files arrive ordered by cosine distance, so rank is their 1-based
+    position and score is the normalized relevance, (1 + cosine
+    similarity) / 2.
+    """
+    return [
+        SourceTrace(
+            document_id=f.id,
+            filename=f.filename,
+            rank=rank,
+            score=relevance(f),
+            preview=f.content[:preview_length],
+        )
+        for rank, f in enumerate(files, start=1)
+    ]
+
+
+def expand_query(prompt: str, count: int = EXPANSION_COUNT) -> list[str]:
+    """Ask the LLM for paraphrases of the prompt.
+
+    Any LLM failure yields no expansions so search falls back to the
+    original prompt alone.
+    """
+    try:
+        raw = expansion_chain.invoke({"question": prompt, "count": count})
+    except Exception:
+        logger.warning("query expansion failed; using original prompt only")
+        return []
+
+    expansions: list[str] = []
+    for line in str(raw).splitlines():
+        phrasing = re.sub(r"^\s*(?:[-*]|\d+[.)])\s*", "", line).strip()
+        if phrasing and phrasing != prompt and phrasing not in expansions:
+            expansions.append(phrasing)
+    return expansions[:count]
+
+
+def fuse_results(
+    result_lists: list[list[FileData]], top_k: int = 5
+) -> list[FileData]:
+    """Merge ranked result lists with reciprocal rank fusion.
+
+    A file found by several queries keeps its closest distance.
+    """
+    scores: dict[str, float] = {}
+    best: dict[str, FileData] = {}
+    for files in result_lists:
+        for rank, f in enumerate(files, start=1):
+            scores[f.id] = scores.get(f.id, 0.0) + 1 / (RRF_K + rank)
+            if f.id not in best or f.similarity < best[f.id].similarity:
+                best[f.id] = f
+
+    ordered = sorted(scores, key=lambda fid: scores[fid], reverse=True)
+    return [best[file_id] for file_id in ordered[:top_k]]
+
+
 async def run_query_pipeline(
-    prompt: str, db: Session
-) -> tuple[list[FileData], str]:
-    embedding = await get_embedding(prompt)
-    files = await fetch_similar_files_pgvector(embedding, db)
+    prompt: str,
+    db: Session,
+    preview_length: int = DEFAULT_PREVIEW_LENGTH,
+    expand: bool = False,
+    min_relevance: float | None = None,
+) -> tuple[list[FileData], list[SourceTrace], str]:
+    """Retrieve context for the prompt and answer it with the LLM.
+
+    When min_relevance is set, files scoring below it are dropped; if none
+    remain the LLM is skipped and an insufficient-context answer is
+    returned with no matches.
+    """
+    queries = [prompt]
+    if expand:
+        queries += expand_query(prompt)
+
+    result_lists = []
+    for query in queries:
+        embedding = await get_embedding(query)
+        result_lists.append(await fetch_similar_files_pgvector(embedding, db))
+    if len(result_lists) == 1:
+        files = result_lists[0]
+    else:
+        files = fuse_results(result_lists)
+
+    if min_relevance is not None:
+        files = [f for f in files if relevance(f) >= min_relevance]
+        if not files:
+            return [], [], INSUFFICIENT_CONTEXT_ANSWER
 
     context = "\n\n".join(f"{f.filename}:\n{f.content}" for f in files)
     answer = chain.invoke({"context": context, "question": prompt})
 
-    return files, answer
+    return files, build_sources(files, preview_length), answer
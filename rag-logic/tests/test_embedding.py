@@ -0,0 +1,219 @@
+import io
+
+import pytest
+from fastapi import HTTPException, UploadFile
+
+from app.services import embedding, file_operations
+
+
+def test_prepare_embedding_text_within_limit():
+    """Short content is embedded as-is"""
+    text, truncated = embedding.prepare_embedding_text(
+        "short", max_length=10, unit="chars", strict=False
+    )
+    assert text == "short"
+    assert truncated is False
+
+
+def test_prepare_embedding_text_disabled():
+    """A limit of 0 never trims"""
+    text, truncated = embedding.prepare_embedding_text(
+        "x" * 10_000, max_length=0, unit="chars", strict=True
+    )
+    assert len(text) == 10_000
+    assert truncated is False
+
+
+def test_prepare_embedding_text_trims_chars(caplog):
+    """Over-length content is trimmed with a logged warning"""
+    text, truncated = embedding.prepare_embedding_text(
+        "abcdefghij", max_length=4, unit="chars", strict=False
+    )
+    assert text == "abcd"
+    assert truncated is True
+    assert "trimming content" in caplog.text
+
+
+def test_prepare_embedding_text_trims_estimated_tokens():
+    """Token limits are converted to characters using the estimate"""
+    text, truncated = embedding.prepare_embedding_text(
+        "a" * 100, max_length=5, unit="tokens", strict=False
+    )
+    assert len(text) == 5 * embedding.CHARS_PER_TOKEN
+    assert truncated is True
+
+
+def test_validate_length_unit():
+    """Only chars and tokens are accepted; others fail before any request"""
+    assert embedding.validate_length_unit("tokens") == "tokens"
+    with pytest.raises(ValueError):
+        embedding.validate_length_unit("words")
+    with pytest.raises(ValueError):
+        embedding.prepare_embedding_text("abc", max_length=1, unit="words")
+
+
+def test_prepare_embedding_text_strict_rejects():
+    """Strict mode rejects over-length content instead of trimming"""
+    with pytest.raises(embedding.ContentTooLongError):
+        embedding.prepare_embedding_text(
+            "abcdefghij", max_length=4, unit="chars", strict=True
+        )
+
+
+class FakeResponse:
+    def __init__(self, payload: dict):
+        self.payload = payload
+
+    def raise_for_status(self) -> None:
+        pass
+
+    def json(self) -> dict:
+        return dict(self.payload)
+
+
+class FakeClient:
+    def __init__(self):
+        self.sent: list[dict] = []
+
+    async def __aenter__(self):
+        return self
+
+    async def __aexit__(self, *exc):
+        return False
+
+    async def post(self, url: str, json: dict) -> FakeResponse:
+        self.sent.append(json)
+        return FakeResponse({"ID": "1"})
+
+
+def make_upload(text: str) -> UploadFile:
+    return UploadFile(filename="notes.txt", file=io.BytesIO(text.encode()))
+
+
+@pytest.mark.asyncio
+async def test_upload_stores_full_content_but_embeds_trimmed(monkeypatch):
+    """Upload embeds the trimmed text, stores the full text, and reports it"""
+    client = FakeClient()
+    embedded: list[str] = []
+
+    async def fake_embed(text: str) -> list[float]:
+        embedded.append(text)
+        return [0.1]
+
+    async def fake_to_text(file: UploadFile) -> str:
+        return "abcdefghij"
+
+    def trim_to_four(text: str) -> tuple[str, bool]:
+        return embedding.prepare_embedding_text(
+            text, max_length=4, unit="chars", strict=False
+        )
+
+    monkeypatch.setattr(file_operations, "embed_text", fake_embed)
+    monkeypatch.setattr(file_operations, "to_text", fake_to_text)
+    monkeypatch.setattr(
+        file_operations, "prepare_embedding_text", trim_to_four
+    )
+    monkeypatch.setattr(file_operations.httpx, "AsyncClient", lambda: client)
+    monkeypatch.setattr(file_operations, "generate_summary", lambda c: None)
+
+    result = await file_operations.upload_file_service(make_upload(""))
+
+    assert embedded == ["abcd"]
+    assert client.sent[0]["content"] == "abcdefghij"
+    assert client.sent[0]["embedding_truncated"] is True
+    assert result["embedding_truncated"] is True
+
+
+@pytest.mark.asyncio
+async def test_upload_strict_mode_rejects_with_400(monkeypatch):
+    """Strict mode turns over-length uploads into a 400"""
+
+    async def fake_to_text(file: UploadFile) -> str:
+        return "abcdefghij"
+
+    def strict_limit(text: str) -> tuple[str, bool]:
+        return embedding.prepare_embedding_text(
+            text, max_length=4, unit="chars", strict=True
+        )
+
+    monkeypatch.setattr(file_operations, "to_text", fake_to_text)
+    monkeypatch.setattr(
+        file_operations, "prepare_embedding_text", strict_limit
+    )
+
+    with pytest.raises(HTTPException) as exc:
+        await file_operations.upload_file_service(make_upload(""))
+    assert exc.value.status_code == 400
+
+
+class StubSummaryChain:
+    def __init__(self, summary: str = "", error: Exception | None = None):
+        self.summary = summary
+        self.error = error
+        self.inputs: list[dict] = []
+
+    def invoke(self, inputs: dict) -> str:
+        self.inputs.append(inputs)
+        if self.error is not None:
+            raise self.error
+        return self.summary
+
+
+@pytest.mark.asyncio
+async def test_upload_generates_and_embeds_summary(monkeypatch):
+    """A missing summary is written by the LLM and embedded separately"""
+    client = FakeClient()
+    embedded: list[str] = []
+    chain = StubSummaryChain("  A report on revenue.\n")
+
+    async def fake_embed(text: str) -> list[float]:
+        embedded.append(text)
+        return [float(len(embedded))]
+
+    async def fake_to_text(file: UploadFile) -> str:
+        return "the full report"
+
+    monkeypatch.setattr(file_operations, "embed_text", fake_embed)
+    monkeypatch.setattr(file_operations, "to_text", fake_to_text)
+    monkeypatch.setattr(file_operations, "summary_chain", chain)
+    monkeypatch.setattr(file_operations.httpx, "AsyncClient", lambda: client)
+
+    await file_operations.upload_file_service(make_upload(""))
+
+    assert chain.inputs == [{"content": "the full report"}]
+    assert embedded == ["the full report", "A report on revenue."]
+    assert client.sent[0]["summary"] == "A report on revenue."
+    assert client.sent[0]["summary_embedding"] == [2.0]
+    assert client.sent[0]["embedding"] == [1.0]
+
+
+@pytest.mark.asyncio
+async def test_summary_fields_prefers_supplied_summary(monkeypatch):
+    """A supplied summary is embedded without asking the LLM"""
+    chain = StubSummaryChain("generated")
+
+    async def fake_embed(text: str) -> list[float]:
+        return [0.5]
+
+    monkeypatch.setattr(file_operations, "embed_text", fake_embed)
+    monkeypatch.setattr(file_operations, "summary_chain", chain)
+
+    fields = await file_operations.summary_fields("content", " mine ")
+
+    assert fields == {"summary": "mine", "summary_embedding": [0.5]}
+    assert chain.inputs == []
+
+
+@pytest.mark.asyncio
+async def test_summary_fields_empty_when_llm_fails(monkeypatch, caplog):
+    """An LLM failure stores the file without a summary"""
+    chain = StubSummaryChain(error=RuntimeError("ollama unreachable"))
+    monkeypatch.setattr(file_operations, "summary_chain", chain)
+
+    fields = await file_operations.summary_fields("x" * 10_000, None)
+
+    assert fields == {}
+    assert "summary generation failed" in caplog.text
+    assert len(chain.inputs[0]["content"]) == (
+        file_operations.SUMMARY_INPUT_CHARS
+    )
@@ -0,0 +1,116 @@
+import io
+
+import pytest
+from fastapi import UploadFile
+from starlette.datastructures import Headers
+
+from app.helpers.file_format_convert import (
+    infer_extension,
+    resolve_content_type,
+    with_inferred_extension,
+)
+from app.services import file_operations
+
+
+def test_infer_extension_maps_supported_types():
+    """Known MIME types map to their extension, ignoring parameters"""
+    assert infer_extension("application/pdf") == ".pdf"
+    assert infer_extension("text/plain; charset=utf-8") == ".txt"
+    assert infer_extension("text/markdown") == ".md"
+    assert infer_extension("image/png") is None
+    assert infer_extension(None) is None
+
+
+def test_with_inferred_extension():
+    """Only bare filenames get an inferred extension"""
+    assert with_inferred_extension("report", "application/pdf") == "report.pdf"
+    assert with_inferred_extension("notes.md", "text/plain") == "notes.md"
+    assert with_inferred_extension("blob", "image/png") == "blob"
+
+
+def test_resolve_content_type_prefers_sniffed_type():
+    """Detected content wins over a wrong label; the label is a fallback"""
+    assert resolve_content_type(b"%PDF-1.4", "text/plain") == "application/pdf"
+    assert resolve_content_type(b"plain words", "application/pdf") == (
+        "text/plain"
+    )
+    assert resolve_content_type(b"# Title", "text/markdown") == "text/markdown"
+    assert resolve_content_type(b"\x89PNG\x00", "image/png") == "image/png"
+    assert resolve_content_type(b"", None) is None
+
+
+class FakeResponse:
+    def raise_for_status(self) -> None:
+        pass
+
+    def json(self) -> dict:
+        return {}
+
+
+class FakeClient:
+    def __init__(self):
+        self.sent: list[dict] = []
+
+    async def __aenter__(self):
+        return self
+
+    async def __aexit__(self, *exc):
+        return False
+
+    async def post(self, url: str, json: dict) -> FakeResponse:
+        self.sent.append(json)
+        return FakeResponse()
+
+
+@pytest.mark.asyncio
+async def test_upload_bare_pdf_name_gets_pdf_extension(monkeypatch):
+    """A PDF uploaded as 'report' is stored as 'report.pdf'"""
+    client = FakeClient()
+    seen: list[str] = []
+
+    async def fake_to_text(file: UploadFile) -> str:
+        seen.append(file.filename or "")
+        return "quarterly report"
+
+    async def fake_embed(text: str) -> list[float]:
+        return [0.1]
+
+    monkeypatch.setattr(file_operations, "to_text", fake_to_text)
+    monkeypatch.setattr(file_operations, "embed_text", fake_embed)
+    monkeypatch.setattr(file_operations.httpx, "AsyncClient", lambda: client)
+
+    upload = UploadFile(
+        file=io.BytesIO(b"%PDF-1.4"),
+        filename="report",
+        headers=Headers({"content-type": "application/pdf"}),
+    )
+    await file_operations.upload_file_service(upload)
+
+    assert seen == ["report.pdf"]
+    assert client.sent[0]["filename"] == "report.pdf"
+
+
+@pytest.mark.asyncio
+async def test_upload_mislabeled_pdf_gets_pdf_extension(monkeypatch):
+    """A PDF declared as text/plain is still stored with .pdf"""
+    client = FakeClient()
+
+    async def fake_to_text(file: UploadFile) -> str:
+        assert await file.read() == b"%PDF-1.4", "the sniff must rewind"
+        return "quarterly report"
+
+    async def fake_embed(text: str) -> list[float]:
+        return [0.1]
+
+    monkeypatch.setattr(file_operations, "to_text", fake_to_text)
+    monkeypatch.setattr(file_operations, "embed_text", fake_embed)
+    monkeypatch.setattr(file_operations.httpx, "AsyncClient", lambda: client)
+
+    upload = UploadFile(
+        file=io.BytesIO(b"%PDF-1.4"),
+        filename="report",
+        headers=Headers({"content-type": "text/plain"}),
+    )
+    await file_operations.upload_file_service(upload)
+
+    assert client.sent[0]["filename"] == "report.pdf"
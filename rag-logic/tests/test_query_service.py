@@ -0,0 +1,252 @@
+import pytest
+from fastapi.testclient import TestClient
+
+from app.db.session import get_db_session
+from app.main import app
+from app.models.schemas import FileData
+from app.routes import file_routes
+from app.services import query_service
+
+
+class StubChain:
+    def __init__(self, answer: str):
+        self.answer = answer
+        self.inputs: list[dict] = []
+
+    def invoke(self, inputs: dict) -> str:
+        self.inputs.append(inputs)
+        return self.answer
+
+
+def make_files() -> list[FileData]:
+    return [
+        FileData(
+            id="a1", filename="a.txt", content="alpha " * 50, similarity=0.1
+        ),
+        FileData(id="b2", filename="b.txt", content="beta", similarity=0.25),
+        FileData(id="c3", filename="c.txt", content="gamma", similarity=0.4),
+    ]
+
+
+def test_build_sources_ranks_and_scores():
+    """Sources keep retrieval order with 1-based ranks and similarities"""
+    sources = query_service.build_sources(make_files(), preview_length=10)
+
+    assert [s.rank for s in sources] == [1, 2, 3]
+    assert [s.document_id for s in sources] == ["a1", "b2", "c3"]
+    assert sources[0].score == pytest.approx(0.95)
+    assert sources[2].score == pytest.approx(0.8)
+    assert [s.score for s in sources] == sorted(
+        (s.score for s in sources), reverse=True
+    )
+    assert sources[0].preview == "alpha alph"
+    assert sources[1].preview == "beta"
+
+
+@pytest.mark.asyncio
+async def test_run_query_pipeline_returns_sources(monkeypatch):
+    """The pipeline returns ranked, scored sources alongside the answer"""
+
+    async def fake_embedding(prompt: str) -> list[float]:
+        return [0.0, 1.0]
+
+    async def fake_fetch(embedding, db, top_k=5):
+        return make_files()
+
+    stub = StubChain("grounded answer")
+    monkeypatch.setattr(query_service, "get_embedding", fake_embedding)
+    monkeypatch.setattr(
+        query_service, "fetch_similar_files_pgvector", fake_fetch
+    )
+    monkeypatch.setattr(query_service, "chain", stub)
+
+    files, sources, answer = await query_service.run_query_pipeline(
+        "what is alpha?", db=None, preview_length=5
+    )
+
+    assert answer == "grounded answer"
+    assert len(files) == 3
+    assert len(sources) == 3
+    for rank, source in enumerate(sources, start=1):
+        assert source.rank == rank
+        assert 0 <= source.score <= 1
+        assert len(source.preview) <= 5
+    assert "a.txt" in stub.inputs[0]["context"]
+
+
+class FailingChain:
+    def invoke(self, inputs: dict) -> str:
+        raise ConnectionError("LLM unavailable")
+
+
+def test_expand_query_parses_stub_expansions(monkeypatch):
+    """Expansions are cleaned of list markers, deduplicated, and capped"""
+    stub = StubChain("1. alpha meaning\n- define alpha\n\nalpha meaning\nx\ny")
+    monkeypatch.setattr(query_service, "expansion_chain", stub)
+
+    expansions = query_service.expand_query("what is alpha?", count=3)
+
+    assert expansions == ["alpha meaning", "define alpha", "x"]
+    assert stub.inputs[0] == {"question": "what is alpha?", "count": 3}
+
+
+def test_expand_query_degrades_when_llm_unavailable(monkeypatch):
+    """An LLM failure yields no expansions instead of an error"""
+    monkeypatch.setattr(query_service, "expansion_chain", FailingChain())
+
+    assert query_service.expand_query("what is alpha?") == []
+
+
+def test_fuse_results_rewards_agreement():
+    """Files found by several queries rank first and keep their best score"""
+    a, b, c = make_files()
+    closer_b = b.model_copy(update={"similarity": 0.05})
+
+    fused = query_service.fuse_results([[a, b], [closer_b, c], [b]])
+
+    assert [f.id for f in fused] == ["b2", "a1", "c3"]
+    assert fused[0].similarity == pytest.approx(0.05)
+
+
+@pytest.mark.asyncio
+async def test_run_query_pipeline_with_expansion(monkeypatch):
+    """Each expansion is embedded and searched, and the results fused"""
+    a, b, c = make_files()
+    by_query = {"what is alpha?": [a], "alpha meaning": [b, a]}
+    embedded: list[str] = []
+
+    async def fake_embedding(prompt: str) -> list[float]:
+        embedded.append(prompt)
+        return [float(len(embedded))]
+
+    async def fake_fetch(embedding, db, top_k=5):
+        return by_query[embedded[int(embedding[0]) - 1]]
+
+    monkeypatch.setattr(query_service, "get_embedding", fake_embedding)
+    monkeypatch.setattr(
+        query_service, "fetch_similar_files_pgvector", fake_fetch
+    )
+    monkeypatch.setattr(
+        query_service, "expansion_chain", StubChain("alpha meaning")
+    )
+    monkeypatch.setattr(query_service, "chain", StubChain("answer"))
+
+    files, sources, _ = await query_service.run_query_pipeline(
+        "what is alpha?", db=None, expand=True
+    )
+
+    assert embedded == ["what is alpha?", "alpha meaning"]
+    assert [f.id for f in files] == ["a1", "b2"]
+    assert [s.rank for s in sources] == [1, 2]
+
+
+@pytest.mark.asyncio
+async def test_run_query_pipeline_expansion_falls_back(monkeypatch):
+    """With the LLM down, expansion falls back to a single search"""
+    embedded: list[str] = []
+
+    async def fake_embedding(prompt: str) -> list[float]:
+        embedded.append(prompt)
+        return [0.0]
+
+    async def fake_fetch(embedding, db, top_k=5):
+        return make_files()
+
+    monkeypatch.setattr(query_service, "get_embedding", fake_embedding)
+    monkeypatch.setattr(
+        query_service, "fetch_similar_files_pgvector", fake_fetch
+    )
+    monkeypatch.setattr(query_service, "expansion_chain", FailingChain())
+    monkeypatch.setattr(query_service, "chain", StubChain("answer"))
+
+    files, _, _ = await query_service.run_query_pipeline(
+        "what is alpha?", db=None, expand=True
+    )
+
+    assert embedded == ["what is alpha?"]
+    assert len(files) == 3
+
+
+@pytest.mark.parametrize(
+    "distance, expected",
+    [(0.0, 1.0), (1.0, 0.5), (1.5, 0.25), (2.0, 0.0), (2.0000001, 0.0)],
+)
+def test_normalize_cosine_distance(distance, expected):
+    """Every cosine distance, including negative similarity, maps to 0-1"""
+    assert query_service.normalize_cosine_distance(
+        distance
+    ) == pytest.approx(expected)
+
+
+@pytest.mark.asyncio
+async def test_run_query_pipeline_min_relevance_filters(monkeypatch):
+    """Files scoring below min_relevance are left out of the context"""
+
+    async def fake_embedding(prompt: str) -> list[float]:
+        return [0.0, 1.0]
+
+    async def fake_fetch(embedding, db, top_k=5):
+        return make_files()
+
+    stub = StubChain("grounded answer")
+    monkeypatch.setattr(query_service, "get_embedding", fake_embedding)
+    monkeypatch.setattr(
+        query_service, "fetch_similar_files_pgvector", fake_fetch
+    )
+    monkeypatch.setattr(query_service, "chain", stub)
+
+    files, sources, _ = await query_service.run_query_pipeline(
+        "what is alpha?", db=None, min_relevance=0.85
+    )
+
+    assert [f.id for f in files] == ["a1", "b2"]
+    assert [s.document_id for s in sources] == ["a1", "b2"]
+    assert "c.txt" not in stub.inputs[0]["context"]
+
+
+@pytest.mark.asyncio
+async def test_run_query_pipeline_insufficient_context(monkeypatch):
+    """When no file passes min_relevance the LLM is not consulted"""
+
+    async def fake_embedding(prompt: str) -> list[float]:
+        return [0.0, 1.0]
+
+    async def fake_fetch(embedding, db, top_k=5):
+        return make_files()
+
+    stub = StubChain("ungrounded answer")
+    monkeypatch.setattr(query_service, "get_embedding", fake_embedding)
+    monkeypatch.setattr(
+        query_service, "fetch_similar_files_pgvector", fake_fetch
+    )
+    monkeypatch.setattr(query_service, "chain", stub)
+
+    files, sources, answer = await query_service.run_query_pipeline(
+        "what is alpha?", db=None, min_relevance=0.96
+    )
+
+    assert files == []
+    assert sources == []
+    assert answer == query_service.INSUFFICIENT_CONTEXT_ANSWER
+    assert stub.inputs == []
+
+
+def test_query_route_reads_expand_from_query_string(monkeypatch):
+    """expand is the ?expand= query parameter, not a body field"""
+    seen: list[bool] = []
+
+    async def fake_pipeline(prompt, db, preview_length, expand, min_relevance):
+        seen.append(expand)
+        return [], [], "answer"
+
+    monkeypatch.setattr(file_routes, "run_query_pipeline", fake_pipeline)
+    app.dependency_overrides[get_db_session] = lambda: None
+    try:
+        client = TestClient(app)
+        body = {"prompt": "what is alpha?"}
+        assert client.post("/file/query?expand=true", json=body).ok
+        assert client.post("/file/query", json=body).ok
+    finally:
+        app.dependency_overrides.clear()
+
+    assert seen == [True, False]